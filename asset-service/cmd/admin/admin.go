@@ -0,0 +1,135 @@
+package main
+
+import (
+	"asset-service/internal/config"
+	"asset-service/internal/database"
+	"asset-service/internal/repository"
+	"asset-service/internal/services"
+	"context"
+	"fmt"
+	"io"
+	"shared/pkg/apperror"
+	"shared/pkg/cli"
+	"shared/pkg/tenant"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// admin holds the services the commands run through. They are built on first
+// use, so printing the usage needs no database.
+type admin struct {
+	out io.Writer
+
+	db       *gorm.DB
+	folders  services.FolderService
+	sharings services.SharingService
+}
+
+func (a *admin) commands() []cli.Command {
+	return []cli.Command{
+		{Name: "asset transfer-ownership", Usage: "--org <org id> --from <user id> --to <user id>", Summary: "give all of a user's folders and notes to another user", Run: a.transferOwnership},
+		{Name: "asset revoke-all-shares", Usage: "--org <org id> --user <user id>", Summary: "revoke every folder and note shared with a user", Run: a.revokeAllShares},
+	}
+}
+
+func (a *admin) connect() error {
+	if a.db != nil {
+		return nil
+	}
+
+	db, err := database.Connect(*config.LoadDB())
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	a.db = db
+
+	folderRepo := repository.NewFolderRepository(db)
+	a.folders = services.NewFolderService(folderRepo)
+	a.sharings = services.NewSharingService(repository.NewSharingRepository(db), folderRepo, repository.NewNoteRepository(db))
+	return nil
+}
+
+// close closes the database. It is safe to call more than once.
+func (a *admin) close() {
+	if a.db != nil {
+		if sqlDB, err := a.db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		a.db = nil
+	}
+}
+
+func (a *admin) transferOwnership(ctx context.Context, args []string) error {
+	fs := cli.Flags("asset transfer-ownership", a.out)
+	orgFlag := fs.String("org", "", "ID of the organisation both users belong to")
+	fromFlag := fs.String("from", "", "ID of the current owner")
+	toFlag := fs.String("to", "", "ID of the new owner")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := cli.Required(fs, "org", "from", "to"); err != nil {
+		return err
+	}
+	org, err := parseID(*orgFlag, "organisation")
+	if err != nil {
+		return err
+	}
+	from, err := parseID(*fromFlag, "user")
+	if err != nil {
+		return err
+	}
+	to, err := parseID(*toFlag, "user")
+	if err != nil {
+		return err
+	}
+	if err := a.connect(); err != nil {
+		return err
+	}
+
+	// Only the organisation's assets move, so none can leave it
+	moved, err := a.folders.TransferOwnership(tenant.WithOrg(ctx, org), from, to)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "transferred %d folders from %s to %s\n", moved, from, to)
+	return nil
+}
+
+func (a *admin) revokeAllShares(ctx context.Context, args []string) error {
+	fs := cli.Flags("asset revoke-all-shares", a.out)
+	orgFlag := fs.String("org", "", "ID of the user's organisation")
+	userFlag := fs.String("user", "", "ID of the user losing access")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := cli.Required(fs, "org", "user"); err != nil {
+		return err
+	}
+	org, err := parseID(*orgFlag, "organisation")
+	if err != nil {
+		return err
+	}
+	user, err := parseID(*userFlag, "user")
+	if err != nil {
+		return err
+	}
+	if err := a.connect(); err != nil {
+		return err
+	}
+
+	folders, notes, err := a.sharings.RevokeAllSharings(tenant.WithOrg(ctx, org), user)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "revoked %d folder and %d note sharings of %s\n", folders, notes, user)
+	return nil
+}
+
+func parseID(s, what string) (uuid.UUID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, apperror.BadRequest("invalid %s ID %q", what, s)
+	}
+	return id, nil
+}
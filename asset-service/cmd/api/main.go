@@ -1,7 +1,8 @@
 package main
 
 import (
-	"log"
+	"context"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,24 +12,50 @@ import (
 	"asset-service/internal/config"
 	"asset-service/internal/database"
 	httpserver "asset-service/internal/http"
+	"asset-service/internal/jobs"
 	"asset-service/internal/repository"
 	"asset-service/internal/services"
+	"asset-service/internal/storage"
+	"shared/pkg/health"
+	"shared/pkg/lifecycle"
+	"shared/pkg/log"
+	"shared/pkg/tracing"
 )
 
 func main() {
+	log.Setup("asset-service")
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, "asset-service")
+	if err != nil {
+		log.Fatal("failed to set up tracing", "error", err)
+	}
+
 	dbCfg := config.LoadDB()
 	srvCfg := config.LoadServerConfig()
+	trashCfg := config.LoadTrashConfig()
+	storageCfg := config.LoadStorageConfig()
+	importCfg := config.LoadImportConfig()
+	healthCfg := health.LoadConfig()
 
 	db, err := database.Connect(*dbCfg)
 	if err != nil {
-		log.Fatalf("❌ Failed to connect to database: %v", err)
+		log.Fatal("failed to connect to database", "error", err)
 	}
-	log.Println("✅ Connected to database")
+	log.Info(ctx, "connected to database")
 
-	if err := database.Migrate(db); err != nil {
-		log.Fatalf("❌ Migration failed: %v", err)
+	if dbCfg.MigrateOnStart {
+		if err := database.Migrate(ctx, db); err != nil {
+			log.Fatal("migration failed", "error", err)
+		}
+		log.Info(ctx, "migrations completed")
 	}
-	log.Println("✅ Migrations completed")
 
 	folderRepo := repository.NewFolderRepository(db)
 	folderSvc := services.NewFolderService(folderRepo)
@@ -39,10 +66,49 @@ func main() {
 	sharingRepo := repository.NewSharingRepository(db)
 	sharingSvc := services.NewSharingService(sharingRepo, folderRepo, noteRepo)
 
+	tagRepo := repository.NewTagRepository(db)
+	tagSvc := services.NewTagService(tagRepo, noteRepo, folderRepo)
+
+	exportSvc := services.NewExportService(noteRepo, folderRepo)
+	importSvc := services.NewImportService(noteSvc, folderRepo, repository.NewTransactor(db), services.ImportLimits{
+		MaxFiles: importCfg.MaxFiles,
+	})
+
+	blobs, err := newBlobStore(storageCfg)
+	if err != nil {
+		log.Fatal("failed to initialise blob storage", "error", err)
+	}
+	log.Info(ctx, "blob storage ready", "driver", storageCfg.Driver)
+
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	attachmentSvc := services.NewAttachmentService(attachmentRepo, noteRepo, folderRepo, blobs, services.AttachmentLimits{
+		MaxBytes:     storageCfg.AttachmentMaxBytes,
+		AllowedTypes: storageCfg.AttachmentAllowedTypes,
+	})
+
+	trashRepo := repository.NewTrashRepository(db)
+	trashSvc := services.NewTrashService(trashRepo, attachmentRepo, blobs)
+
+	probes := health.New(healthCfg.CheckTimeout)
+	probes.Register("postgres", health.DB(db))
+	if healthCfg.RedisAddr != "" {
+		probes.Register("redis", health.Redis(healthCfg.RedisAddr, healthCfg.RedisPassword))
+	}
+
 	engine := httpserver.NewRouter(httpserver.RouterDeps{
 		FolderService:  folderSvc,
 		NoteService:    noteSvc,
 		SharingService: sharingSvc,
+		TrashService:   trashSvc,
+		TagService:     tagSvc,
+		ExportService:  exportSvc,
+		ImportService:  importSvc,
+
+		AttachmentService:  attachmentSvc,
+		AttachmentMaxBytes: storageCfg.AttachmentMaxBytes,
+		ImportMaxBytes:     importCfg.MaxBytes,
+
+		Health: probes,
 	})
 
 	srv := &http.Server{
@@ -54,16 +120,47 @@ func main() {
 		MaxHeaderBytes: 1 << 20,
 	}
 
-	go func() {
-		log.Printf("HTTP server listening on :%s", srvCfg.Port)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("server error: %v", err)
-		}
-	}()
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatal("failed to get database pool", "error", err)
+	}
+	purger := jobs.NewTrashPurger(trashSvc, trashCfg)
+
+	// Components stop in reverse: readiness fails first, then the server
+	// drains, and the pool and tracer go last once nothing uses them.
+	lc := lifecycle.New(srvCfg.ShutdownTimeout)
+	lc.Add(
+		lifecycle.Hook("tracing", nil, shutdownTracing),
+		lifecycle.Closer("postgres", sqlDB.Close),
+		lifecycle.Runner("trash purger", func(ctx context.Context) error {
+			purger.Run(ctx)
+			return nil
+		}),
+		lifecycle.HTTPServer(srv, srvCfg.ShutdownTimeout),
+		probes.Readiness(healthCfg.DrainDelay),
+	)
+	if err := lc.Run(ctx); err != nil {
+		log.Fatal("asset-service stopped with errors", "error", err)
+	}
+	log.Info(context.Background(), "shutdown complete")
+}
 
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
-	<-stop
-	log.Println("shutting down...")
-	_ = srv.Close()
+func newBlobStore(cfg config.StorageConfig) (storage.BlobStore, error) {
+	switch cfg.Driver {
+	case "local":
+		return storage.NewLocalStore(cfg.LocalDir)
+	case "s3":
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return storage.NewS3Store(ctx, storage.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			Bucket:    cfg.S3Bucket,
+			Region:    cfg.S3Region,
+			UseSSL:    cfg.S3UseSSL,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
 }
@@ -2,7 +2,7 @@ package config
 
 import (
 	"fmt"
-	"log"
+	"shared/pkg/log"
 	"shared/utils"
 	"strconv"
 
@@ -16,6 +16,10 @@ type DatabaseConfig struct {
 	DBName    string
 	DBPort    int
 	DBSSLMode string
+
+	// MigrateOnStart applies pending migrations when the service boots.
+	// Disable it to run `migrate up` as a separate deploy step instead.
+	MigrateOnStart bool
 }
 
 var cfg *DatabaseConfig
@@ -29,7 +33,7 @@ func LoadDB() *DatabaseConfig {
 
 	port, err := strconv.Atoi(utils.GetEnv("DB_PORT", "5432"))
 	if err != nil {
-		log.Fatalf("Invalid DB_PORT: %v", err)
+		log.Fatal("invalid DB_PORT", "error", err)
 	}
 
 	cfg = &DatabaseConfig{
@@ -39,6 +43,8 @@ func LoadDB() *DatabaseConfig {
 		DBName:    utils.GetEnv("DB_NAME", "postgres"),
 		DBPort:    port,
 		DBSSLMode: utils.GetEnv("DB_SSLMODE", "disable"),
+
+		MigrateOnStart: utils.AsBool("DB_MIGRATE_ON_START", true),
 	}
 
 	return cfg
@@ -0,0 +1,17 @@
+package config
+
+import "shared/utils"
+
+type ImportConfig struct {
+	// MaxBytes caps the size of a whole import request.
+	MaxBytes int64
+	// MaxFiles caps the number of Markdown files per import.
+	MaxFiles int
+}
+
+func LoadImportConfig() ImportConfig {
+	return ImportConfig{
+		MaxBytes: utils.AsInt64("IMPORT_MAX_BYTES", 50<<20), // 50MB
+		MaxFiles: utils.AsInt("IMPORT_MAX_FILES", 500),
+	}
+}
@@ -2,14 +2,19 @@ package config
 
 import (
 	"shared/utils"
+	"time"
 )
 
 type ServerConfig struct {
 	Port string
+	// ShutdownTimeout bounds how long in-flight requests get to finish, and
+	// each other component to stop, on shutdown.
+	ShutdownTimeout time.Duration
 }
 
 func LoadServerConfig() ServerConfig {
 	return ServerConfig{
-		Port: utils.GetEnv("SERVER_PORT", "7070"),
+		Port:            utils.GetEnv("SERVER_PORT", "7070"),
+		ShutdownTimeout: utils.AsDuration("SHUTDOWN_TIMEOUT", 10*time.Second),
 	}
 }
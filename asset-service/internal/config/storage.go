@@ -0,0 +1,41 @@
+package config
+
+import (
+	"shared/utils"
+	"strings"
+)
+
+type StorageConfig struct {
+	// Driver selects the blob store: "local" or "s3".
+	Driver   string
+	LocalDir string
+
+	S3Endpoint  string
+	S3AccessKey string
+	S3SecretKey string
+	S3Bucket    string
+	S3Region    string
+	S3UseSSL    bool
+
+	AttachmentMaxBytes     int64
+	AttachmentAllowedTypes []string
+}
+
+func LoadStorageConfig() StorageConfig {
+	return StorageConfig{
+		Driver:      utils.GetEnv("STORAGE_DRIVER", "local"),
+		LocalDir:    utils.GetEnv("STORAGE_LOCAL_DIR", "./data/attachments"),
+		S3Endpoint:  utils.GetEnv("S3_ENDPOINT", "localhost:9000"),
+		S3AccessKey: utils.GetEnv("S3_ACCESS_KEY", "minioadmin"),
+		S3SecretKey: utils.GetEnv("S3_SECRET_KEY", "minioadmin"),
+		S3Bucket:    utils.GetEnv("S3_BUCKET", "attachments"),
+		S3Region:    utils.GetEnv("S3_REGION", "us-east-1"),
+		S3UseSSL:    utils.GetEnv("S3_USE_SSL", "false") == "true",
+
+		AttachmentMaxBytes: utils.AsInt64("ATTACHMENT_MAX_BYTES", 25<<20), // 25MB
+		AttachmentAllowedTypes: strings.Split(utils.GetEnv(
+			"ATTACHMENT_ALLOWED_TYPES",
+			"application/pdf,image/png,image/jpeg,image/gif,image/webp,text/plain",
+		), ","),
+	}
+}
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"shared/utils"
+	"time"
+)
+
+type TrashConfig struct {
+	// Retention is how long trashed items are kept before being purged.
+	// A zero value disables the purge job.
+	Retention     time.Duration
+	PurgeInterval time.Duration
+}
+
+func LoadTrashConfig() TrashConfig {
+	return TrashConfig{
+		Retention:     utils.AsDuration("TRASH_RETENTION", 30*24*time.Hour),
+		PurgeInterval: utils.AsDuration("TRASH_PURGE_INTERVAL", time.Hour),
+	}
+}
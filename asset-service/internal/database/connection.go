@@ -2,6 +2,10 @@ package database
 
 import (
 	"asset-service/internal/config"
+	"shared/pkg/log"
+	"shared/pkg/metrics"
+	"shared/pkg/tenant"
+	"shared/pkg/tracing"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -10,10 +14,22 @@ import (
 func Connect(cfg config.DatabaseConfig) (*gorm.DB, error) {
 	dsn := cfg.DSN()
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: log.NewGormLogger(),
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	if err := db.Use(metrics.NewGormPlugin(cfg.DBName)); err != nil {
+		return nil, err
+	}
+	if err := db.Use(tracing.NewGormPlugin(cfg.DBName)); err != nil {
+		return nil, err
+	}
+	if err := db.Use(tenant.NewGormPlugin()); err != nil {
+		return nil, err
+	}
+
 	return db, nil
 }
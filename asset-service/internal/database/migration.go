@@ -2,19 +2,50 @@ package database
 
 import (
 	"asset-service/internal/models"
+	"context"
+	"embed"
+	"shared/pkg/migrate"
 
 	"gorm.io/gorm"
 )
 
-func Migrate(db *gorm.DB) error {
-	if err := db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp"`).Error; err != nil {
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// schemaModels are checked against the schema by Verify; add new models here along
+// with the migration creating their table.
+var schemaModels = []any{
+	&models.Folder{},
+	&models.Note{},
+	&models.FolderSharing{},
+	&models.NoteSharing{},
+	&models.Tag{},
+	&models.Attachment{},
+}
+
+// NewMigrator returns a migrator for the migrations embedded in the binary.
+func NewMigrator(db *gorm.DB) (*migrate.Migrator, error) {
+	migrations, err := migrate.Load(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(sqlDB, migrations), nil
+}
+
+// Migrate applies every pending migration.
+func Migrate(ctx context.Context, db *gorm.DB) error {
+	m, err := NewMigrator(db)
+	if err != nil {
 		return err
 	}
+	return m.Up(ctx)
+}
 
-	return db.AutoMigrate(
-		&models.Folder{},
-		&models.Note{},
-		&models.FolderSharing{},
-		&models.NoteSharing{},
-	)
+// Verify checks that the schema has what the models expect.
+func Verify(db *gorm.DB) error {
+	return migrate.Verify(db, schemaModels...)
 }
@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// The models as of the first release, which AutoMigrate created the schema
+// from before versioned migrations.
+type baselineFolder struct {
+	ID        uuid.UUID               `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Name      string                  `gorm:"not null"`
+	Notes     []baselineNote          `gorm:"foreignKey:FolderID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Sharings  []baselineFolderSharing `gorm:"foreignKey:FolderID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	OwnerID   uuid.UUID               `gorm:"type:uuid;not null"`
+	CreatedAt time.Time               `gorm:"autoCreateTime"`
+	UpdatedAt time.Time               `gorm:"autoUpdateTime"`
+	CreatedBy uuid.UUID               `gorm:"type:uuid;not null"`
+	UpdatedBy uuid.UUID               `gorm:"type:uuid;not null"`
+}
+
+func (baselineFolder) TableName() string { return "folders" }
+
+type baselineNote struct {
+	ID        uuid.UUID             `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Name      string                `gorm:"type:string"`
+	Content   string                `gorm:"type:string"`
+	FolderID  uuid.UUID             `gorm:"type:uuid;not null"`
+	Sharings  []baselineNoteSharing `gorm:"foreignKey:NoteID"`
+	CreatedAt time.Time             `gorm:"autoCreateTime"`
+	UpdatedAt time.Time             `gorm:"autoUpdateTime"`
+}
+
+func (baselineNote) TableName() string { return "notes" }
+
+type baselineFolderSharing struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null"`
+	Permission string    `gorm:"type:varchar(16);not null;check:permission IN ('read','write')"`
+	FolderID   uuid.UUID `gorm:"type:uuid"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
+
+func (baselineFolderSharing) TableName() string { return "folder_sharings" }
+
+type baselineNoteSharing struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null"`
+	Permission string    `gorm:"type:varchar(16);not null;check:permission IN ('read','write')"`
+	NoteID     uuid.UUID `gorm:"type:uuid"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
+
+func (baselineNoteSharing) TableName() string { return "note_sharings" }
+
+// scratchDB opens TEST_DATABASE_DSN in a schema of its own, dropped when the
+// test ends.
+func scratchDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	admin, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	schema := "migration_test_" + uuid.NewString()[:8]
+	if err := admin.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp"`).Error; err != nil {
+		t.Fatalf("Failed to create uuid-ossp: %v", err)
+	}
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+	})
+
+	db, err := gorm.Open(postgres.Open(fmt.Sprintf("%s search_path=%s,public", dsn, schema)), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("Failed to connect to %s: %v", schema, err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("Failed to get the pool: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+func TestMigrate_AdoptsAutoMigratedBaseline(t *testing.T) {
+	db := scratchDB(t)
+	ctx := context.Background()
+
+	if err := db.AutoMigrate(&baselineFolder{}, &baselineNote{}, &baselineFolderSharing{}, &baselineNoteSharing{}); err != nil {
+		t.Fatalf("Failed to create the baseline schema: %v", err)
+	}
+	folder := baselineFolder{Name: "Plans", OwnerID: uuid.New(), CreatedBy: uuid.New(), UpdatedBy: uuid.New()}
+	if err := db.Create(&folder).Error; err != nil {
+		t.Fatalf("Failed to seed a folder: %v", err)
+	}
+	if err := db.Create(&baselineNote{Name: "Roadmap", FolderID: folder.ID}).Error; err != nil {
+		t.Fatalf("Failed to seed a note: %v", err)
+	}
+
+	if err := Migrate(ctx, db); err != nil {
+		t.Fatalf("Expected the migrations to apply over the baseline, got %v", err)
+	}
+	if err := Verify(db); err != nil {
+		t.Errorf("Expected the migrated schema to match the models, got %v", err)
+	}
+
+	var missing int64
+	if err := db.Table("folders").Where("org_id IS NULL OR metadata IS NULL OR deleted_at IS NOT NULL").Count(&missing).Error; err != nil {
+		t.Fatalf("Failed to query the migrated folders: %v", err)
+	}
+	if missing != 0 {
+		t.Errorf("Expected existing folders to get defaults for the new columns, got %d without", missing)
+	}
+}
+
+func TestMigrate_FreshDatabaseMatchesModels(t *testing.T) {
+	db := scratchDB(t)
+
+	if err := Migrate(context.Background(), db); err != nil {
+		t.Fatalf("Expected the migrations to apply, got %v", err)
+	}
+	if err := Verify(db); err != nil {
+		t.Errorf("Expected the schema to match the models, got %v", err)
+	}
+}
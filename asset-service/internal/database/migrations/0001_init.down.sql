@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS note_sharings;
+DROP TABLE IF EXISTS folder_sharings;
+DROP TABLE IF EXISTS notes;
+DROP TABLE IF EXISTS folders;
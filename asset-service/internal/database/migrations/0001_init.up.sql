@@ -0,0 +1,51 @@
+-- Baseline schema, matching what AutoMigrate created for the first release,
+-- before the trash, folder details, tags and attachments. IF NOT EXISTS lets
+-- databases created by AutoMigrate adopt it; the later migrations add what
+-- came after and likewise skip what AutoMigrate already created.
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS folders (
+    id uuid NOT NULL DEFAULT uuid_generate_v4(),
+    name text NOT NULL,
+    owner_id uuid NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    created_by uuid NOT NULL,
+    updated_by uuid NOT NULL,
+    CONSTRAINT folders_pkey PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS notes (
+    id uuid NOT NULL DEFAULT uuid_generate_v4(),
+    name text,
+    content text,
+    folder_id uuid NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT notes_pkey PRIMARY KEY (id),
+    CONSTRAINT fk_folders_notes FOREIGN KEY (folder_id) REFERENCES folders (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS folder_sharings (
+    id uuid NOT NULL DEFAULT uuid_generate_v4(),
+    user_id uuid NOT NULL,
+    permission varchar(16) NOT NULL,
+    folder_id uuid,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT folder_sharings_pkey PRIMARY KEY (id),
+    CONSTRAINT fk_folders_sharings FOREIGN KEY (folder_id) REFERENCES folders (id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT chk_folder_sharings_permission CHECK (permission IN ('read','write'))
+);
+
+CREATE TABLE IF NOT EXISTS note_sharings (
+    id uuid NOT NULL DEFAULT uuid_generate_v4(),
+    user_id uuid NOT NULL,
+    permission varchar(16) NOT NULL,
+    note_id uuid,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT note_sharings_pkey PRIMARY KEY (id),
+    CONSTRAINT fk_notes_sharings FOREIGN KEY (note_id) REFERENCES notes (id),
+    CONSTRAINT chk_note_sharings_permission CHECK (permission IN ('read','write'))
+);
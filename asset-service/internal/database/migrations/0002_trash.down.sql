@@ -0,0 +1,10 @@
+-- Trashed rows would come back as live ones, so they are dropped first.
+DELETE FROM note_sharings WHERE deleted_at IS NOT NULL;
+DELETE FROM folder_sharings WHERE deleted_at IS NOT NULL;
+DELETE FROM notes WHERE deleted_at IS NOT NULL;
+DELETE FROM folders WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE note_sharings DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE folder_sharings DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE notes DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE folders DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,13 @@
+-- Deleted folders, notes and sharings stay in the trash until restored or
+-- purged.
+ALTER TABLE folders ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_folders_deleted_at ON folders (deleted_at);
+
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_notes_deleted_at ON notes (deleted_at);
+
+ALTER TABLE folder_sharings ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_folder_sharings_deleted_at ON folder_sharings (deleted_at);
+
+ALTER TABLE note_sharings ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_note_sharings_deleted_at ON note_sharings (deleted_at);
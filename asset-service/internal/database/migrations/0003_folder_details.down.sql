@@ -0,0 +1,3 @@
+ALTER TABLE folders DROP COLUMN IF EXISTS metadata;
+ALTER TABLE folders DROP COLUMN IF EXISTS color;
+ALTER TABLE folders DROP COLUMN IF EXISTS description;
@@ -0,0 +1,3 @@
+ALTER TABLE folders ADD COLUMN IF NOT EXISTS description text NOT NULL DEFAULT '';
+ALTER TABLE folders ADD COLUMN IF NOT EXISTS color varchar(7) NOT NULL DEFAULT '';
+ALTER TABLE folders ADD COLUMN IF NOT EXISTS metadata jsonb NOT NULL DEFAULT '{}';
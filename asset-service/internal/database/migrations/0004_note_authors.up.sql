@@ -0,0 +1,3 @@
+-- Notes from before this migration have no recorded author.
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS created_by uuid;
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS updated_by uuid;
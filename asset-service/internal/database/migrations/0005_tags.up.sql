@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS tags (
+    id uuid NOT NULL DEFAULT uuid_generate_v4(),
+    owner_id uuid NOT NULL,
+    name varchar(64) NOT NULL,
+    created_at timestamptz,
+    CONSTRAINT tags_pkey PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tag_owner_name ON tags (owner_id, name);
+
+CREATE TABLE IF NOT EXISTS note_tags (
+    note_id uuid NOT NULL,
+    tag_id uuid NOT NULL,
+    CONSTRAINT note_tags_pkey PRIMARY KEY (note_id, tag_id),
+    CONSTRAINT fk_note_tags_note FOREIGN KEY (note_id) REFERENCES notes (id) ON DELETE CASCADE,
+    CONSTRAINT fk_note_tags_tag FOREIGN KEY (tag_id) REFERENCES tags (id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+-- The blobs of the dropped attachments are left in storage.
+DROP TABLE IF EXISTS attachments;
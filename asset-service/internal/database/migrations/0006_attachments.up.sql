@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS attachments (
+    id uuid NOT NULL DEFAULT uuid_generate_v4(),
+    note_id uuid NOT NULL,
+    file_name text NOT NULL,
+    content_type varchar(255) NOT NULL,
+    size bigint NOT NULL,
+    checksum varchar(64) NOT NULL,
+    storage_key text NOT NULL,
+    created_by uuid NOT NULL,
+    created_at timestamptz,
+    CONSTRAINT attachments_pkey PRIMARY KEY (id),
+    CONSTRAINT fk_attachments_note FOREIGN KEY (note_id) REFERENCES notes (id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_attachments_note_id ON attachments (note_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_attachments_storage_key ON attachments (storage_key);
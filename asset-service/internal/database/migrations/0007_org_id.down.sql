@@ -0,0 +1,6 @@
+ALTER TABLE attachments DROP COLUMN IF EXISTS org_id;
+ALTER TABLE tags DROP COLUMN IF EXISTS org_id;
+ALTER TABLE note_sharings DROP COLUMN IF EXISTS org_id;
+ALTER TABLE folder_sharings DROP COLUMN IF EXISTS org_id;
+ALTER TABLE notes DROP COLUMN IF EXISTS org_id;
+ALTER TABLE folders DROP COLUMN IF EXISTS org_id;
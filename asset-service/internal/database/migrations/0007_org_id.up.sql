@@ -0,0 +1,34 @@
+-- Assets belong to the organisation of the user who created them. Rows from
+-- before organisations existed go to the default one user-service created in
+-- its migration 0007. The organisations live in user-service's database, so
+-- there is no foreign key.
+
+ALTER TABLE folders ADD COLUMN IF NOT EXISTS org_id uuid;
+UPDATE folders SET org_id = '00000000-0000-0000-0000-000000000001' WHERE org_id IS NULL;
+ALTER TABLE folders ALTER COLUMN org_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_folders_org_id ON folders (org_id);
+
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS org_id uuid;
+UPDATE notes SET org_id = '00000000-0000-0000-0000-000000000001' WHERE org_id IS NULL;
+ALTER TABLE notes ALTER COLUMN org_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_notes_org_id ON notes (org_id);
+
+ALTER TABLE folder_sharings ADD COLUMN IF NOT EXISTS org_id uuid;
+UPDATE folder_sharings SET org_id = '00000000-0000-0000-0000-000000000001' WHERE org_id IS NULL;
+ALTER TABLE folder_sharings ALTER COLUMN org_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_folder_sharings_org_id ON folder_sharings (org_id);
+
+ALTER TABLE note_sharings ADD COLUMN IF NOT EXISTS org_id uuid;
+UPDATE note_sharings SET org_id = '00000000-0000-0000-0000-000000000001' WHERE org_id IS NULL;
+ALTER TABLE note_sharings ALTER COLUMN org_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_note_sharings_org_id ON note_sharings (org_id);
+
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS org_id uuid;
+UPDATE tags SET org_id = '00000000-0000-0000-0000-000000000001' WHERE org_id IS NULL;
+ALTER TABLE tags ALTER COLUMN org_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_tags_org_id ON tags (org_id);
+
+ALTER TABLE attachments ADD COLUMN IF NOT EXISTS org_id uuid;
+UPDATE attachments SET org_id = '00000000-0000-0000-0000-000000000001' WHERE org_id IS NULL;
+ALTER TABLE attachments ALTER COLUMN org_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_attachments_org_id ON attachments (org_id);
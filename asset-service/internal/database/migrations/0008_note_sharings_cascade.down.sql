@@ -0,0 +1,3 @@
+ALTER TABLE note_sharings DROP CONSTRAINT IF EXISTS fk_notes_sharings;
+ALTER TABLE note_sharings ADD CONSTRAINT fk_notes_sharings
+    FOREIGN KEY (note_id) REFERENCES notes (id);
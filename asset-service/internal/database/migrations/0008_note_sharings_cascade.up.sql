@@ -0,0 +1,4 @@
+-- Sharings go with their note, like folder sharings go with their folder.
+ALTER TABLE note_sharings DROP CONSTRAINT IF EXISTS fk_notes_sharings;
+ALTER TABLE note_sharings ADD CONSTRAINT fk_notes_sharings
+    FOREIGN KEY (note_id) REFERENCES notes (id) ON DELETE CASCADE;
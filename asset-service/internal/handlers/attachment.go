@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"asset-service/internal/services"
+	"errors"
+	"mime"
+	"net/http"
+	"shared/middlewares"
+	"shared/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttachmentHandler struct {
+	svc      services.AttachmentService
+	maxBytes int64
+}
+
+func NewAttachmentHandler(svc services.AttachmentService, maxBytes int64) *AttachmentHandler {
+	return &AttachmentHandler{svc: svc, maxBytes: maxBytes}
+}
+
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	// Leave some room for the multipart envelope around the file itself.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxBytes+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.Error(services.ErrAttachmentTooLarge)
+			return
+		}
+		c.Error(apperror.BadRequest("multipart field 'file' is required"))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.Error(apperror.BadRequest("%v", err))
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.svc.Upload(c.Request.Context(), c.Param("noteId"), services.AttachmentUpload{
+		FileName:    fileHeader.Filename,
+		ContentType: fileHeader.Header.Get("Content-Type"),
+		Size:        fileHeader.Size,
+		Body:        file,
+	}, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+func (h *AttachmentHandler) ListAttachments(c *gin.Context) {
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	attachments, err := h.svc.List(c.Request.Context(), c.Param("noteId"), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+// DownloadAttachment streams the attachment. http.ServeContent takes care of
+// Range, If-Range and conditional requests.
+func (h *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	attachment, blob, err := h.svc.Open(c.Request.Context(), c.Param("noteId"), c.Param("attachmentId"), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	defer blob.Close()
+
+	c.Header("Content-Type", attachment.ContentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	c.Header("ETag", `"`+attachment.Checksum+`"`)
+	http.ServeContent(c.Writer, c.Request, attachment.FileName, blob.ModTime(), blob)
+}
+
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	if err := h.svc.Delete(c.Request.Context(), c.Param("noteId"), c.Param("attachmentId"), userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
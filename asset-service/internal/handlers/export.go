@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"asset-service/internal/services"
+	"mime"
+	"net/http"
+	"shared/middlewares"
+	"shared/pkg/apperror"
+	"shared/pkg/log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportHandler struct {
+	svc services.ExportService
+}
+
+func NewExportHandler(svc services.ExportService) *ExportHandler {
+	return &ExportHandler{svc: svc}
+}
+
+// ExportFolder streams the folder as a ZIP archive of Markdown files.
+func (h *ExportHandler) ExportFolder(c *gin.Context) {
+	format := c.DefaultQuery("format", services.ExportFormatZip)
+	if format != services.ExportFormatZip {
+		c.Error(apperror.BadRequest("unsupported export format: %s", format))
+		return
+	}
+
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	export, err := h.svc.ExportFolder(c.Request.Context(), c.Param("folderId"), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Large folders take longer than the server's write timeout to stream.
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		log.Warn(c.Request.Context(), "failed to lift write deadline for export", "error", err)
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": export.FileName()}))
+	c.Status(http.StatusOK)
+
+	// Once the first bytes are out the status can no longer change; a failed
+	// export leaves the client with a truncated archive that fails to open.
+	if err := export.WriteZip(c.Request.Context(), c.Writer); err != nil {
+		log.Error(c.Request.Context(), "failed to export folder", "folder_id", export.Folder.ID, "error", err)
+		c.Abort()
+	}
+}
@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"asset-service/internal/models"
 	"asset-service/internal/services"
 	"net/http"
+	"shared/middlewares"
+	"shared/pkg/apperror"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 type FolderHandler struct {
@@ -22,75 +24,123 @@ func (h *FolderHandler) CreateFolder(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperror.BadRequest("%v", err))
 		return
 	}
 
-	// Get user ID from auth middleware
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in token"})
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
 		return
 	}
 
-	result, err := h.svc.CreateFolder(req.Name, userID.(uuid.UUID))
+	result, err := h.svc.CreateFolder(c.Request.Context(), req.Name, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, result)
 }
 
+// ListFolders returns a page of the user's folders. Notes and sharings are
+// only embedded when requested with include=notes,sharings.
 func (h *FolderHandler) ListFolders(c *gin.Context) {
-	// Get user ID from auth middleware
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in token"})
+	q, err := ParseListQuery(c, models.FolderFields, models.FolderRelations)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	result, err := h.svc.ListFolders(userID.(uuid.UUID))
+	userID, err := middlewares.ExtractUserID(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	result, next, err := h.svc.ListFolders(c.Request.Context(), userID, q)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	WriteList(c, result, q, next)
 }
 
 func (h *FolderHandler) GetFolderByID(c *gin.Context) {
 	id := c.Param("folderId")
 
-	// Get user ID from auth middleware
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in token"})
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
 		return
 	}
 
-	result, err := h.svc.GetFolderByID(id, userID.(uuid.UUID))
+	result, err := h.svc.GetFolderByID(c.Request.Context(), id, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, result)
 }
 
-func (h *FolderHandler) DeleteFolder(c *gin.Context) {
+func (h *FolderHandler) UpdateFolder(c *gin.Context) {
+	var req models.FolderUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.BadRequest("%v", err))
+		return
+	}
+
 	id := c.Param("folderId")
 
-	// Get user ID from auth middleware
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User ID not found in token"})
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	result, err := h.svc.UpdateFolder(c.Request.Context(), id, req, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *FolderHandler) DuplicateFolder(c *gin.Context) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	// The body is optional; without a name the copy is called "<name> (copy)".
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(apperror.BadRequest("%v", err))
+			return
+		}
+	}
+
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
 		return
 	}
 
-	err := h.svc.DeleteFolder(id, userID.(uuid.UUID))
+	result, err := h.svc.DuplicateFolder(c.Request.Context(), c.Param("folderId"), req.Name, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
+
+func (h *FolderHandler) DeleteFolder(c *gin.Context) {
+	id := c.Param("folderId")
+
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	if err := h.svc.DeleteFolder(c.Request.Context(), id, userID); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"asset-service/internal/models"
+	"asset-service/internal/services"
+	"errors"
+	"net/http"
+	"shared/middlewares"
+	"shared/pkg/apperror"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImportHandler struct {
+	svc      services.ImportService
+	maxBytes int64
+}
+
+func NewImportHandler(svc services.ImportService, maxBytes int64) *ImportHandler {
+	return &ImportHandler{svc: svc, maxBytes: maxBytes}
+}
+
+// ImportNotes accepts one or more multipart "file" fields holding .md files
+// or .zip archives. With ?atomic=true either every note is created or none.
+func (h *ImportHandler) ImportNotes(c *gin.Context) {
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	atomic, err := strconv.ParseBool(c.DefaultQuery("atomic", "false"))
+	if err != nil {
+		c.Error(apperror.BadRequest("atomic must be true or false"))
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxBytes)
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.Error(apperror.TooLarge("import exceeds the maximum allowed size"))
+			return
+		}
+		c.Error(apperror.BadRequest("%v", err))
+		return
+	}
+
+	headers := form.File["file"]
+	if len(headers) == 0 {
+		c.Error(apperror.BadRequest("multipart field 'file' is required"))
+		return
+	}
+
+	files := make([]services.ImportFile, 0, len(headers))
+	for _, header := range headers {
+		file, err := header.Open()
+		if err != nil {
+			c.Error(apperror.BadRequest("%v", err))
+			return
+		}
+		defer file.Close()
+
+		files = append(files, services.ImportFile{Name: header.Filename, Size: header.Size, Body: file})
+	}
+
+	report, err := h.svc.ImportNotes(c.Request.Context(), c.Param("folderId"), files, atomic, userID)
+	if err != nil {
+		if report != nil {
+			// A rolled back import is still described file by file.
+			problem := apperror.ProblemFor(err, c.Request.URL.Path)
+			c.Header("Content-Type", apperror.ProblemContentType)
+			c.JSON(problem.Status, importProblem{Problem: problem, Report: report})
+			return
+		}
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// importProblem is the problem+json body of a failed import, extended with
+// the per-file report.
+type importProblem struct {
+	apperror.Problem
+	Report *models.ImportReport `json:"report"`
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"asset-service/internal/models"
+	"encoding/json"
+	"shared/pkg/apperror"
+	"shared/pkg/listing"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ParseListQuery reads limit, cursor, sort, fields and include from the query
+// string, validating fields and include against what the listing supports.
+//
+//	?limit=20&sort=-updatedAt&fields=noteName,updatedAt&include=tags&cursor=...
+func ParseListQuery(c *gin.Context, fields map[string]string, relations []string) (models.ListQuery, error) {
+	q := models.ListQuery{Limit: models.DefaultPageSize, Sort: models.SortCreatedAt}
+
+	if v := c.Query("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > models.MaxPageSize {
+			return q, apperror.BadRequest("limit must be between 1 and %d", models.MaxPageSize)
+		}
+		q.Limit = n
+	}
+
+	if v := c.Query("sort"); v != "" {
+		q.Desc = strings.HasPrefix(v, "-")
+		q.Sort = strings.TrimPrefix(v, "-")
+		switch q.Sort {
+		case models.SortName, models.SortCreatedAt, models.SortUpdatedAt:
+		default:
+			return q, apperror.BadRequest("sort must be one of name, createdAt or updatedAt, optionally prefixed with '-'")
+		}
+	}
+
+	cursor, err := listing.CursorParam(c)
+	if err != nil {
+		return q, err
+	}
+	q.After = cursor
+
+	for _, field := range splitList(c.Query("fields")) {
+		if _, ok := fields[field]; !ok {
+			return q, apperror.BadRequest("unknown field %q", field)
+		}
+		q.Fields = append(q.Fields, field)
+	}
+
+	for _, relation := range splitList(c.Query("include")) {
+		if !slices.Contains(relations, relation) {
+			return q, apperror.BadRequest("cannot include %q; expected one of %s", relation, strings.Join(relations, ", "))
+		}
+		q.Include = append(q.Include, relation)
+	}
+
+	return q, nil
+}
+
+// WriteList responds like listing.WriteList, with every item reduced to the
+// requested fields.
+func WriteList(c *gin.Context, items any, q models.ListQuery, next *listing.Cursor) {
+	if len(q.Fields) > 0 {
+		projected, err := projectFields(items, q)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		items = projected
+	}
+	listing.WriteList(c, items, next)
+}
+
+// projectFields keeps the id, the requested fields and the included
+// relations of every item.
+func projectFields(items any, q models.ListQuery) ([]map[string]json.RawMessage, error) {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return nil, err
+	}
+
+	keep := map[string]bool{"id": true}
+	for _, field := range q.Fields {
+		keep[field] = true
+	}
+	for _, relation := range q.Include {
+		keep[relation] = true
+	}
+
+	for _, object := range objects {
+		for key := range object {
+			if !keep[key] {
+				delete(object, key)
+			}
+		}
+	}
+	return objects, nil
+}
+
+func splitList(v string) []string {
+	var parts []string
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
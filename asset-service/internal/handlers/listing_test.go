@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"asset-service/internal/models"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"shared/pkg/listing"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func listTestContext(target string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	return c, w
+}
+
+func TestParseListQuery(t *testing.T) {
+	cursor := listing.Cursor{Sort: models.SortUpdatedAt, Desc: true, Value: "2026-01-02T03:04:05Z", ID: uuid.New()}
+	c, _ := listTestContext("/notes?limit=10&sort=-updatedAt&fields=noteName,%20updatedAt&include=tags&cursor=" + cursor.Encode())
+
+	q, err := ParseListQuery(c, models.NoteFields, models.NoteRelations)
+	if err != nil {
+		t.Fatalf("Expected query to parse, got: %v", err)
+	}
+	if q.Limit != 10 || q.Sort != models.SortUpdatedAt || !q.Desc {
+		t.Errorf("Unexpected paging: %+v", q)
+	}
+	if q.After == nil || *q.After != cursor {
+		t.Errorf("Expected cursor %+v, got %+v", cursor, q.After)
+	}
+	if len(q.Fields) != 2 || !q.HasField("updatedAt") || q.HasField("noteContent") {
+		t.Errorf("Unexpected fields: %v", q.Fields)
+	}
+	if !q.Includes("tags") || q.Includes("sharings") {
+		t.Errorf("Unexpected include: %v", q.Include)
+	}
+}
+
+func TestParseListQuery_RejectsInvalidInput(t *testing.T) {
+	for _, target := range []string{
+		"/notes?limit=0",
+		"/notes?limit=1000",
+		"/notes?sort=size",
+		"/notes?fields=password",
+		"/notes?include=folder",
+		"/notes?cursor=not-a-cursor",
+	} {
+		c, _ := listTestContext(target)
+		if _, err := ParseListQuery(c, models.NoteFields, models.NoteRelations); err == nil {
+			t.Errorf("Expected %s to be rejected", target)
+		}
+	}
+}
+
+func TestWriteList_ProjectsFieldsAndAdvertisesNextPage(t *testing.T) {
+	c, w := listTestContext("/notes?fields=noteName&limit=1")
+	q, err := ParseListQuery(c, models.NoteFields, models.NoteRelations)
+	if err != nil {
+		t.Fatalf("Expected query to parse, got: %v", err)
+	}
+
+	note := models.Note{ID: uuid.New(), Name: "Plan", Content: "secret"}
+	next := &listing.Cursor{Sort: q.Sort, Value: "x", ID: note.ID}
+	WriteList(c, []models.Note{note}, q, next)
+
+	var body []map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Expected a JSON array, got %s", w.Body.String())
+	}
+	if len(body) != 1 || len(body[0]) != 2 || body[0]["noteName"] != "Plan" || body[0]["id"] != note.ID.String() {
+		t.Errorf("Expected only id and noteName, got %v", body)
+	}
+	if w.Header().Get(listing.NextCursorHeader) != next.Encode() {
+		t.Errorf("Expected next cursor header, got %q", w.Header().Get(listing.NextCursorHeader))
+	}
+	if w.Header().Get("Link") == "" {
+		t.Error("Expected a Link header for the next page")
+	}
+}
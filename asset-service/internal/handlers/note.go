@@ -1,8 +1,15 @@
 package handlers
 
 import (
+	"asset-service/internal/models"
 	"asset-service/internal/services"
+	"encoding/json"
+	"io"
 	"net/http"
+	"shared/middlewares"
+	"shared/pkg/apperror"
+	"shared/pkg/listing"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -25,127 +32,258 @@ func (h *NoteHandler) CreateNote(c *gin.Context) {
 		FolderID uuid.UUID `json:"folder_id"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperror.BadRequest("%v", err))
 		return
 	}
 
-	// Extract user ID from context
-	userIDValue, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	note, err := h.NoteService.CreateNote(c.Request.Context(), req.Title, req.Content, req.FolderID, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, note)
+}
+
+// CreateFolderNote creates a note in the folder named in the path.
+func (h *NoteHandler) CreateFolderNote(c *gin.Context) {
+	var req struct {
+		Title   string `json:"title"`
+		Content string `json:"content"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.BadRequest("%v", err))
 		return
 	}
-	userID, ok := userIDValue.(uuid.UUID)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user ID format"})
+
+	folderID, err := uuid.Parse(c.Param("folderId"))
+	if err != nil {
+		c.Error(apperror.BadRequest("invalid folder ID"))
 		return
 	}
 
-	note, err := h.NoteService.CreateNote(req.Title, req.Content, req.FolderID, userID)
+	userID, err := middlewares.ExtractUserID(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	note, err := h.NoteService.CreateNote(c.Request.Context(), req.Title, req.Content, folderID, userID)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, note)
 }
 
+// ListFolderNotes returns a page of the folder's notes without their content.
+func (h *NoteHandler) ListFolderNotes(c *gin.Context) {
+	q, err := ParseListQuery(c, models.NoteSummaryFields, models.NoteSummaryRelations)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	notes, next, err := h.NoteService.ListFolderNotes(c.Request.Context(), c.Param("folderId"), q, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	WriteList(c, notes, q, next)
+}
+
 func (h *NoteHandler) ListNotes(c *gin.Context) {
-	// Extract user ID from context
-	userIDValue, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
 		return
 	}
-	userID, ok := userIDValue.(uuid.UUID)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user ID format"})
+
+	q, err := ParseListQuery(c, models.NoteFields, models.NoteRelations)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	notes, err := h.NoteService.ListNotes(userID)
+	var (
+		notes []models.Note
+		next  *listing.Cursor
+	)
+	if tags := c.Query("tags"); tags != "" {
+		var matchAll bool
+		switch c.DefaultQuery("match", "all") {
+		case "all":
+			matchAll = true
+		case "any":
+			matchAll = false
+		default:
+			c.Error(apperror.BadRequest("match must be 'all' or 'any'"))
+			return
+		}
+		notes, next, err = h.NoteService.ListNotesByTags(c.Request.Context(), userID, strings.Split(tags, ","), matchAll, q)
+	} else {
+		notes, next, err = h.NoteService.ListNotes(c.Request.Context(), userID, q)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, notes)
+	WriteList(c, notes, q, next)
 }
 
 func (h *NoteHandler) GetNote(c *gin.Context) {
 	id := c.Param("noteId")
 
-	// Extract user ID from context
-	userIDValue, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
-		return
-	}
-	userID, ok := userIDValue.(uuid.UUID)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user ID format"})
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
 		return
 	}
 
-	note, err := h.NoteService.GetNote(id, userID)
+	note, err := h.NoteService.GetNote(c.Request.Context(), id, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, note)
 }
 
+// UpdateNoteRequest is the PUT payload. Only the listed fields are accepted;
+// unknown fields are rejected.
+type UpdateNoteRequest struct {
+	Title   *string `json:"title"`
+	Content *string `json:"content"`
+}
+
 func (h *NoteHandler) UpdateNote(c *gin.Context) {
-	var req struct {
-		Title   string `json:"title"`
-		Content string `json:"content"`
-	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	var req UpdateNoteRequest
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		c.Error(apperror.BadRequest("%v", err))
 		return
 	}
 
 	id := c.Param("noteId")
 
-	// Extract user ID from context
-	userIDValue, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	update := models.NoteUpdate{Name: req.Title, Content: req.Content}
+	updatedNote, err := h.NoteService.UpdateNote(c.Request.Context(), id, update, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedNote)
+}
+
+// PatchNote accepts JSON Merge Patch (application/merge-patch+json, also used
+// for plain application/json) and JSON Patch (application/json-patch+json)
+// documents against the note's JSON representation.
+func (h *NoteHandler) PatchNote(c *gin.Context) {
+	var format models.PatchFormat
+	switch c.ContentType() {
+	case string(models.PatchFormatMerge), "application/json":
+		format = models.PatchFormatMerge
+	case string(models.PatchFormatJSONPatch):
+		format = models.PatchFormatJSONPatch
+	default:
+		c.Header("Accept-Patch", string(models.PatchFormatMerge)+", "+string(models.PatchFormatJSONPatch))
+		c.Error(apperror.Unsupported("unsupported patch content type"))
+		return
+	}
+
+	patch, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.Error(apperror.BadRequest("%v", err))
 		return
 	}
-	userID, ok := userIDValue.(uuid.UUID)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user ID format"})
+
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
 		return
 	}
 
-	updatedNote, err := h.NoteService.UpdateNote(id, req, userID)
+	updatedNote, err := h.NoteService.PatchNote(c.Request.Context(), c.Param("noteId"), format, patch, userID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, updatedNote)
 }
 
-func (h *NoteHandler) DeleteNote(c *gin.Context) {
-	id := c.Param("noteId")
+type TransferNoteRequest struct {
+	FolderID uuid.UUID `json:"folderId" binding:"required"`
+}
 
-	// Extract user ID from context
-	userIDValue, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+func (h *NoteHandler) MoveNote(c *gin.Context) {
+	var req TransferNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.BadRequest("%v", err))
 		return
 	}
-	userID, ok := userIDValue.(uuid.UUID)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "invalid user ID format"})
+
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	note, err := h.NoteService.MoveNote(c.Request.Context(), c.Param("noteId"), req.FolderID, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, note)
+}
+
+func (h *NoteHandler) CopyNote(c *gin.Context) {
+	var req TransferNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.BadRequest("%v", err))
+		return
+	}
+
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	note, err := h.NoteService.CopyNote(c.Request.Context(), c.Param("noteId"), req.FolderID, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, note)
+}
+
+func (h *NoteHandler) DeleteNote(c *gin.Context) {
+	id := c.Param("noteId")
+
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
 		return
 	}
 
-	if err := h.NoteService.DeleteNote(id, userID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.NoteService.DeleteNote(c.Request.Context(), id, userID); err != nil {
+		c.Error(err)
 		return
 	}
 
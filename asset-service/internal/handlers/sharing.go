@@ -4,6 +4,8 @@ import (
 	"asset-service/internal/models"
 	"asset-service/internal/services"
 	"net/http"
+	"shared/middlewares"
+	"shared/pkg/apperror"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -26,32 +28,24 @@ type ShareRequest struct {
 func (h *SharingHandler) ShareFolder(c *gin.Context) {
 	folderID, err := uuid.Parse(c.Param("folderId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+		c.Error(apperror.BadRequest("invalid folder ID"))
 		return
 	}
 
 	var req ShareRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperror.BadRequest("%v", err))
 		return
 	}
 
-	// Get user ID from JWT token context (set by auth middleware)
-	ownerID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
-		return
-	}
-
-	ownerUUID, ok := ownerID.(uuid.UUID)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+	ownerUUID, err := middlewares.ExtractUserID(c)
+	if err != nil {
 		return
 	}
 
-	err = h.svc.ShareFolder(folderID, req.UserID, req.Permission, ownerUUID)
+	err = h.svc.ShareFolder(c.Request.Context(), folderID, req.UserID, req.Permission, ownerUUID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -61,32 +55,24 @@ func (h *SharingHandler) ShareFolder(c *gin.Context) {
 func (h *SharingHandler) RevokeFolderSharing(c *gin.Context) {
 	folderID, err := uuid.Parse(c.Param("folderId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
+		c.Error(apperror.BadRequest("invalid folder ID"))
 		return
 	}
 
 	userID, err := uuid.Parse(c.Param("userId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
-		return
-	}
-
-	// Get owner ID from JWT token context
-	ownerID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		c.Error(apperror.BadRequest("invalid user ID"))
 		return
 	}
 
-	ownerUUID, ok := ownerID.(uuid.UUID)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+	ownerUUID, err := middlewares.ExtractUserID(c)
+	if err != nil {
 		return
 	}
 
-	err = h.svc.RevokeFolderSharing(folderID, userID, ownerUUID)
+	err = h.svc.RevokeFolderSharing(c.Request.Context(), folderID, userID, ownerUUID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -96,26 +82,18 @@ func (h *SharingHandler) RevokeFolderSharing(c *gin.Context) {
 func (h *SharingHandler) ListFolderSharings(c *gin.Context) {
 	folderID, err := uuid.Parse(c.Param("folderId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid folder ID"})
-		return
-	}
-
-	// Get owner ID from JWT token context
-	ownerID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		c.Error(apperror.BadRequest("invalid folder ID"))
 		return
 	}
 
-	ownerUUID, ok := ownerID.(uuid.UUID)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+	ownerUUID, err := middlewares.ExtractUserID(c)
+	if err != nil {
 		return
 	}
 
-	sharings, err := h.svc.ListFolderSharings(folderID, ownerUUID)
+	sharings, err := h.svc.ListFolderSharings(c.Request.Context(), folderID, ownerUUID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -126,32 +104,24 @@ func (h *SharingHandler) ListFolderSharings(c *gin.Context) {
 func (h *SharingHandler) ShareNote(c *gin.Context) {
 	noteID, err := uuid.Parse(c.Param("noteId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		c.Error(apperror.BadRequest("invalid note ID"))
 		return
 	}
 
 	var req ShareRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperror.BadRequest("%v", err))
 		return
 	}
 
-	// Get user ID from JWT token context (set by auth middleware)
-	ownerID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
-		return
-	}
-
-	ownerUUID, ok := ownerID.(uuid.UUID)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+	ownerUUID, err := middlewares.ExtractUserID(c)
+	if err != nil {
 		return
 	}
 
-	err = h.svc.ShareNote(noteID, req.UserID, req.Permission, ownerUUID)
+	err = h.svc.ShareNote(c.Request.Context(), noteID, req.UserID, req.Permission, ownerUUID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -161,32 +131,24 @@ func (h *SharingHandler) ShareNote(c *gin.Context) {
 func (h *SharingHandler) RevokeNoteSharing(c *gin.Context) {
 	noteID, err := uuid.Parse(c.Param("noteId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		c.Error(apperror.BadRequest("invalid note ID"))
 		return
 	}
 
 	userID, err := uuid.Parse(c.Param("userId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
-		return
-	}
-
-	// Get owner ID from JWT token context
-	ownerID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		c.Error(apperror.BadRequest("invalid user ID"))
 		return
 	}
 
-	ownerUUID, ok := ownerID.(uuid.UUID)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+	ownerUUID, err := middlewares.ExtractUserID(c)
+	if err != nil {
 		return
 	}
 
-	err = h.svc.RevokeNoteSharing(noteID, userID, ownerUUID)
+	err = h.svc.RevokeNoteSharing(c.Request.Context(), noteID, userID, ownerUUID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -196,26 +158,18 @@ func (h *SharingHandler) RevokeNoteSharing(c *gin.Context) {
 func (h *SharingHandler) ListNoteSharings(c *gin.Context) {
 	noteID, err := uuid.Parse(c.Param("noteId"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
-		return
-	}
-
-	// Get owner ID from JWT token context
-	ownerID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		c.Error(apperror.BadRequest("invalid note ID"))
 		return
 	}
 
-	ownerUUID, ok := ownerID.(uuid.UUID)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+	ownerUUID, err := middlewares.ExtractUserID(c)
+	if err != nil {
 		return
 	}
 
-	sharings, err := h.svc.ListNoteSharings(noteID, ownerUUID)
+	sharings, err := h.svc.ListNoteSharings(c.Request.Context(), noteID, ownerUUID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
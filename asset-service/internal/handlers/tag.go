@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"asset-service/internal/services"
+	"net/http"
+	"shared/middlewares"
+	"shared/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	svc services.TagService
+}
+
+func NewTagHandler(svc services.TagService) *TagHandler {
+	return &TagHandler{svc: svc}
+}
+
+type TagNoteRequest struct {
+	Tags []string `json:"tags" binding:"required"`
+}
+
+func (h *TagHandler) AddNoteTags(c *gin.Context) {
+	var req TagNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.BadRequest("%v", err))
+		return
+	}
+
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	tags, err := h.svc.AddNoteTags(c.Request.Context(), c.Param("noteId"), req.Tags, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tags)
+}
+
+func (h *TagHandler) RemoveNoteTag(c *gin.Context) {
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	if err := h.svc.RemoveNoteTag(c.Request.Context(), c.Param("noteId"), c.Param("tag"), userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *TagHandler) CountTags(c *gin.Context) {
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	counts, err := h.svc.CountTags(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, counts)
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"asset-service/internal/services"
+	"net/http"
+	"shared/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TrashHandler struct {
+	svc services.TrashService
+}
+
+func NewTrashHandler(svc services.TrashService) *TrashHandler {
+	return &TrashHandler{svc: svc}
+}
+
+func (h *TrashHandler) ListTrash(c *gin.Context) {
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	trash, err := h.svc.ListTrash(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, trash)
+}
+
+func (h *TrashHandler) RestoreFolder(c *gin.Context) {
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	if err := h.svc.RestoreFolder(c.Request.Context(), c.Param("folderId"), userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Folder restored successfully"})
+}
+
+func (h *TrashHandler) RestoreNote(c *gin.Context) {
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	if err := h.svc.RestoreNote(c.Request.Context(), c.Param("noteId"), userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Note restored successfully"})
+}
+
+func (h *TrashHandler) DeleteFolder(c *gin.Context) {
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	if err := h.svc.DeleteFolderPermanently(c.Request.Context(), c.Param("folderId"), userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *TrashHandler) DeleteNote(c *gin.Context) {
+	userID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	if err := h.svc.DeleteNotePermanently(c.Request.Context(), c.Param("noteId"), userID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -3,8 +3,12 @@ package httpserver
 import (
 	"asset-service/internal/handlers"
 	"asset-service/internal/services"
-	"net/http"
 	"shared/middlewares"
+	"shared/pkg/health"
+	"shared/pkg/listing"
+	"shared/pkg/log"
+	"shared/pkg/metrics"
+	"shared/pkg/tracing"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -14,25 +18,42 @@ type RouterDeps struct {
 	FolderService  services.FolderService
 	NoteService    services.NoteService
 	SharingService services.SharingService
+	TrashService   services.TrashService
+	TagService     services.TagService
+	ExportService  services.ExportService
+	ImportService  services.ImportService
+
+	AttachmentService  services.AttachmentService
+	AttachmentMaxBytes int64
+	ImportMaxBytes     int64
+
+	Health *health.Health
 }
 
 func NewRouter(deps RouterDeps) *gin.Engine {
 	r := gin.New()
+	r.Use(middlewares.RequestLogger())
+	r.Use(tracing.Middleware())
+	r.Use(metrics.HTTPMiddleware())
 	r.Use(gin.Recovery())
+	r.Use(middlewares.ErrorHandler())
 
 	// CORS middleware
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000", "http://localhost:5173", "http://localhost:5174", "http://localhost:4173"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept", "X-Requested-With"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept", "X-Requested-With", log.RequestIDHeader, "traceparent", "tracestate"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Disposition", "Content-Range", "Accept-Ranges", "Link", listing.NextCursorHeader, log.RequestIDHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * 3600, // 12 hours
 	}))
 
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok"})
-	})
+	r.GET("/metrics", metrics.Handler())
+
+	r.GET("/livez", deps.Health.Livez())
+	r.GET("/readyz", deps.Health.Readyz())
+	// Kept for existing probes; new ones should use /livez and /readyz.
+	r.GET("/health", deps.Health.Livez())
 
 	v1 := r.Group("/api/v1")
 
@@ -43,8 +64,20 @@ func NewRouter(deps RouterDeps) *gin.Engine {
 		folders.POST("", h.CreateFolder)
 		folders.GET("", h.ListFolders)
 		folders.GET("/:folderId", h.GetFolderByID)
+		folders.PUT("/:folderId", h.UpdateFolder)
+		folders.POST("/:folderId/duplicate", h.DuplicateFolder)
 		folders.DELETE("/:folderId", h.DeleteFolder)
 
+		noteHandler := handlers.NewNoteHandler(deps.NoteService)
+		folders.POST("/:folderId/notes", noteHandler.CreateFolderNote)
+		folders.GET("/:folderId/notes", noteHandler.ListFolderNotes)
+
+		exportHandler := handlers.NewExportHandler(deps.ExportService)
+		folders.GET("/:folderId/export", exportHandler.ExportFolder)
+
+		importHandler := handlers.NewImportHandler(deps.ImportService, deps.ImportMaxBytes)
+		folders.POST("/:folderId/import", importHandler.ImportNotes)
+
 		// Folder sharing endpoints
 		sharingHandler := handlers.NewSharingHandler(deps.SharingService)
 		folders.POST("/:folderId/share", sharingHandler.ShareFolder)
@@ -60,13 +93,46 @@ func NewRouter(deps RouterDeps) *gin.Engine {
 		notes.GET("", h.ListNotes)
 		notes.GET("/:noteId", h.GetNote)
 		notes.PUT("/:noteId", h.UpdateNote)
+		notes.PATCH("/:noteId", h.PatchNote)
 		notes.DELETE("/:noteId", h.DeleteNote)
+		notes.POST("/:noteId/move", h.MoveNote)
+		notes.POST("/:noteId/copy", h.CopyNote)
 
 		// Note sharing endpoints
 		sharingHandler := handlers.NewSharingHandler(deps.SharingService)
 		notes.POST("/:noteId/share", sharingHandler.ShareNote)
 		notes.DELETE("/:noteId/share/:userId", sharingHandler.RevokeNoteSharing)
 		notes.GET("/:noteId/share", sharingHandler.ListNoteSharings)
+
+		// Note tagging endpoints
+		tagHandler := handlers.NewTagHandler(deps.TagService)
+		notes.POST("/:noteId/tags", tagHandler.AddNoteTags)
+		notes.DELETE("/:noteId/tags/:tag", tagHandler.RemoveNoteTag)
+
+		// Note attachment endpoints
+		attachmentHandler := handlers.NewAttachmentHandler(deps.AttachmentService, deps.AttachmentMaxBytes)
+		notes.POST("/:noteId/attachments", attachmentHandler.UploadAttachment)
+		notes.GET("/:noteId/attachments", attachmentHandler.ListAttachments)
+		notes.GET("/:noteId/attachments/:attachmentId", attachmentHandler.DownloadAttachment)
+		notes.DELETE("/:noteId/attachments/:attachmentId", attachmentHandler.DeleteAttachment)
+	}
+
+	tags := v1.Group("/tags")
+	tags.Use(middlewares.AuthMiddleware())
+	{
+		h := handlers.NewTagHandler(deps.TagService)
+		tags.GET("", h.CountTags)
+	}
+
+	trash := v1.Group("/trash")
+	trash.Use(middlewares.AuthMiddleware())
+	{
+		h := handlers.NewTrashHandler(deps.TrashService)
+		trash.GET("", h.ListTrash)
+		trash.POST("/folders/:folderId/restore", h.RestoreFolder)
+		trash.POST("/notes/:noteId/restore", h.RestoreNote)
+		trash.DELETE("/folders/:folderId", h.DeleteFolder)
+		trash.DELETE("/notes/:noteId", h.DeleteNote)
 	}
 
 	return r
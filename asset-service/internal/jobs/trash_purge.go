@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"asset-service/internal/config"
+	"asset-service/internal/services"
+	"context"
+	"shared/pkg/log"
+	"shared/pkg/tenant"
+	"time"
+)
+
+// TrashPurger periodically removes trashed items older than the configured
+// retention period.
+type TrashPurger struct {
+	svc services.TrashService
+	cfg config.TrashConfig
+}
+
+func NewTrashPurger(svc services.TrashService, cfg config.TrashConfig) *TrashPurger {
+	return &TrashPurger{svc: svc, cfg: cfg}
+}
+
+// Run purges once immediately and then on every tick until ctx is cancelled.
+func (p *TrashPurger) Run(ctx context.Context) {
+	if p.cfg.Retention <= 0 || p.cfg.PurgeInterval <= 0 {
+		log.Info(ctx, "trash purge disabled")
+		return
+	}
+
+	ticker := time.NewTicker(p.cfg.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		p.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *TrashPurger) purge(ctx context.Context) {
+	// Retention applies to every organisation's trash alike
+	purged, err := p.svc.PurgeExpired(tenant.WithoutOrg(ctx), p.cfg.Retention)
+	if err != nil {
+		log.Error(ctx, "trash purge failed", "purged", purged, "error", err)
+		return
+	}
+	if purged > 0 {
+		log.Info(ctx, "trash purge completed", "purged", purged, "retention", p.cfg.Retention.String())
+	}
+}
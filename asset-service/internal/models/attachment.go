@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment is a file stored in the blob store and linked to a note. It
+// follows the note through the trash and is removed when the note is purged.
+type Attachment struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	OrgID       uuid.UUID `gorm:"type:uuid;not null;index" json:"-"`
+	NoteID      uuid.UUID `gorm:"type:uuid;not null;index" json:"noteId"`
+	Note        *Note     `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	FileName    string    `gorm:"not null" json:"fileName"`
+	ContentType string    `gorm:"type:varchar(255);not null" json:"contentType"`
+	Size        int64     `gorm:"not null" json:"size"`
+	Checksum    string    `gorm:"type:varchar(64);not null" json:"checksum"`
+	StorageKey  string    `gorm:"not null;uniqueIndex" json:"-"`
+	CreatedBy   uuid.UUID `gorm:"type:uuid;not null" json:"createdBy"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
@@ -4,16 +4,55 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Folder struct {
-	ID        uuid.UUID       `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	Name      string          `gorm:"not null" json:"folderName"`
-	Notes     []Note          `gorm:"foreignKey:FolderID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"notes"`
-	Sharings  []FolderSharing `gorm:"foreignKey:FolderID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"sharings"`
-	OwnerID   uuid.UUID       `gorm:"type:uuid;not null" json:"ownerId"`
-	CreatedAt time.Time       `gorm:"autoCreateTime" json:"createdAt"`
-	UpdatedAt time.Time       `gorm:"autoUpdateTime" json:"updatedAt"`
-	CreatedBy uuid.UUID       `gorm:"type:uuid;not null" json:"createdBy"`
-	UpdatedBy uuid.UUID       `gorm:"type:uuid;not null" json:"updatedBy"`
+	ID          uuid.UUID       `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	OrgID       uuid.UUID       `gorm:"type:uuid;not null;index" json:"-"`
+	Name        string          `gorm:"not null" json:"folderName"`
+	Description string          `gorm:"type:text;not null;default:''" json:"description"`
+	Color       string          `gorm:"type:varchar(7);not null;default:''" json:"color"`
+	Metadata    Metadata        `gorm:"type:jsonb;not null;default:'{}'" json:"metadata"`
+	Notes       []Note          `gorm:"foreignKey:FolderID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"notes"`
+	Sharings    []FolderSharing `gorm:"foreignKey:FolderID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"sharings"`
+	OwnerID     uuid.UUID       `gorm:"type:uuid;not null" json:"ownerId"`
+	CreatedAt   time.Time       `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time       `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt  `gorm:"index" json:"deletedAt,omitempty"`
+	CreatedBy   uuid.UUID       `gorm:"type:uuid;not null" json:"createdBy"`
+	UpdatedBy   uuid.UUID       `gorm:"type:uuid;not null" json:"updatedBy"`
+}
+
+// FolderFields maps the JSON fields of a folder that can be selected in
+// listings to their columns.
+var FolderFields = map[string]string{
+	"id":          "id",
+	"folderName":  "name",
+	"description": "description",
+	"color":       "color",
+	"metadata":    "metadata",
+	"ownerId":     "owner_id",
+	"createdAt":   "created_at",
+	"updatedAt":   "updated_at",
+	"createdBy":   "created_by",
+	"updatedBy":   "updated_by",
+}
+
+// Relations a folder listing can include.
+const (
+	FolderRelationNotes    = "notes"
+	FolderRelationSharings = "sharings"
+)
+
+// FolderRelations lists the relations a folder listing can include.
+var FolderRelations = []string{FolderRelationNotes, FolderRelationSharings}
+
+// FolderUpdate carries the fields a client may change on a folder. Nil fields
+// are left untouched; Metadata replaces the whole map when present.
+type FolderUpdate struct {
+	Name        *string   `json:"name"`
+	Description *string   `json:"description"`
+	Color       *string   `json:"color"`
+	Metadata    *Metadata `json:"metadata"`
 }
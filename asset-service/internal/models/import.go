@@ -0,0 +1,31 @@
+package models
+
+import "github.com/google/uuid"
+
+type ImportStatus string
+
+const (
+	ImportStatusCreated    ImportStatus = "created"
+	ImportStatusFailed     ImportStatus = "failed"
+	ImportStatusSkipped    ImportStatus = "skipped"
+	ImportStatusRolledBack ImportStatus = "rolled_back"
+)
+
+// ImportResult reports what happened to a single file. For files inside a
+// ZIP archive File is "<archive>/<path in archive>".
+type ImportResult struct {
+	File     string       `json:"file"`
+	Status   ImportStatus `json:"status"`
+	NoteID   *uuid.UUID   `json:"noteId,omitempty"`
+	NoteName string       `json:"noteName,omitempty"`
+	Error    string       `json:"error,omitempty"`
+}
+
+type ImportReport struct {
+	FolderID uuid.UUID      `json:"folderId"`
+	Atomic   bool           `json:"atomic"`
+	Created  int            `json:"created"`
+	Failed   int            `json:"failed"`
+	Skipped  int            `json:"skipped"`
+	Results  []ImportResult `json:"results"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Metadata holds free-form key/value pairs stored as a JSONB column.
+type Metadata map[string]string
+
+func (m Metadata) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (m *Metadata) Scan(value any) error {
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*m = Metadata{}
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into Metadata", value)
+	}
+	return json.Unmarshal(raw, m)
+}
@@ -4,14 +4,84 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Note struct {
-	ID        uuid.UUID     `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	Name      string        `gorm:"type:string" json:"noteName"`
-	Content   string        `gorm:"type:string" json:"noteContent"`
-	FolderID  uuid.UUID     `gorm:"type:uuid;not null" json:"folderId"`
-	Sharings  []NoteSharing `gorm:"foreignKey:NoteID" json:"sharings"`
-	CreatedAt time.Time     `gorm:"autoCreateTime" json:"createdAt"`
-	UpdatedAt time.Time     `gorm:"autoUpdateTime" json:"updatedAt"`
+	ID        uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	OrgID     uuid.UUID      `gorm:"type:uuid;not null;index" json:"-"`
+	Name      string         `gorm:"type:string" json:"noteName"`
+	Content   string         `gorm:"type:string" json:"noteContent"`
+	FolderID  uuid.UUID      `gorm:"type:uuid;not null" json:"folderId"`
+	Sharings  []NoteSharing  `gorm:"foreignKey:NoteID;constraint:OnDelete:CASCADE" json:"sharings"`
+	Tags      []Tag          `gorm:"many2many:note_tags;constraint:OnDelete:CASCADE" json:"tags"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
+	CreatedBy uuid.UUID      `gorm:"type:uuid" json:"createdBy"`
+	UpdatedBy uuid.UUID      `gorm:"type:uuid" json:"updatedBy"`
+}
+
+// NoteFields maps the JSON fields of a note that can be selected in listings
+// to their columns.
+var NoteFields = map[string]string{
+	"id":          "id",
+	"noteName":    "name",
+	"noteContent": "content",
+	"folderId":    "folder_id",
+	"createdAt":   "created_at",
+	"updatedAt":   "updated_at",
+	"createdBy":   "created_by",
+	"updatedBy":   "updated_by",
+}
+
+// NoteSummaryFields are the NoteFields available on note summaries.
+var NoteSummaryFields = map[string]string{
+	"id":        "id",
+	"noteName":  "name",
+	"folderId":  "folder_id",
+	"createdAt": "created_at",
+	"updatedAt": "updated_at",
+	"createdBy": "created_by",
+	"updatedBy": "updated_by",
+}
+
+// Relations a note listing can include.
+const (
+	NoteRelationSharings = "sharings"
+	NoteRelationTags     = "tags"
+)
+
+// NoteRelations lists the relations a note listing can include.
+var NoteRelations = []string{NoteRelationSharings, NoteRelationTags}
+
+// NoteSummaryRelations are the NoteRelations available on note summaries.
+var NoteSummaryRelations = []string{NoteRelationTags}
+
+// NoteUpdate lists the note fields clients are allowed to change. Nil fields
+// are left untouched.
+type NoteUpdate struct {
+	Name    *string `json:"noteName"`
+	Content *string `json:"noteContent"`
+}
+
+type PatchFormat string
+
+const (
+	// PatchFormatMerge is a JSON Merge Patch document (RFC 7396).
+	PatchFormatMerge PatchFormat = "application/merge-patch+json"
+	// PatchFormatJSONPatch is a JSON Patch operation list (RFC 6902).
+	PatchFormatJSONPatch PatchFormat = "application/json-patch+json"
+)
+
+// NoteSummary is a note without its content, for listings.
+type NoteSummary struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"noteName"`
+	FolderID  uuid.UUID `json:"folderId"`
+	Tags      []Tag     `json:"tags,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	CreatedBy uuid.UUID `json:"createdBy"`
+	UpdatedBy uuid.UUID `json:"updatedBy"`
 }
@@ -0,0 +1,52 @@
+package models
+
+import "shared/pkg/listing"
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// Sortable fields shared by every listing.
+const (
+	SortName      = "name"
+	SortCreatedAt = "createdAt"
+	SortUpdatedAt = "updatedAt"
+)
+
+// ListQuery controls cursor-paginated listings. Fields and Include use the
+// JSON names of the listed model.
+type ListQuery struct {
+	Limit int
+	After *listing.Cursor
+	// Sort is one of SortName, SortCreatedAt or SortUpdatedAt.
+	Sort string
+	Desc bool
+	// Fields limits the returned attributes; empty means all of them.
+	Fields []string
+	// Include names the relations to load alongside each item.
+	Include []string
+}
+
+func (q ListQuery) Includes(relation string) bool {
+	for _, name := range q.Include {
+		if name == relation {
+			return true
+		}
+	}
+	return false
+}
+
+// HasField reports whether the field was requested, treating an empty field
+// list as "all fields".
+func (q ListQuery) HasField(field string) bool {
+	if len(q.Fields) == 0 {
+		return true
+	}
+	for _, name := range q.Fields {
+		if name == field {
+			return true
+		}
+	}
+	return false
+}
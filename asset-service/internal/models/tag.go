@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tag is a label in a user's tag vocabulary. Notes are tagged from the
+// vocabulary of the user who owns their folder.
+type Tag struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	OrgID     uuid.UUID `gorm:"type:uuid;not null;index" json:"-"`
+	OwnerID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_tag_owner_name" json:"ownerId"`
+	Name      string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_tag_owner_name" json:"name"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+// TagCount is the number of notes carrying a tag name.
+type TagCount struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// Trash lists the soft-deleted folders and notes a user can restore or purge.
+type Trash struct {
+	Folders []Folder `json:"folders"`
+	Notes   []Note   `json:"notes"`
+}
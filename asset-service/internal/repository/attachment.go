@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"asset-service/internal/models"
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AttachmentRepository interface {
+	CreateAttachment(ctx context.Context, attachment *models.Attachment) error
+	GetAttachment(ctx context.Context, noteID, id uuid.UUID) (*models.Attachment, error)
+	ListAttachments(ctx context.Context, noteID uuid.UUID) ([]models.Attachment, error)
+	DeleteAttachment(ctx context.Context, id uuid.UUID) error
+	ListStorageKeysByNote(ctx context.Context, noteID uuid.UUID) ([]string, error)
+	ListStorageKeysByFolder(ctx context.Context, folderID uuid.UUID) ([]string, error)
+}
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) CreateAttachment(ctx context.Context, attachment *models.Attachment) error {
+	return r.db.WithContext(ctx).Create(attachment).Error
+}
+
+func (r *attachmentRepository) GetAttachment(ctx context.Context, noteID, id uuid.UUID) (*models.Attachment, error) {
+	var attachment models.Attachment
+	err := r.db.WithContext(ctx).Where("id = ? AND note_id = ?", id, noteID).First(&attachment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *attachmentRepository) ListAttachments(ctx context.Context, noteID uuid.UUID) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := r.db.WithContext(ctx).Where("note_id = ?", noteID).Order("created_at").Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *attachmentRepository) DeleteAttachment(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Attachment{}, "id = ?", id).Error
+}
+
+func (r *attachmentRepository) ListStorageKeysByNote(ctx context.Context, noteID uuid.UUID) ([]string, error) {
+	var keys []string
+	err := r.db.WithContext(ctx).Model(&models.Attachment{}).Where("note_id = ?", noteID).Pluck("storage_key", &keys).Error
+	return keys, err
+}
+
+// ListStorageKeysByFolder includes attachments of trashed notes, since purging
+// a folder removes those notes as well.
+func (r *attachmentRepository) ListStorageKeysByFolder(ctx context.Context, folderID uuid.UUID) ([]string, error) {
+	db := r.db.WithContext(ctx)
+	var keys []string
+	err := db.Model(&models.Attachment{}).
+		Where("note_id IN (?)", db.Unscoped().Model(&models.Note{}).Select("id").Where("folder_id = ?", folderID)).
+		Pluck("storage_key", &keys).Error
+	return keys, err
+}
@@ -2,18 +2,25 @@ package repository
 
 import (
 	"asset-service/internal/models"
+	"context"
+	"shared/pkg/listing"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type FolderRepository interface {
-	CreateFolder(folder *models.Folder) error
-	GetFolderByID(id uuid.UUID) (*models.Folder, error)
-	ListFolders() ([]models.Folder, error)
-	ListFoldersByOwner(ownerID uuid.UUID) ([]models.Folder, error)
-	ListFoldersByOwnerOrShared(userID uuid.UUID) ([]models.Folder, error)
-	DeleteFolder(id uuid.UUID) error
+	CreateFolder(ctx context.Context, folder *models.Folder) error
+	GetFolderByID(ctx context.Context, id uuid.UUID) (*models.Folder, error)
+	GetFolderWithSharings(ctx context.Context, id uuid.UUID) (*models.Folder, error)
+	ListFolders(ctx context.Context) ([]models.Folder, error)
+	ListFoldersByOwner(ctx context.Context, ownerID uuid.UUID) ([]models.Folder, error)
+	ListFoldersByOwnerOrShared(ctx context.Context, userID uuid.UUID, q models.ListQuery) ([]models.Folder, *listing.Cursor, error)
+	UpdateFolder(ctx context.Context, id uuid.UUID, changes map[string]any) error
+	DuplicateFolder(ctx context.Context, folder *models.Folder, notes []models.Note) error
+	DeleteFolder(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error
+	TransferOwnership(ctx context.Context, fromID, toID uuid.UUID) (int64, error)
 }
 
 type folderRepository struct {
@@ -24,41 +31,183 @@ func NewFolderRepository(db *gorm.DB) FolderRepository {
 	return &folderRepository{db: db}
 }
 
-func (r *folderRepository) CreateFolder(folder *models.Folder) error {
-	return r.db.Create(folder).Error
+func (r *folderRepository) CreateFolder(ctx context.Context, folder *models.Folder) error {
+	return r.db.WithContext(ctx).Create(folder).Error
 }
 
-func (r *folderRepository) GetFolderByID(id uuid.UUID) (*models.Folder, error) {
+func (r *folderRepository) GetFolderByID(ctx context.Context, id uuid.UUID) (*models.Folder, error) {
 	var folder models.Folder
-	err := r.db.Preload("Notes").Preload("Sharings").Where("id = ?", id).First(&folder).Error
+	err := r.db.WithContext(ctx).Preload("Notes").Preload("Sharings").Where("id = ?", id).First(&folder).Error
 	if err != nil {
 		return nil, err
 	}
 	return &folder, nil
 }
 
-func (r *folderRepository) ListFolders() ([]models.Folder, error) {
+// GetFolderWithSharings loads the folder and its sharings but not its notes,
+// for callers that only need to check access before reading notes separately.
+func (r *folderRepository) GetFolderWithSharings(ctx context.Context, id uuid.UUID) (*models.Folder, error) {
+	var folder models.Folder
+	if err := r.db.WithContext(ctx).Preload("Sharings").Where("id = ?", id).First(&folder).Error; err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+func (r *folderRepository) ListFolders(ctx context.Context) ([]models.Folder, error) {
 	var folders []models.Folder
-	err := r.db.Preload("Notes").Preload("Sharings").Find(&folders).Error
+	err := r.db.WithContext(ctx).Preload("Notes").Preload("Sharings").Find(&folders).Error
 	return folders, err
 }
 
-func (r *folderRepository) ListFoldersByOwner(ownerID uuid.UUID) ([]models.Folder, error) {
+func (r *folderRepository) ListFoldersByOwner(ctx context.Context, ownerID uuid.UUID) ([]models.Folder, error) {
 	var folders []models.Folder
-	err := r.db.Preload("Notes").Preload("Sharings").Where("owner_id = ?", ownerID).Find(&folders).Error
+	err := r.db.WithContext(ctx).Preload("Notes").Preload("Sharings").Where("owner_id = ?", ownerID).Find(&folders).Error
 	return folders, err
 }
 
-func (r *folderRepository) ListFoldersByOwnerOrShared(userID uuid.UUID) ([]models.Folder, error) {
-	var folders []models.Folder
-	err := r.db.Preload("Notes").Preload("Sharings").
-		Where("owner_id = ? OR id IN (?)",
+// ListFoldersByOwnerOrShared returns one page of the folders the user owns or
+// has shared with them. Notes and sharings are only loaded when included.
+func (r *folderRepository) ListFoldersByOwnerOrShared(ctx context.Context, userID uuid.UUID, q models.ListQuery) ([]models.Folder, *listing.Cursor, error) {
+	db := r.db.WithContext(ctx)
+	page, err := keyset("folders", q)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := db.Select(selectColumns("folders", models.FolderFields, q)).
+		Where("folders.owner_id = ? OR folders.id IN (?)",
 			userID,
-			r.db.Table("folder_sharings").Select("folder_id").Where("user_id = ?", userID)).
-		Find(&folders).Error
-	return folders, err
+			db.Model(&models.FolderSharing{}).Select("folder_id").Where("user_id = ?", userID)).
+		Scopes(page)
+	if q.Includes(models.FolderRelationNotes) {
+		query = query.Preload("Notes")
+	}
+	if q.Includes(models.FolderRelationSharings) {
+		query = query.Preload("Sharings")
+	}
+
+	var folders []models.Folder
+	if err := query.Find(&folders).Error; err != nil {
+		return nil, nil, err
+	}
+
+	folders, cursor := nextCursor(folders, q, func(f models.Folder) (uuid.UUID, string, time.Time, time.Time) {
+		return f.ID, f.Name, f.CreatedAt, f.UpdatedAt
+	})
+	return folders, cursor, nil
+}
+
+func (r *folderRepository) UpdateFolder(ctx context.Context, id uuid.UUID, changes map[string]any) error {
+	return r.db.WithContext(ctx).Model(&models.Folder{}).Where("id = ?", id).Updates(changes).Error
 }
 
-func (r *folderRepository) DeleteFolder(id uuid.UUID) error {
-	return r.db.Delete(&models.Folder{}, id).Error
+// DuplicateFolder creates the folder and its notes in a single transaction.
+func (r *folderRepository) DuplicateFolder(ctx context.Context, folder *models.Folder, notes []models.Note) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(folder).Error; err != nil {
+			return err
+		}
+		if len(notes) == 0 {
+			return nil
+		}
+
+		for i := range notes {
+			notes[i].FolderID = folder.ID
+		}
+		if err := tx.Create(&notes).Error; err != nil {
+			return err
+		}
+		folder.Notes = notes
+		return nil
+	})
+}
+
+// DeleteFolder moves the folder to the trash together with its notes and
+// sharings. Every row is stamped with the same deletion time so that a later
+// restore brings back exactly what this call removed.
+func (r *folderRepository) DeleteFolder(ctx context.Context, id uuid.UUID, deletedBy uuid.UUID) error {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		noteIDs := tx.Model(&models.Note{}).Select("id").Where("folder_id = ?", id)
+
+		if err := tx.Model(&models.NoteSharing{}).Where("note_id IN (?)", noteIDs).Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+		trashed := map[string]any{"deleted_at": now, "updated_by": deletedBy}
+
+		if err := tx.Model(&models.Note{}).Where("folder_id = ?", id).Updates(trashed).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.FolderSharing{}).Where("folder_id = ?", id).Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+
+		res := tx.Model(&models.Folder{}).Where("id = ?", id).Updates(trashed)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// TransferOwnership hands every folder owned by fromID, trashed ones
+// included, to toID and returns how many moved. Sharings that gave toID
+// access to those folders or their notes are dropped, as an owner needs none,
+// and the notes' tags move to toID's tags of the same name, as MoveNote does.
+func (r *folderRepository) TransferOwnership(ctx context.Context, fromID, toID uuid.UUID) (int64, error) {
+	var moved int64
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		folderIDs := tx.Unscoped().Model(&models.Folder{}).Select("id").Where("owner_id = ?", fromID)
+		noteIDs := tx.Unscoped().Model(&models.Note{}).Select("id").Where("folder_id IN (?)", folderIDs)
+
+		if err := tx.Unscoped().Where("user_id = ? AND note_id IN (?)", toID, noteIDs).Delete(&models.NoteSharing{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ? AND folder_id IN (?)", toID, folderIDs).Delete(&models.FolderSharing{}).Error; err != nil {
+			return err
+		}
+		if err := rehomeTags(tx, noteIDs, fromID, toID); err != nil {
+			return err
+		}
+
+		res := tx.Unscoped().Model(&models.Folder{}).Where("owner_id = ?", fromID).
+			Updates(map[string]any{"owner_id": toID, "updated_by": toID})
+		moved = res.RowsAffected
+		return res.Error
+	})
+	return moved, err
+}
+
+// rehomeTags points the notes' tags owned by fromID at toID's tags of the
+// same name, creating those that toID doesn't have yet.
+func rehomeTags(tx *gorm.DB, noteIDs *gorm.DB, fromID, toID uuid.UUID) error {
+	var names []string
+	err := tx.Table("note_tags").
+		Joins("JOIN tags ON tags.id = note_tags.tag_id").
+		Where("note_tags.note_id IN (?) AND tags.owner_id = ?", noteIDs, fromID).
+		Distinct().Pluck("tags.name", &names).Error
+	if err != nil || len(names) == 0 {
+		return err
+	}
+	if _, err := findOrCreateTags(tx, toID, names); err != nil {
+		return err
+	}
+
+	err = tx.Exec(`INSERT INTO note_tags (note_id, tag_id)
+		SELECT note_tags.note_id, new_tags.id FROM note_tags
+		JOIN tags old_tags ON old_tags.id = note_tags.tag_id
+		JOIN tags new_tags ON new_tags.owner_id = ? AND new_tags.name = old_tags.name
+		WHERE old_tags.owner_id = ? AND note_tags.note_id IN (?)
+		ON CONFLICT DO NOTHING`, toID, fromID, noteIDs).Error
+	if err != nil {
+		return err
+	}
+	return tx.Exec("DELETE FROM note_tags WHERE note_id IN (?) AND tag_id IN (?)",
+		noteIDs, tx.Model(&models.Tag{}).Select("id").Where("owner_id = ?", fromID)).Error
 }
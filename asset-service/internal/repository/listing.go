@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"asset-service/internal/models"
+	"fmt"
+	"maps"
+	"shared/pkg/apperror"
+	"shared/pkg/listing"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var sortColumns = map[string]string{
+	models.SortName:      "name",
+	models.SortCreatedAt: "created_at",
+	models.SortUpdatedAt: "updated_at",
+}
+
+// keyset applies the sort order, the position after the cursor and a limit
+// of one more than requested, which tells whether another page follows.
+func keyset(table string, q models.ListQuery) (func(*gorm.DB) *gorm.DB, error) {
+	column, ok := sortColumns[q.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort field %q", q.Sort)
+	}
+	column = table + "." + column
+	id := table + ".id"
+
+	var after []any
+	if q.After != nil {
+		if q.After.Sort != q.Sort || q.After.Desc != q.Desc {
+			return nil, apperror.BadRequest("cursor was issued for a different sort order")
+		}
+		value, err := cursorValue(q.Sort, q.After.Value)
+		if err != nil {
+			return nil, err
+		}
+		after = []any{value, q.After.ID}
+	}
+
+	dir, op := "ASC", ">"
+	if q.Desc {
+		dir, op = "DESC", "<"
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		if after != nil {
+			db = db.Where(fmt.Sprintf("(%s, %s) %s (?, ?)", column, id, op), after...)
+		}
+		return db.Order(fmt.Sprintf("%s %s, %s %s", column, dir, id, dir)).Limit(q.Limit + 1)
+	}, nil
+}
+
+func cursorValue(sort, value string) (any, error) {
+	if sort == models.SortName {
+		return value, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, listing.ErrInvalidCursor
+	}
+	return t, nil
+}
+
+// nextCursor trims the extra row fetched by keyset and returns the cursor for
+// the following page, or nil on the last page.
+func nextCursor[T any](items []T, q models.ListQuery, key func(T) (uuid.UUID, string, time.Time, time.Time)) ([]T, *listing.Cursor) {
+	if len(items) <= q.Limit {
+		return items, nil
+	}
+	items = items[:q.Limit]
+
+	id, name, createdAt, updatedAt := key(items[len(items)-1])
+	cursor := &listing.Cursor{Sort: q.Sort, Desc: q.Desc, ID: id}
+	switch q.Sort {
+	case models.SortName:
+		cursor.Value = name
+	case models.SortCreatedAt:
+		cursor.Value = createdAt.UTC().Format(time.RFC3339Nano)
+	case models.SortUpdatedAt:
+		cursor.Value = updatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return items, cursor
+}
+
+// selectColumns returns the table's columns for the requested fields, or for
+// every listed field when none were requested, plus the ones required for
+// keyset pagination and preloading. Columns missing from fields, such as a
+// note's content in summaries, are never loaded.
+func selectColumns(table string, fields map[string]string, q models.ListQuery, required ...string) []string {
+	requested := q.Fields
+	if len(requested) == 0 {
+		requested = slices.Sorted(maps.Keys(fields))
+	}
+
+	seen := map[string]bool{}
+	var columns []string
+	add := func(column string) {
+		if !seen[column] {
+			seen[column] = true
+			columns = append(columns, table+"."+column)
+		}
+	}
+
+	add("id")
+	add(sortColumns[q.Sort])
+	for _, column := range required {
+		add(column)
+	}
+	for _, field := range requested {
+		if column, ok := fields[field]; ok {
+			add(column)
+		}
+	}
+	return columns
+}
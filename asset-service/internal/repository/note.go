@@ -2,17 +2,25 @@ package repository
 
 import (
 	"asset-service/internal/models"
+	"context"
+	"shared/pkg/listing"
+	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type NoteRepository interface {
-	CreateNote(note *models.Note) error
-	ListNotes() ([]models.Note, error)
-	ListNotesByUserAccess(userID string) ([]models.Note, error)
-	GetNote(id string) (models.Note, error)
-	UpdateNote(id string, note any) (models.Note, error)
-	DeleteNote(id string) error
+	CreateNote(ctx context.Context, note *models.Note) error
+	ListNotes(ctx context.Context) ([]models.Note, error)
+	ListNotesByUserAccess(ctx context.Context, userID string, q models.ListQuery) ([]models.Note, *listing.Cursor, error)
+	ListNotesByTags(ctx context.Context, userID string, names []string, matchAll bool, q models.ListQuery) ([]models.Note, *listing.Cursor, error)
+	GetNote(ctx context.Context, id string) (models.Note, error)
+	ListNoteSummaries(ctx context.Context, folderID uuid.UUID, q models.ListQuery) ([]models.NoteSummary, *listing.Cursor, error)
+	EachNoteInFolder(ctx context.Context, folderID uuid.UUID, batchSize int, fn func([]models.Note) error) error
+	UpdateNote(ctx context.Context, id string, changes map[string]any, updatedBy uuid.UUID) (models.Note, error)
+	MoveNote(ctx context.Context, id string, target *models.Folder, updatedBy uuid.UUID, ownerChanged bool) (models.Note, error)
+	DeleteNote(ctx context.Context, id string, deletedBy uuid.UUID) error
 }
 
 type noteRepository struct {
@@ -23,45 +31,226 @@ func NewNoteRepository(db *gorm.DB) NoteRepository {
 	return &noteRepository{db: db}
 }
 
-func (r *noteRepository) CreateNote(note *models.Note) error {
-	return r.db.Create(note).Error
+func (r *noteRepository) CreateNote(ctx context.Context, note *models.Note) error {
+	return r.db.WithContext(ctx).Create(note).Error
 }
 
-func (r *noteRepository) ListNotes() ([]models.Note, error) {
+func (r *noteRepository) ListNotes(ctx context.Context) ([]models.Note, error) {
 	var notes []models.Note
-	if err := r.db.Find(&notes).Error; err != nil {
+	if err := r.db.WithContext(ctx).Find(&notes).Error; err != nil {
 		return nil, err
 	}
 	return notes, nil
 }
 
-func (r *noteRepository) ListNotesByUserAccess(userID string) ([]models.Note, error) {
+func (r *noteRepository) ListNotesByUserAccess(ctx context.Context, userID string, q models.ListQuery) ([]models.Note, *listing.Cursor, error) {
+	return r.listNotes(ctx, userID, q, nil)
+}
+
+// ListNotesByTags returns the user's accessible notes carrying all (matchAll)
+// or any of the given tag names.
+func (r *noteRepository) ListNotesByTags(ctx context.Context, userID string, names []string, matchAll bool, q models.ListQuery) ([]models.Note, *listing.Cursor, error) {
+	tagged := r.db.WithContext(ctx).Table("note_tags").
+		Select("note_tags.note_id").
+		Joins("JOIN tags ON tags.id = note_tags.tag_id").
+		Where("tags.name IN ?", names)
+	if matchAll {
+		tagged = tagged.Group("note_tags.note_id").Having("COUNT(DISTINCT tags.name) = ?", len(names))
+	}
+
+	return r.listNotes(ctx, userID, q, func(db *gorm.DB) *gorm.DB {
+		return db.Where("notes.id IN (?)", tagged)
+	})
+}
+
+// listNotes returns one page of the user's accessible notes, loading only the
+// requested columns and relations.
+func (r *noteRepository) listNotes(ctx context.Context, userID string, q models.ListQuery, filter func(*gorm.DB) *gorm.DB) ([]models.Note, *listing.Cursor, error) {
+	db := r.db.WithContext(ctx)
+	page, err := keyset("notes", q)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := db.Select(selectColumns("notes", models.NoteFields, q, "folder_id")).
+		Scopes(accessibleNotes(db, userID), page)
+	if filter != nil {
+		query = query.Scopes(filter)
+	}
+	if q.Includes(models.NoteRelationSharings) {
+		query = query.Preload("Sharings")
+	}
+	if q.Includes(models.NoteRelationTags) {
+		query = query.Preload("Tags")
+	}
+
 	var notes []models.Note
-	err := r.db.Preload("Sharings").
-		Joins("JOIN folders ON notes.folder_id = folders.id").
-		Where("folders.owner_id = ? OR folders.id IN (?)",
-			userID,
-			r.db.Table("folder_sharings").Select("folder_id").Where("user_id = ?", userID)).
-		Find(&notes).Error
-	return notes, err
+	if err := query.Find(&notes).Error; err != nil {
+		return nil, nil, err
+	}
+
+	notes, cursor := nextCursor(notes, q, func(n models.Note) (uuid.UUID, string, time.Time, time.Time) {
+		return n.ID, n.Name, n.CreatedAt, n.UpdatedAt
+	})
+	return notes, cursor, nil
+}
+
+// accessibleNotes limits a notes query to notes in live folders the user owns
+// or has shared with them.
+func accessibleNotes(db *gorm.DB, userID string) func(*gorm.DB) *gorm.DB {
+	return func(q *gorm.DB) *gorm.DB {
+		return q.Joins("JOIN folders ON notes.folder_id = folders.id").
+			Where("folders.deleted_at IS NULL").
+			Where("folders.owner_id = ? OR folders.id IN (?)",
+				userID,
+				db.Model(&models.FolderSharing{}).Select("folder_id").Where("user_id = ?", userID))
+	}
 }
 
-func (r *noteRepository) GetNote(id string) (models.Note, error) {
+func (r *noteRepository) GetNote(ctx context.Context, id string) (models.Note, error) {
 	var note models.Note
-	if err := r.db.First(&note, "id = ?", id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Tags").First(&note, "id = ?", id).Error; err != nil {
 		return models.Note{}, err
 	}
 	return note, nil
 }
 
-func (r *noteRepository) UpdateNote(id string, note any) (models.Note, error) {
+// ListNoteSummaries returns one page of the folder's notes. Note content is
+// never loaded.
+func (r *noteRepository) ListNoteSummaries(ctx context.Context, folderID uuid.UUID, q models.ListQuery) ([]models.NoteSummary, *listing.Cursor, error) {
+	page, err := keyset("notes", q)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := r.db.WithContext(ctx).Select(selectColumns("notes", models.NoteSummaryFields, q, "folder_id")).
+		Where("notes.folder_id = ?", folderID).
+		Scopes(page)
+	if q.Includes(models.NoteRelationTags) {
+		query = query.Preload("Tags")
+	}
+
+	var notes []models.Note
+	if err := query.Find(&notes).Error; err != nil {
+		return nil, nil, err
+	}
+
+	notes, cursor := nextCursor(notes, q, func(n models.Note) (uuid.UUID, string, time.Time, time.Time) {
+		return n.ID, n.Name, n.CreatedAt, n.UpdatedAt
+	})
+
+	summaries := make([]models.NoteSummary, len(notes))
+	for i, note := range notes {
+		summaries[i] = models.NoteSummary{
+			ID:        note.ID,
+			Name:      note.Name,
+			FolderID:  note.FolderID,
+			Tags:      note.Tags,
+			CreatedAt: note.CreatedAt,
+			UpdatedAt: note.UpdatedAt,
+			CreatedBy: note.CreatedBy,
+			UpdatedBy: note.UpdatedBy,
+		}
+	}
+	return summaries, cursor, nil
+}
+
+// EachNoteInFolder calls fn with the folder's notes, tags included, in batches
+// of batchSize ordered by id, so large folders are never loaded at once.
+func (r *noteRepository) EachNoteInFolder(ctx context.Context, folderID uuid.UUID, batchSize int, fn func([]models.Note) error) error {
+	var batch []models.Note
+	return r.db.WithContext(ctx).Preload("Tags").Where("folder_id = ?", folderID).
+		FindInBatches(&batch, batchSize, func(_ *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
+// UpdateNote applies the column changes and returns the note as stored
+// afterwards.
+func (r *noteRepository) UpdateNote(ctx context.Context, id string, changes map[string]any, updatedBy uuid.UUID) (models.Note, error) {
+	columns := make(map[string]any, len(changes)+1)
+	for column, value := range changes {
+		columns[column] = value
+	}
+	columns["updated_by"] = updatedBy
+
 	var updatedNote models.Note
-	if err := r.db.Model(&updatedNote).Where("id = ?", id).Updates(note).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Note{}).Where("id = ?", id).Updates(columns)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Preload("Tags").First(&updatedNote, "id = ?", id).Error
+	})
+	if err != nil {
 		return models.Note{}, err
 	}
 	return updatedNote, nil
 }
 
-func (r *noteRepository) DeleteNote(id string) error {
-	return r.db.Delete(&models.Note{}, "id = ?", id).Error
+// MoveNote re-parents the note. When the target folder has a different owner
+// the note's own sharings are revoked and its tags are re-created in the new
+// owner's vocabulary, all in the same transaction.
+func (r *noteRepository) MoveNote(ctx context.Context, id string, target *models.Folder, updatedBy uuid.UUID, ownerChanged bool) (models.Note, error) {
+	var note models.Note
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("Tags").First(&note, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		if ownerChanged {
+			if err := tx.Where("note_id = ?", id).Delete(&models.NoteSharing{}).Error; err != nil {
+				return err
+			}
+
+			if len(note.Tags) > 0 {
+				names := make([]string, len(note.Tags))
+				for i, tag := range note.Tags {
+					names[i] = tag.Name
+				}
+				tags, err := findOrCreateTags(tx, target.OwnerID, names)
+				if err != nil {
+					return err
+				}
+				if err := tx.Model(&note).Association("Tags").Replace(tags); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := tx.Model(&models.Note{}).Where("id = ?", id).
+			Updates(map[string]any{"folder_id": target.ID, "updated_by": updatedBy}).Error; err != nil {
+			return err
+		}
+
+		note = models.Note{}
+		return tx.Preload("Sharings").Preload("Tags").First(&note, "id = ?", id).Error
+	})
+	if err != nil {
+		return models.Note{}, err
+	}
+	return note, nil
+}
+
+// DeleteNote moves the note and its sharings to the trash.
+func (r *noteRepository) DeleteNote(ctx context.Context, id string, deletedBy uuid.UUID) error {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.NoteSharing{}).Where("note_id = ?", id).Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+
+		res := tx.Model(&models.Note{}).Where("id = ?", id).Updates(map[string]any{"deleted_at": now, "updated_by": deletedBy})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
 }
@@ -2,21 +2,24 @@ package repository
 
 import (
 	"asset-service/internal/models"
+	"context"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type SharingRepository interface {
-	ShareFolder(sharing *models.FolderSharing) error
-	GetFolderSharing(folderID, userID uuid.UUID) (*models.FolderSharing, error)
-	ListFolderSharings(folderID uuid.UUID) ([]models.FolderSharing, error)
-	RevokeFolderSharing(folderID, userID uuid.UUID) error
-
-	ShareNote(sharing *models.NoteSharing) error
-	GetNoteSharing(noteID, userID uuid.UUID) (*models.NoteSharing, error)
-	ListNoteSharings(noteID uuid.UUID) ([]models.NoteSharing, error)
-	RevokeNoteSharing(noteID, userID uuid.UUID) error
+	ShareFolder(ctx context.Context, sharing *models.FolderSharing) error
+	GetFolderSharing(ctx context.Context, folderID, userID uuid.UUID) (*models.FolderSharing, error)
+	ListFolderSharings(ctx context.Context, folderID uuid.UUID) ([]models.FolderSharing, error)
+	RevokeFolderSharing(ctx context.Context, folderID, userID uuid.UUID) error
+
+	ShareNote(ctx context.Context, sharing *models.NoteSharing) error
+	GetNoteSharing(ctx context.Context, noteID, userID uuid.UUID) (*models.NoteSharing, error)
+	ListNoteSharings(ctx context.Context, noteID uuid.UUID) ([]models.NoteSharing, error)
+	RevokeNoteSharing(ctx context.Context, noteID, userID uuid.UUID) error
+
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) (folders, notes int64, err error)
 }
 
 type sharingRepository struct {
@@ -28,75 +31,94 @@ func NewSharingRepository(db *gorm.DB) SharingRepository {
 }
 
 // Folder sharing methods
-func (r *sharingRepository) ShareFolder(sharing *models.FolderSharing) error {
+func (r *sharingRepository) ShareFolder(ctx context.Context, sharing *models.FolderSharing) error {
+	db := r.db.WithContext(ctx)
 	// Check if sharing already exists and update it, otherwise create new
 	var existingSharing models.FolderSharing
-	err := r.db.Where("folder_id = ? AND user_id = ?", sharing.FolderID, sharing.UserID).First(&existingSharing).Error
+	err := db.Where("folder_id = ? AND user_id = ?", sharing.FolderID, sharing.UserID).First(&existingSharing).Error
 
 	if err == gorm.ErrRecordNotFound {
 		// Create new sharing
-		return r.db.Create(sharing).Error
+		return db.Create(sharing).Error
 	} else if err != nil {
 		return err
 	}
 
 	// Update existing sharing
 	existingSharing.Permission = sharing.Permission
-	return r.db.Save(&existingSharing).Error
+	return db.Save(&existingSharing).Error
 }
 
-func (r *sharingRepository) GetFolderSharing(folderID, userID uuid.UUID) (*models.FolderSharing, error) {
+func (r *sharingRepository) GetFolderSharing(ctx context.Context, folderID, userID uuid.UUID) (*models.FolderSharing, error) {
 	var sharing models.FolderSharing
-	err := r.db.Where("folder_id = ? AND user_id = ?", folderID, userID).First(&sharing).Error
+	err := r.db.WithContext(ctx).Where("folder_id = ? AND user_id = ?", folderID, userID).First(&sharing).Error
 	if err != nil {
 		return nil, err
 	}
 	return &sharing, nil
 }
 
-func (r *sharingRepository) ListFolderSharings(folderID uuid.UUID) ([]models.FolderSharing, error) {
+func (r *sharingRepository) ListFolderSharings(ctx context.Context, folderID uuid.UUID) ([]models.FolderSharing, error) {
 	var sharings []models.FolderSharing
-	err := r.db.Where("folder_id = ?", folderID).Find(&sharings).Error
+	err := r.db.WithContext(ctx).Where("folder_id = ?", folderID).Find(&sharings).Error
 	return sharings, err
 }
 
-func (r *sharingRepository) RevokeFolderSharing(folderID, userID uuid.UUID) error {
-	return r.db.Where("folder_id = ? AND user_id = ?", folderID, userID).Delete(&models.FolderSharing{}).Error
+func (r *sharingRepository) RevokeFolderSharing(ctx context.Context, folderID, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("folder_id = ? AND user_id = ?", folderID, userID).Delete(&models.FolderSharing{}).Error
 }
 
 // Note sharing methods
-func (r *sharingRepository) ShareNote(sharing *models.NoteSharing) error {
+func (r *sharingRepository) ShareNote(ctx context.Context, sharing *models.NoteSharing) error {
+	db := r.db.WithContext(ctx)
 	// Check if sharing already exists and update it, otherwise create new
 	var existingSharing models.NoteSharing
-	err := r.db.Where("note_id = ? AND user_id = ?", sharing.NoteID, sharing.UserID).First(&existingSharing).Error
+	err := db.Where("note_id = ? AND user_id = ?", sharing.NoteID, sharing.UserID).First(&existingSharing).Error
 
 	if err == gorm.ErrRecordNotFound {
 		// Create new sharing
-		return r.db.Create(sharing).Error
+		return db.Create(sharing).Error
 	} else if err != nil {
 		return err
 	}
 
 	// Update existing sharing
 	existingSharing.Permission = sharing.Permission
-	return r.db.Save(&existingSharing).Error
+	return db.Save(&existingSharing).Error
 }
 
-func (r *sharingRepository) GetNoteSharing(noteID, userID uuid.UUID) (*models.NoteSharing, error) {
+func (r *sharingRepository) GetNoteSharing(ctx context.Context, noteID, userID uuid.UUID) (*models.NoteSharing, error) {
 	var sharing models.NoteSharing
-	err := r.db.Where("note_id = ? AND user_id = ?", noteID, userID).First(&sharing).Error
+	err := r.db.WithContext(ctx).Where("note_id = ? AND user_id = ?", noteID, userID).First(&sharing).Error
 	if err != nil {
 		return nil, err
 	}
 	return &sharing, nil
 }
 
-func (r *sharingRepository) ListNoteSharings(noteID uuid.UUID) ([]models.NoteSharing, error) {
+func (r *sharingRepository) ListNoteSharings(ctx context.Context, noteID uuid.UUID) ([]models.NoteSharing, error) {
 	var sharings []models.NoteSharing
-	err := r.db.Where("note_id = ?", noteID).Find(&sharings).Error
+	err := r.db.WithContext(ctx).Where("note_id = ?", noteID).Find(&sharings).Error
 	return sharings, err
 }
 
-func (r *sharingRepository) RevokeNoteSharing(noteID, userID uuid.UUID) error {
-	return r.db.Where("note_id = ? AND user_id = ?", noteID, userID).Delete(&models.NoteSharing{}).Error
+func (r *sharingRepository) RevokeNoteSharing(ctx context.Context, noteID, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("note_id = ? AND user_id = ?", noteID, userID).Delete(&models.NoteSharing{}).Error
+}
+
+// RevokeAllForUser revokes every folder and note sharing granted to the user
+// and returns how many of each were revoked.
+func (r *sharingRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) (folders, notes int64, err error) {
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("user_id = ?", userID).Delete(&models.FolderSharing{})
+		if res.Error != nil {
+			return res.Error
+		}
+		folders = res.RowsAffected
+
+		res = tx.Where("user_id = ?", userID).Delete(&models.NoteSharing{})
+		notes = res.RowsAffected
+		return res.Error
+	})
+	return folders, notes, err
 }
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"asset-service/internal/models"
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepository interface {
+	FindOrCreateTags(ctx context.Context, ownerID uuid.UUID, names []string) ([]models.Tag, error)
+	AddNoteTags(ctx context.Context, noteID uuid.UUID, tags []models.Tag) error
+	RemoveNoteTag(ctx context.Context, noteID, ownerID uuid.UUID, name string) error
+	ListNoteTags(ctx context.Context, noteID uuid.UUID) ([]models.Tag, error)
+	CountTagsByUserAccess(ctx context.Context, userID uuid.UUID) ([]models.TagCount, error)
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) FindOrCreateTags(ctx context.Context, ownerID uuid.UUID, names []string) ([]models.Tag, error) {
+	return findOrCreateTags(r.db.WithContext(ctx), ownerID, names)
+}
+
+func (r *tagRepository) AddNoteTags(ctx context.Context, noteID uuid.UUID, tags []models.Tag) error {
+	return r.db.WithContext(ctx).Model(&models.Note{ID: noteID}).Association("Tags").Append(tags)
+}
+
+// RemoveNoteTag detaches the owner's tag with the given name from the note.
+func (r *tagRepository) RemoveNoteTag(ctx context.Context, noteID, ownerID uuid.UUID, name string) error {
+	db := r.db.WithContext(ctx)
+	return db.Exec(
+		"DELETE FROM note_tags WHERE note_id = ? AND tag_id IN (?)",
+		noteID, db.Model(&models.Tag{}).Select("id").Where("owner_id = ? AND name = ?", ownerID, name),
+	).Error
+}
+
+func (r *tagRepository) ListNoteTags(ctx context.Context, noteID uuid.UUID) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.db.WithContext(ctx).Model(&models.Note{ID: noteID}).Order("name").Association("Tags").Find(&tags)
+	return tags, err
+}
+
+// CountTagsByUserAccess counts, per tag name, the notes the user can access.
+func (r *tagRepository) CountTagsByUserAccess(ctx context.Context, userID uuid.UUID) ([]models.TagCount, error) {
+	db := r.db.WithContext(ctx)
+	accessible := db.Model(&models.Note{}).Select("notes.id").Scopes(accessibleNotes(db, userID.String()))
+
+	var counts []models.TagCount
+	err := db.Table("note_tags").
+		Select("tags.name AS name, COUNT(DISTINCT note_tags.note_id) AS count").
+		Joins("JOIN tags ON tags.id = note_tags.tag_id").
+		Where("note_tags.note_id IN (?)", accessible).
+		Group("tags.name").
+		Order("count DESC, name").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// findOrCreateTags returns the owner's tags with the given names, creating the
+// missing ones. Concurrent creators are tolerated through ON CONFLICT.
+func findOrCreateTags(db *gorm.DB, ownerID uuid.UUID, names []string) ([]models.Tag, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	tags := make([]models.Tag, len(names))
+	for i, name := range names {
+		tags[i] = models.Tag{OwnerID: ownerID, Name: name}
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	var existing []models.Tag
+	err := db.Where("owner_id = ? AND name IN ?", ownerID, names).Find(&existing).Error
+	return existing, err
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Repositories groups repositories that share one database handle.
+type Repositories struct {
+	Notes   NoteRepository
+	Folders FolderRepository
+}
+
+// Transactor runs work against repositories bound to a single transaction,
+// so services can compose several repository calls atomically.
+type Transactor interface {
+	// WithinTransaction commits when fn returns nil and rolls back otherwise.
+	WithinTransaction(ctx context.Context, fn func(repos Repositories) error) error
+}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return &transactor{db: db}
+}
+
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(repos Repositories) error) error {
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(Repositories{
+			Notes:   NewNoteRepository(tx),
+			Folders: NewFolderRepository(tx),
+		})
+	})
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"asset-service/internal/models"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TrashRepository interface {
+	ListDeletedFolders(ctx context.Context, ownerID uuid.UUID) ([]models.Folder, error)
+	ListDeletedNotes(ctx context.Context, userID uuid.UUID) ([]models.Note, error)
+	GetDeletedFolder(ctx context.Context, id uuid.UUID) (*models.Folder, error)
+	GetDeletedNote(ctx context.Context, id uuid.UUID) (*models.Note, error)
+	GetFolderIncludingDeleted(ctx context.Context, id uuid.UUID) (*models.Folder, error)
+	RestoreFolder(ctx context.Context, folder *models.Folder, restoredBy uuid.UUID) error
+	RestoreNote(ctx context.Context, note *models.Note, restoredBy uuid.UUID) error
+	PurgeFolder(ctx context.Context, id uuid.UUID) error
+	PurgeNote(ctx context.Context, id uuid.UUID) error
+	ListExpiredFolderIDs(ctx context.Context, cutoff time.Time) ([]uuid.UUID, error)
+	ListExpiredNoteIDs(ctx context.Context, cutoff time.Time) ([]uuid.UUID, error)
+}
+
+type trashRepository struct {
+	db *gorm.DB
+}
+
+func NewTrashRepository(db *gorm.DB) TrashRepository {
+	return &trashRepository{db: db}
+}
+
+func (r *trashRepository) ListDeletedFolders(ctx context.Context, ownerID uuid.UUID) ([]models.Folder, error) {
+	var folders []models.Folder
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("owner_id = ? AND deleted_at IS NOT NULL", ownerID).
+		Order("deleted_at DESC").
+		Find(&folders).Error
+	return folders, err
+}
+
+// ListDeletedNotes returns notes that were deleted on their own from folders
+// the user owns, and those the user deleted from folders still shared with
+// them for writing. Notes removed together with their folder are restored
+// through the folder and are not listed separately.
+func (r *trashRepository) ListDeletedNotes(ctx context.Context, userID uuid.UUID) ([]models.Note, error) {
+	db := r.db.WithContext(ctx)
+	writable := db.Model(&models.FolderSharing{}).Select("folder_id").
+		Where("user_id = ? AND permission = ?", userID, models.PermissionWrite)
+
+	var notes []models.Note
+	err := db.Unscoped().
+		Joins("JOIN folders ON notes.folder_id = folders.id").
+		Where("folders.deleted_at IS NULL AND notes.deleted_at IS NOT NULL").
+		Where("folders.owner_id = ? OR (notes.updated_by = ? AND folders.id IN (?))", userID, userID, writable).
+		Order("notes.deleted_at DESC").
+		Find(&notes).Error
+	return notes, err
+}
+
+func (r *trashRepository) GetDeletedFolder(ctx context.Context, id uuid.UUID) (*models.Folder, error) {
+	var folder models.Folder
+	err := r.db.WithContext(ctx).Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&folder).Error
+	if err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+func (r *trashRepository) GetDeletedNote(ctx context.Context, id uuid.UUID) (*models.Note, error) {
+	var note models.Note
+	err := r.db.WithContext(ctx).Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&note).Error
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// GetFolderIncludingDeleted returns the folder with its active sharings,
+// whether or not the folder itself is in the trash.
+func (r *trashRepository) GetFolderIncludingDeleted(ctx context.Context, id uuid.UUID) (*models.Folder, error) {
+	var folder models.Folder
+	err := r.db.WithContext(ctx).Unscoped().Preload("Sharings", "deleted_at IS NULL").Where("id = ?", id).First(&folder).Error
+	if err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+// RestoreFolder brings back the folder and every note and sharing that was
+// trashed in the same operation. Sharings superseded by a newer active sharing
+// for the same user are left in the trash.
+func (r *trashRepository) RestoreFolder(ctx context.Context, folder *models.Folder, restoredBy uuid.UUID) error {
+	deletedAt := folder.DeletedAt.Time
+	restored := map[string]any{"deleted_at": nil, "updated_by": restoredBy}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.Folder{}).
+			Where("id = ?", folder.ID).
+			Updates(restored).Error; err != nil {
+			return err
+		}
+
+		activeFolderShares := tx.Model(&models.FolderSharing{}).Select("user_id").Where("folder_id = ?", folder.ID)
+		if err := tx.Unscoped().Model(&models.FolderSharing{}).
+			Where("folder_id = ? AND deleted_at = ? AND user_id NOT IN (?)", folder.ID, deletedAt, activeFolderShares).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+
+		var noteIDs []uuid.UUID
+		if err := tx.Unscoped().Model(&models.Note{}).
+			Where("folder_id = ? AND deleted_at = ?", folder.ID, deletedAt).
+			Pluck("id", &noteIDs).Error; err != nil {
+			return err
+		}
+		if len(noteIDs) == 0 {
+			return nil
+		}
+
+		if err := tx.Unscoped().Model(&models.Note{}).
+			Where("id IN ?", noteIDs).
+			Updates(restored).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Model(&models.NoteSharing{}).
+			Where("note_id IN ? AND deleted_at = ?", noteIDs, deletedAt).
+			Update("deleted_at", nil).Error
+	})
+}
+
+func (r *trashRepository) RestoreNote(ctx context.Context, note *models.Note, restoredBy uuid.UUID) error {
+	deletedAt := note.DeletedAt.Time
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.Note{}).
+			Where("id = ?", note.ID).
+			Updates(map[string]any{"deleted_at": nil, "updated_by": restoredBy}).Error; err != nil {
+			return err
+		}
+
+		activeNoteShares := tx.Model(&models.NoteSharing{}).Select("user_id").Where("note_id = ?", note.ID)
+		return tx.Unscoped().Model(&models.NoteSharing{}).
+			Where("note_id = ? AND deleted_at = ? AND user_id NOT IN (?)", note.ID, deletedAt, activeNoteShares).
+			Update("deleted_at", nil).Error
+	})
+}
+
+// PurgeFolder permanently removes the folder, its notes and all sharings,
+// regardless of whether the individual rows are trashed.
+func (r *trashRepository) PurgeFolder(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		noteIDs := tx.Unscoped().Model(&models.Note{}).Select("id").Where("folder_id = ?", id)
+
+		if err := tx.Unscoped().Where("note_id IN (?)", noteIDs).Delete(&models.NoteSharing{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("folder_id = ?", id).Delete(&models.Note{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("folder_id = ?", id).Delete(&models.FolderSharing{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id = ?", id).Delete(&models.Folder{}).Error
+	})
+}
+
+func (r *trashRepository) PurgeNote(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("note_id = ?", id).Delete(&models.NoteSharing{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id = ?", id).Delete(&models.Note{}).Error
+	})
+}
+
+func (r *trashRepository) ListExpiredFolderIDs(ctx context.Context, cutoff time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Unscoped().Model(&models.Folder{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *trashRepository) ListExpiredNoteIDs(ctx context.Context, cutoff time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Unscoped().Model(&models.Note{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Pluck("id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"asset-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// canReadFolder reports whether the user owns the folder or has it shared
+// with them at any permission level.
+func canReadFolder(folder *models.Folder, userID uuid.UUID) bool {
+	if folder.OwnerID == userID {
+		return true
+	}
+	for _, sharing := range folder.Sharings {
+		if sharing.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// canWriteFolder reports whether the user owns the folder or has write
+// permission shared with them.
+func canWriteFolder(folder *models.Folder, userID uuid.UUID) bool {
+	if folder.OwnerID == userID {
+		return true
+	}
+	for _, sharing := range folder.Sharings {
+		if sharing.UserID == userID && sharing.Permission == models.PermissionWrite {
+			return true
+		}
+	}
+	return false
+}
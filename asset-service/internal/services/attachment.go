@@ -0,0 +1,244 @@
+package services
+
+import (
+	"asset-service/internal/models"
+	"asset-service/internal/repository"
+	"asset-service/internal/storage"
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"shared/pkg/apperror"
+	"shared/pkg/log"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrAttachmentTooLarge is returned when an upload exceeds the size limit.
+var ErrAttachmentTooLarge = apperror.TooLarge("attachment exceeds the maximum allowed size")
+
+type AttachmentLimits struct {
+	MaxBytes     int64
+	AllowedTypes []string
+}
+
+// AttachmentUpload describes a file received from a client. ContentType is
+// only a hint; the stored type is sniffed from the content.
+type AttachmentUpload struct {
+	FileName    string
+	ContentType string
+	Size        int64
+	Body        io.Reader
+}
+
+type AttachmentService interface {
+	Upload(ctx context.Context, noteID string, upload AttachmentUpload, userID uuid.UUID) (*models.Attachment, error)
+	List(ctx context.Context, noteID string, userID uuid.UUID) ([]models.Attachment, error)
+	Open(ctx context.Context, noteID, attachmentID string, userID uuid.UUID) (*models.Attachment, storage.Blob, error)
+	Delete(ctx context.Context, noteID, attachmentID string, userID uuid.UUID) error
+}
+
+type attachmentService struct {
+	repo       repository.AttachmentRepository
+	noteRepo   repository.NoteRepository
+	folderRepo repository.FolderRepository
+	blobs      storage.BlobStore
+	limits     AttachmentLimits
+}
+
+func NewAttachmentService(
+	repo repository.AttachmentRepository,
+	noteRepo repository.NoteRepository,
+	folderRepo repository.FolderRepository,
+	blobs storage.BlobStore,
+	limits AttachmentLimits,
+) AttachmentService {
+	return &attachmentService{
+		repo:       repo,
+		noteRepo:   noteRepo,
+		folderRepo: folderRepo,
+		blobs:      blobs,
+		limits:     limits,
+	}
+}
+
+func (s *attachmentService) Upload(ctx context.Context, noteID string, upload AttachmentUpload, userID uuid.UUID) (*models.Attachment, error) {
+	note, err := s.noteWithAccess(ctx, noteID, userID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if upload.Size > s.limits.MaxBytes {
+		return nil, ErrAttachmentTooLarge
+	}
+
+	fileName := filepath.Base(strings.TrimSpace(upload.FileName))
+	if fileName == "" || fileName == "." || fileName == string(filepath.Separator) {
+		return nil, apperror.Validation("invalid attachment", map[string]string{"file": "file name is required"})
+	}
+
+	// Sniff the real content type from the first bytes instead of trusting
+	// the client-provided header.
+	body := bufio.NewReaderSize(upload.Body, 512)
+	head, err := body.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+
+	contentType := http.DetectContentType(head)
+	if !s.allowedType(contentType) {
+		return nil, apperror.Validation("invalid attachment", map[string]string{"file": fmt.Sprintf("type %q is not allowed", contentType)})
+	}
+
+	id := uuid.New()
+	attachment := &models.Attachment{
+		ID:          id,
+		NoteID:      note.ID,
+		FileName:    fileName,
+		ContentType: contentType,
+		CreatedBy:   userID,
+		StorageKey:  fmt.Sprintf("attachments/%s/%s", note.ID, id),
+	}
+
+	hash := sha256.New()
+	counter := &countingReader{r: io.LimitReader(body, s.limits.MaxBytes+1)}
+	if err := s.blobs.Put(ctx, attachment.StorageKey, io.TeeReader(counter, hash), upload.Size, contentType); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+
+	if counter.n > s.limits.MaxBytes {
+		s.removeBlob(ctx, attachment.StorageKey)
+		return nil, ErrAttachmentTooLarge
+	}
+
+	attachment.Size = counter.n
+	attachment.Checksum = hex.EncodeToString(hash.Sum(nil))
+
+	if err := s.repo.CreateAttachment(ctx, attachment); err != nil {
+		s.removeBlob(ctx, attachment.StorageKey)
+		return nil, fmt.Errorf("failed to save attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+func (s *attachmentService) List(ctx context.Context, noteID string, userID uuid.UUID) ([]models.Attachment, error) {
+	note, err := s.noteWithAccess(ctx, noteID, userID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListAttachments(ctx, note.ID)
+}
+
+// Open returns the attachment metadata and an open blob. The caller must
+// close the blob.
+func (s *attachmentService) Open(ctx context.Context, noteID, attachmentID string, userID uuid.UUID) (*models.Attachment, storage.Blob, error) {
+	attachment, err := s.attachment(ctx, noteID, attachmentID, userID, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blob, err := s.blobs.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open attachment: %w", err)
+	}
+
+	return attachment, blob, nil
+}
+
+func (s *attachmentService) Delete(ctx context.Context, noteID, attachmentID string, userID uuid.UUID) error {
+	attachment, err := s.attachment(ctx, noteID, attachmentID, userID, true)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteAttachment(ctx, attachment.ID); err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	s.removeBlob(ctx, attachment.StorageKey)
+	return nil
+}
+
+func (s *attachmentService) attachment(ctx context.Context, noteID, attachmentID string, userID uuid.UUID, write bool) (*models.Attachment, error) {
+	id, err := parseID(attachmentID, "attachment")
+	if err != nil {
+		return nil, err
+	}
+
+	note, err := s.noteWithAccess(ctx, noteID, userID, write)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.repo.GetAttachment(ctx, note.ID, id)
+	if err != nil {
+		return nil, apperror.Lookup(err, "attachment")
+	}
+
+	return attachment, nil
+}
+
+// noteWithAccess applies the note's access rules: read access to the folder
+// for viewing attachments, write access for changing them.
+func (s *attachmentService) noteWithAccess(ctx context.Context, noteID string, userID uuid.UUID, write bool) (models.Note, error) {
+	if _, err := parseID(noteID, "note"); err != nil {
+		return models.Note{}, err
+	}
+	note, err := s.noteRepo.GetNote(ctx, noteID)
+	if err != nil {
+		return models.Note{}, apperror.Lookup(err, "note")
+	}
+
+	folder, err := s.folderRepo.GetFolderByID(ctx, note.FolderID)
+	if err != nil {
+		return models.Note{}, apperror.Lookup(err, "folder")
+	}
+
+	if write && !canWriteFolder(folder, userID) {
+		return models.Note{}, apperror.Forbidden("access denied: you don't have write permission for this note")
+	}
+	if !write && !canReadFolder(folder, userID) {
+		return models.Note{}, apperror.Forbidden("access denied: you don't have permission to view this note")
+	}
+
+	return note, nil
+}
+
+func (s *attachmentService) allowedType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range s.limits.AllowedTypes {
+		if strings.EqualFold(strings.TrimSpace(allowed), mediaType) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *attachmentService) removeBlob(ctx context.Context, key string) {
+	if err := s.blobs.Delete(ctx, key); err != nil {
+		log.Error(ctx, "failed to delete attachment blob", "key", key, "error", err)
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"shared/pkg/apperror"
+
+	"github.com/google/uuid"
+)
+
+// fieldErrors collects invalid input field by field, keyed by the JSON name
+// of the offending field.
+type fieldErrors map[string]string
+
+func (e fieldErrors) add(field, message string) {
+	e[field] = message
+}
+
+// orNil returns an apperror validation error only when at least one field
+// failed.
+func (e fieldErrors) orNil() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return apperror.Validation("validation failed", e)
+}
+
+// parseID parses an ID received from a client; what names the resource in
+// the error message.
+func parseID(id, what string) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, apperror.BadRequest("invalid %s ID", what)
+	}
+	return parsed, nil
+}
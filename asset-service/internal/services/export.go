@@ -0,0 +1,196 @@
+package services
+
+import (
+	"archive/zip"
+	"asset-service/internal/models"
+	"asset-service/internal/repository"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"shared/pkg/apperror"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ExportFormatZip = "zip"
+
+	exportBatchSize       = 100
+	exportManifestVersion = 1
+	exportNotesDir        = "notes"
+	exportManifestName    = "manifest.json"
+)
+
+type ExportService interface {
+	ExportFolder(ctx context.Context, id string, userID uuid.UUID) (*FolderExport, error)
+}
+
+type exportService struct {
+	noteRepo   repository.NoteRepository
+	folderRepo repository.FolderRepository
+}
+
+func NewExportService(noteRepo repository.NoteRepository, folderRepo repository.FolderRepository) ExportService {
+	return &exportService{noteRepo: noteRepo, folderRepo: folderRepo}
+}
+
+// ExportFolder checks that the user may read the folder and returns an export
+// that can be written afterwards. Splitting the two lets handlers report
+// access errors before any archive bytes are sent.
+func (s *exportService) ExportFolder(ctx context.Context, id string, userID uuid.UUID) (*FolderExport, error) {
+	folderID, err := parseID(id, "folder")
+	if err != nil {
+		return nil, err
+	}
+
+	folder, err := s.folderRepo.GetFolderWithSharings(ctx, folderID)
+	if err != nil {
+		return nil, apperror.Lookup(err, "folder")
+	}
+
+	if !canReadFolder(folder, userID) {
+		return nil, apperror.Forbidden("access denied: you don't have permission to export this folder")
+	}
+
+	return &FolderExport{Folder: folder, notes: s.noteRepo}, nil
+}
+
+// FolderExport streams a folder as a ZIP archive: one Markdown file per note
+// under notes/ and a manifest.json describing the folder and every file.
+type FolderExport struct {
+	Folder *models.Folder
+	notes  repository.NoteRepository
+}
+
+// FileName is the suggested name for the downloaded archive.
+func (e *FolderExport) FileName() string {
+	return strings.TrimSuffix(markdownFileName(e.Folder.Name), ".md") + ".zip"
+}
+
+type exportManifest struct {
+	Version    int                  `json:"version"`
+	ExportedAt time.Time            `json:"exportedAt"`
+	Folder     exportManifestFolder `json:"folder"`
+	Notes      []exportManifestNote `json:"notes"`
+}
+
+type exportManifestFolder struct {
+	ID          uuid.UUID       `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Color       string          `json:"color"`
+	Metadata    models.Metadata `json:"metadata"`
+	CreatedAt   time.Time       `json:"createdAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+}
+
+type exportManifestNote struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	File      string    `json:"file"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Tags      []string  `json:"tags"`
+}
+
+// WriteZip writes the archive to w. Notes are read in batches and written as
+// they arrive, so only the manifest entries are kept in memory.
+func (e *FolderExport) WriteZip(ctx context.Context, w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	manifest := exportManifest{
+		Version:    exportManifestVersion,
+		ExportedAt: time.Now().UTC(),
+		Folder: exportManifestFolder{
+			ID:          e.Folder.ID,
+			Name:        e.Folder.Name,
+			Description: e.Folder.Description,
+			Color:       e.Folder.Color,
+			Metadata:    e.Folder.Metadata,
+			CreatedAt:   e.Folder.CreatedAt.UTC(),
+			UpdatedAt:   e.Folder.UpdatedAt.UTC(),
+		},
+		Notes: []exportManifestNote{},
+	}
+	usedNames := map[string]int{}
+
+	err := e.notes.EachNoteInFolder(ctx, e.Folder.ID, exportBatchSize, func(notes []models.Note) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		for _, note := range notes {
+			file := path.Join(exportNotesDir, uniqueFileName(markdownFileName(note.Name), usedNames))
+
+			body, err := renderNoteMarkdown(note)
+			if err != nil {
+				return fmt.Errorf("failed to render note %s: %w", note.ID, err)
+			}
+			if err := writeZipEntry(zw, file, note.UpdatedAt, body); err != nil {
+				return err
+			}
+
+			tags := make([]string, len(note.Tags))
+			for i, tag := range note.Tags {
+				tags[i] = tag.Name
+			}
+			manifest.Notes = append(manifest.Notes, exportManifestNote{
+				ID:        note.ID,
+				Name:      note.Name,
+				File:      file,
+				CreatedAt: note.CreatedAt.UTC(),
+				UpdatedAt: note.UpdatedAt.UTC(),
+				Tags:      tags,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export notes: %w", err)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeZipEntry(zw, exportManifestName, manifest.ExportedAt, data); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func writeZipEntry(zw *zip.Writer, name string, modified time.Time, data []byte) error {
+	entry, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add %s to archive: %w", name, err)
+	}
+	if _, err := entry.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// uniqueFileName appends " (2)", " (3)", ... to names already used in the
+// archive. Names are compared case-insensitively so the archive also unpacks
+// cleanly on case-insensitive file systems.
+func uniqueFileName(name string, used map[string]int) string {
+	key := strings.ToLower(name)
+	used[key]++
+	if used[key] == 1 {
+		return name
+	}
+
+	ext := path.Ext(name)
+	candidate := strings.TrimSuffix(name, ext) + " (" + strconv.Itoa(used[key]) + ")" + ext
+	return uniqueFileName(candidate, used)
+}
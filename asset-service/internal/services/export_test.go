@@ -0,0 +1,87 @@
+package services
+
+import (
+	"archive/zip"
+	"asset-service/internal/models"
+	"asset-service/internal/repository"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// batchedNoteRepo serves EachNoteInFolder from memory; other methods are not
+// used by exports.
+type batchedNoteRepo struct {
+	repository.NoteRepository
+	notes []models.Note
+}
+
+func (r *batchedNoteRepo) EachNoteInFolder(_ context.Context, _ uuid.UUID, batchSize int, fn func([]models.Note) error) error {
+	for start := 0; start < len(r.notes); start += batchSize {
+		end := min(start+batchSize, len(r.notes))
+		if err := fn(r.notes[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestFolderExport_WritesMarkdownAndManifest(t *testing.T) {
+	folder := &models.Folder{ID: uuid.New(), Name: "Team/Plans"}
+	notes := &batchedNoteRepo{notes: []models.Note{
+		{ID: uuid.New(), Name: "Roadmap", Content: "# Roadmap", FolderID: folder.ID, Tags: []models.Tag{{Name: "q3"}}},
+		{ID: uuid.New(), Name: "roadmap", Content: "duplicate name", FolderID: folder.ID},
+	}}
+	export := &FolderExport{Folder: folder, notes: notes}
+
+	var buf bytes.Buffer
+	if err := export.WriteZip(context.Background(), &buf); err != nil {
+		t.Fatalf("WriteZip failed: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Failed to read archive: %v", err)
+	}
+
+	files := map[string]string{}
+	for _, f := range archive.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", f.Name, err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+
+	first, ok := files["notes/Roadmap.md"]
+	if !ok {
+		t.Fatalf("Expected notes/Roadmap.md in archive, got %v", archive.File)
+	}
+	if !strings.HasPrefix(first, "---\nid: "+notes.notes[0].ID.String()) || !strings.Contains(first, "- q3") {
+		t.Errorf("Expected front-matter with id and tags, got:\n%s", first)
+	}
+	if !strings.HasSuffix(first, "---\n\n# Roadmap\n") {
+		t.Errorf("Expected content after front-matter, got:\n%s", first)
+	}
+	if _, ok := files["notes/roadmap (2).md"]; !ok {
+		t.Errorf("Expected clashing name to be numbered, got files %v", files)
+	}
+
+	var manifest exportManifest
+	if err := json.Unmarshal([]byte(files["manifest.json"]), &manifest); err != nil {
+		t.Fatalf("Failed to parse manifest: %v", err)
+	}
+	if manifest.Folder.ID != folder.ID || len(manifest.Notes) != 2 {
+		t.Errorf("Unexpected manifest: %+v", manifest)
+	}
+	if export.FileName() != "Team-Plans.zip" {
+		t.Errorf("Expected sanitized archive name, got %q", export.FileName())
+	}
+}
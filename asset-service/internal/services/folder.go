@@ -3,16 +3,25 @@ package services
 import (
 	"asset-service/internal/models"
 	"asset-service/internal/repository"
+	"context"
 	"fmt"
+	"regexp"
+	"shared/pkg/apperror"
+	"shared/pkg/listing"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
 
 type FolderService interface {
-	CreateFolder(name string, userID uuid.UUID) (any, error)
-	GetFolderByID(id string, userID uuid.UUID) (any, error)
-	ListFolders(userID uuid.UUID) ([]any, error)
-	DeleteFolder(id string, userID uuid.UUID) error
+	CreateFolder(ctx context.Context, name string, userID uuid.UUID) (any, error)
+	GetFolderByID(ctx context.Context, id string, userID uuid.UUID) (any, error)
+	ListFolders(ctx context.Context, userID uuid.UUID, q models.ListQuery) ([]any, *listing.Cursor, error)
+	UpdateFolder(ctx context.Context, id string, update models.FolderUpdate, userID uuid.UUID) (any, error)
+	DuplicateFolder(ctx context.Context, id string, name string, userID uuid.UUID) (any, error)
+	DeleteFolder(ctx context.Context, id string, userID uuid.UUID) error
+	TransferOwnership(ctx context.Context, fromID, toID uuid.UUID) (int64, error)
 }
 
 type folderService struct {
@@ -23,7 +32,7 @@ func NewFolderService(repo repository.FolderRepository) FolderService {
 	return &folderService{repo: repo}
 }
 
-func (s *folderService) CreateFolder(name string, userID uuid.UUID) (any, error) {
+func (s *folderService) CreateFolder(ctx context.Context, name string, userID uuid.UUID) (any, error) {
 	folder := &models.Folder{
 		Name:      name,
 		OwnerID:   userID,
@@ -31,7 +40,7 @@ func (s *folderService) CreateFolder(name string, userID uuid.UUID) (any, error)
 		UpdatedBy: userID,
 	}
 
-	err := s.repo.CreateFolder(folder)
+	err := s.repo.CreateFolder(ctx, folder)
 	if err != nil {
 		return nil, err
 	}
@@ -39,39 +48,28 @@ func (s *folderService) CreateFolder(name string, userID uuid.UUID) (any, error)
 	return folder, nil
 }
 
-func (s *folderService) GetFolderByID(id string, userID uuid.UUID) (any, error) {
-	folderID, err := uuid.Parse(id)
+func (s *folderService) GetFolderByID(ctx context.Context, id string, userID uuid.UUID) (any, error) {
+	folderID, err := parseID(id, "folder")
 	if err != nil {
 		return nil, err
 	}
 
-	folder, err := s.repo.GetFolderByID(folderID)
+	folder, err := s.repo.GetFolderByID(ctx, folderID)
 	if err != nil {
-		return nil, err
-	}
-
-	// Check if user owns the folder or has it shared with them
-	hasAccess := folder.OwnerID == userID
-	if !hasAccess {
-		for _, sharing := range folder.Sharings {
-			if sharing.UserID == userID {
-				hasAccess = true
-				break
-			}
-		}
+		return nil, apperror.Lookup(err, "folder")
 	}
 
-	if !hasAccess {
-		return nil, fmt.Errorf("access denied: you don't have permission to view this folder")
+	if !canReadFolder(folder, userID) {
+		return nil, apperror.Forbidden("access denied: you don't have permission to view this folder")
 	}
 
 	return folder, nil
 }
 
-func (s *folderService) ListFolders(userID uuid.UUID) ([]any, error) {
-	folders, err := s.repo.ListFoldersByOwnerOrShared(userID)
+func (s *folderService) ListFolders(ctx context.Context, userID uuid.UUID, q models.ListQuery) ([]any, *listing.Cursor, error) {
+	folders, cursor, err := s.repo.ListFoldersByOwnerOrShared(ctx, userID, q)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	result := make([]any, len(folders))
@@ -79,24 +77,182 @@ func (s *folderService) ListFolders(userID uuid.UUID) ([]any, error) {
 		result[i] = folder
 	}
 
-	return result, nil
+	return result, cursor, nil
 }
 
-func (s *folderService) DeleteFolder(id string, userID uuid.UUID) error {
-	folderID, err := uuid.Parse(id)
+func (s *folderService) DeleteFolder(ctx context.Context, id string, userID uuid.UUID) error {
+	folderID, err := parseID(id, "folder")
 	if err != nil {
 		return err
 	}
 
 	// First check if user owns the folder
-	folder, err := s.repo.GetFolderByID(folderID)
+	folder, err := s.repo.GetFolderByID(ctx, folderID)
 	if err != nil {
-		return err
+		return apperror.Lookup(err, "folder")
 	}
 
 	if folder.OwnerID != userID {
-		return fmt.Errorf("only the folder owner can delete this folder")
+		return apperror.Forbidden("only the folder owner can delete this folder")
 	}
 
-	return s.repo.DeleteFolder(folderID)
+	return s.repo.DeleteFolder(ctx, folderID, userID)
+}
+
+// DuplicateFolder copies a folder and all of its notes into a new folder owned
+// by the caller. Sharings are not copied.
+func (s *folderService) DuplicateFolder(ctx context.Context, id string, name string, userID uuid.UUID) (any, error) {
+	folderID, err := parseID(id, "folder")
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := s.repo.GetFolderByID(ctx, folderID)
+	if err != nil {
+		return nil, apperror.Lookup(err, "folder")
+	}
+
+	if !canWriteFolder(source, userID) {
+		return nil, apperror.Forbidden("access denied: you don't have write permission for this folder")
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = source.Name + " (copy)"
+	}
+	if utf8.RuneCountInString(name) > maxFolderNameLength {
+		return nil, apperror.Validation("invalid folder", map[string]string{
+			"name": fmt.Sprintf("cannot exceed %d characters", maxFolderNameLength),
+		})
+	}
+
+	folder := &models.Folder{
+		Name:        name,
+		Description: source.Description,
+		Color:       source.Color,
+		Metadata:    source.Metadata,
+		OwnerID:     userID,
+		CreatedBy:   userID,
+		UpdatedBy:   userID,
+	}
+
+	notes := make([]models.Note, len(source.Notes))
+	for i, note := range source.Notes {
+		notes[i] = models.Note{
+			Name:      note.Name,
+			Content:   note.Content,
+			CreatedBy: userID,
+			UpdatedBy: userID,
+		}
+	}
+
+	if err := s.repo.DuplicateFolder(ctx, folder, notes); err != nil {
+		return nil, fmt.Errorf("failed to duplicate folder: %w", err)
+	}
+
+	return folder, nil
+}
+
+const (
+	maxFolderNameLength    = 255
+	maxFolderDescLength    = 4096
+	maxMetadataEntries     = 50
+	maxMetadataKeyLength   = 64
+	maxMetadataValueLength = 1024
+)
+
+var folderColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+func (s *folderService) UpdateFolder(ctx context.Context, id string, update models.FolderUpdate, userID uuid.UUID) (any, error) {
+	folderID, err := parseID(id, "folder")
+	if err != nil {
+		return nil, err
+	}
+
+	folder, err := s.repo.GetFolderByID(ctx, folderID)
+	if err != nil {
+		return nil, apperror.Lookup(err, "folder")
+	}
+
+	if !canWriteFolder(folder, userID) {
+		return nil, apperror.Forbidden("access denied: you don't have write permission for this folder")
+	}
+
+	changes, err := folderChanges(update)
+	if err != nil {
+		return nil, err
+	}
+	changes["updated_by"] = userID
+
+	if err := s.repo.UpdateFolder(ctx, folderID, changes); err != nil {
+		return nil, fmt.Errorf("failed to update folder: %w", err)
+	}
+
+	return s.repo.GetFolderByID(ctx, folderID)
+}
+
+// folderChanges validates the update and converts it into a column map.
+func folderChanges(update models.FolderUpdate) (map[string]any, error) {
+	verr := fieldErrors{}
+	changes := map[string]any{}
+
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		switch {
+		case name == "":
+			verr.add("name", "cannot be empty")
+		case utf8.RuneCountInString(name) > maxFolderNameLength:
+			verr.add("name", fmt.Sprintf("cannot exceed %d characters", maxFolderNameLength))
+		default:
+			changes["name"] = name
+		}
+	}
+
+	if update.Description != nil {
+		if utf8.RuneCountInString(*update.Description) > maxFolderDescLength {
+			verr.add("description", fmt.Sprintf("cannot exceed %d characters", maxFolderDescLength))
+		} else {
+			changes["description"] = *update.Description
+		}
+	}
+
+	if update.Color != nil {
+		if *update.Color != "" && !folderColorPattern.MatchString(*update.Color) {
+			verr.add("color", "must be a hex value such as #1a2b3c")
+		} else {
+			changes["color"] = strings.ToLower(*update.Color)
+		}
+	}
+
+	if update.Metadata != nil {
+		metadata := *update.Metadata
+		if len(metadata) > maxMetadataEntries {
+			verr.add("metadata", fmt.Sprintf("cannot have more than %d entries", maxMetadataEntries))
+		}
+		for key, value := range metadata {
+			if key == "" || utf8.RuneCountInString(key) > maxMetadataKeyLength {
+				verr.add("metadata", fmt.Sprintf("keys must be between 1 and %d characters", maxMetadataKeyLength))
+			} else if utf8.RuneCountInString(value) > maxMetadataValueLength {
+				verr.add("metadata."+key, fmt.Sprintf("cannot exceed %d characters", maxMetadataValueLength))
+			}
+		}
+		if metadata == nil {
+			metadata = models.Metadata{}
+		}
+		changes["metadata"] = metadata
+	}
+
+	if err := verr.orNil(); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// TransferOwnership moves all of fromID's folders, with their notes, to toID.
+// It is meant for offboarding and is not exposed over HTTP.
+func (s *folderService) TransferOwnership(ctx context.Context, fromID, toID uuid.UUID) (int64, error) {
+	if fromID == toID {
+		return 0, apperror.BadRequest("cannot transfer folders to their current owner")
+	}
+	return s.repo.TransferOwnership(ctx, fromID, toID)
 }
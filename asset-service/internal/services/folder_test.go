@@ -0,0 +1,155 @@
+package services
+
+import (
+	"asset-service/internal/models"
+	"asset-service/internal/repository"
+	"context"
+	"errors"
+	"shared/pkg/apperror"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// memoryFolderRepo keeps folders in memory and records the last changes
+// applied to them.
+type memoryFolderRepo struct {
+	repository.FolderRepository
+	folders map[uuid.UUID]*models.Folder
+	changes map[string]any
+}
+
+func (r *memoryFolderRepo) GetFolderByID(_ context.Context, id uuid.UUID) (*models.Folder, error) {
+	if f, ok := r.folders[id]; ok {
+		return f, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryFolderRepo) GetFolderWithSharings(ctx context.Context, id uuid.UUID) (*models.Folder, error) {
+	return r.GetFolderByID(ctx, id)
+}
+
+func (r *memoryFolderRepo) UpdateFolder(_ context.Context, _ uuid.UUID, changes map[string]any) error {
+	r.changes = changes
+	return nil
+}
+
+// sharedFolder returns a repository holding one folder that owner shares
+// with writer for writing and with reader for reading.
+func sharedFolder() (repo *memoryFolderRepo, folder *models.Folder, owner, writer, reader uuid.UUID) {
+	owner, writer, reader = uuid.New(), uuid.New(), uuid.New()
+	folder = &models.Folder{ID: uuid.New(), Name: "Plans", OwnerID: owner, Sharings: []models.FolderSharing{
+		{UserID: writer, Permission: models.PermissionWrite},
+		{UserID: reader, Permission: models.PermissionRead},
+	}}
+	repo = &memoryFolderRepo{folders: map[uuid.UUID]*models.Folder{folder.ID: folder}}
+	return repo, folder, owner, writer, reader
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestFolderChanges_Validation(t *testing.T) {
+	tests := []struct {
+		name       string
+		update     models.FolderUpdate
+		wantField  string
+		wantChange map[string]any
+	}{
+		{name: "trims name", update: models.FolderUpdate{Name: ptr("  Plans ")}, wantChange: map[string]any{"name": "Plans"}},
+		{name: "blank name", update: models.FolderUpdate{Name: ptr("   ")}, wantField: "name"},
+		{name: "name counts characters", update: models.FolderUpdate{Name: ptr(strings.Repeat("é", maxFolderNameLength))}, wantChange: map[string]any{"name": strings.Repeat("é", maxFolderNameLength)}},
+		{name: "long name", update: models.FolderUpdate{Name: ptr(strings.Repeat("a", maxFolderNameLength+1))}, wantField: "name"},
+		{name: "long description", update: models.FolderUpdate{Description: ptr(strings.Repeat("a", maxFolderDescLength+1))}, wantField: "description"},
+		{name: "lowercases colour", update: models.FolderUpdate{Color: ptr("#1A2B3C")}, wantChange: map[string]any{"color": "#1a2b3c"}},
+		{name: "clears colour", update: models.FolderUpdate{Color: ptr("")}, wantChange: map[string]any{"color": ""}},
+		{name: "invalid colour", update: models.FolderUpdate{Color: ptr("red")}, wantField: "color"},
+		{name: "empty metadata key", update: models.FolderUpdate{Metadata: &models.Metadata{"": "x"}}, wantField: "metadata"},
+		{name: "long metadata value", update: models.FolderUpdate{Metadata: &models.Metadata{"k": strings.Repeat("a", maxMetadataValueLength+1)}}, wantField: "metadata.k"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := folderChanges(tt.update)
+			if tt.wantField != "" {
+				var appErr *apperror.Error
+				if !errors.As(err, &appErr) || appErr.Kind != apperror.KindValidation || appErr.Fields[tt.wantField] == "" {
+					t.Errorf("Expected %s to be rejected, got %v", tt.wantField, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected the update to be valid, got %v", err)
+			}
+			for column, want := range tt.wantChange {
+				if changes[column] != want {
+					t.Errorf("Expected %s to be %q, got %q", column, want, changes[column])
+				}
+			}
+		})
+	}
+}
+
+func TestFolderService_UpdateNeedsWriteAccess(t *testing.T) {
+	repo, folder, owner, writer, reader := sharedFolder()
+	svc := NewFolderService(repo)
+	ctx := context.Background()
+	update := models.FolderUpdate{Name: ptr("Roadmap")}
+
+	for _, userID := range []uuid.UUID{owner, writer} {
+		repo.changes = nil
+		if _, err := svc.UpdateFolder(ctx, folder.ID.String(), update, userID); err != nil {
+			t.Fatalf("Expected the update to succeed, got %v", err)
+		}
+		if repo.changes["name"] != "Roadmap" || repo.changes["updated_by"] != userID {
+			t.Errorf("Expected the rename to be recorded for %s, got %v", userID, repo.changes)
+		}
+	}
+
+	repo.changes = nil
+	_, err := svc.UpdateFolder(ctx, folder.ID.String(), update, reader)
+	assertKind(t, err, apperror.KindForbidden)
+	_, err = svc.UpdateFolder(ctx, uuid.NewString(), update, owner)
+	assertKind(t, err, apperror.KindNotFound)
+	if repo.changes != nil {
+		t.Errorf("Expected no changes to be applied, got %v", repo.changes)
+	}
+}
+
+func (r *memoryFolderRepo) DuplicateFolder(_ context.Context, folder *models.Folder, notes []models.Note) error {
+	folder.ID = uuid.New()
+	folder.Notes = notes
+	r.folders[folder.ID] = folder
+	return nil
+}
+
+func TestFolderService_Duplicate(t *testing.T) {
+	repo, folder, _, writer, reader := sharedFolder()
+	folder.Color = "#1a2b3c"
+	folder.Notes = []models.Note{{Name: "Roadmap", Content: "# Roadmap", CreatedBy: folder.OwnerID}}
+	svc := NewFolderService(repo)
+	ctx := context.Background()
+
+	_, err := svc.DuplicateFolder(ctx, folder.ID.String(), "", reader)
+	assertKind(t, err, apperror.KindForbidden)
+	_, err = svc.DuplicateFolder(ctx, folder.ID.String(), strings.Repeat("a", maxFolderNameLength+1), writer)
+	assertKind(t, err, apperror.KindValidation)
+
+	result, err := svc.DuplicateFolder(ctx, folder.ID.String(), "  ", writer)
+	if err != nil {
+		t.Fatalf("Expected the writer to duplicate the folder, got %v", err)
+	}
+	copied := result.(*models.Folder)
+	if copied.Name != "Plans (copy)" || copied.OwnerID != writer || copied.Color != folder.Color {
+		t.Errorf("Expected a copy named after the source and owned by the writer, got %+v", copied)
+	}
+	if len(copied.Notes) != 1 || copied.Notes[0].Content != "# Roadmap" || copied.Notes[0].CreatedBy != writer {
+		t.Errorf("Expected the notes to be copied for the writer, got %+v", copied.Notes)
+	}
+	if len(copied.Sharings) != 0 {
+		t.Errorf("Expected sharings not to be copied, got %+v", copied.Sharings)
+	}
+}
@@ -0,0 +1,239 @@
+package services
+
+import (
+	"archive/zip"
+	"asset-service/internal/models"
+	"asset-service/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"shared/pkg/apperror"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// ErrImportRolledBack is returned with the report when an atomic import had
+// at least one failing file and nothing was saved.
+var ErrImportRolledBack = apperror.Validation("import failed; no notes were created", nil)
+
+type ImportLimits struct {
+	// MaxFiles caps the number of Markdown files per import, counting the
+	// entries of ZIP archives.
+	MaxFiles int
+}
+
+// ImportFile is an uploaded .md file or .zip archive.
+type ImportFile struct {
+	Name string
+	Size int64
+	Body io.ReaderAt
+}
+
+type ImportService interface {
+	ImportNotes(ctx context.Context, folderID string, files []ImportFile, atomic bool, userID uuid.UUID) (*models.ImportReport, error)
+}
+
+type importService struct {
+	notes      NoteService
+	folderRepo repository.FolderRepository
+	tx         repository.Transactor
+	limits     ImportLimits
+}
+
+func NewImportService(notes NoteService, folderRepo repository.FolderRepository, tx repository.Transactor, limits ImportLimits) ImportService {
+	return &importService{notes: notes, folderRepo: folderRepo, tx: tx, limits: limits}
+}
+
+// importEntry is a single Markdown document waiting to be imported.
+type importEntry struct {
+	name string
+	open func() (io.ReadCloser, error)
+}
+
+// ImportNotes creates a note for every Markdown file in files. In atomic mode
+// all notes are created in one transaction that is rolled back if any file
+// fails; otherwise each file succeeds or fails on its own.
+func (s *importService) ImportNotes(ctx context.Context, folderID string, files []ImportFile, atomic bool, userID uuid.UUID) (*models.ImportReport, error) {
+	id, err := parseID(folderID, "folder")
+	if err != nil {
+		return nil, err
+	}
+
+	folder, err := s.folderRepo.GetFolderWithSharings(ctx, id)
+	if err != nil {
+		return nil, apperror.Lookup(err, "folder")
+	}
+	if !canWriteFolder(folder, userID) {
+		return nil, apperror.Forbidden("access denied: you don't have write permission for this folder")
+	}
+
+	report := &models.ImportReport{FolderID: folder.ID, Atomic: atomic, Results: []models.ImportResult{}}
+
+	entries, err := s.collectEntries(files, report)
+	if err != nil {
+		return nil, err
+	}
+
+	if !atomic {
+		s.importEntries(ctx, s.notes, entries, folder.ID, userID, report)
+		return report, nil
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(repos repository.Repositories) error {
+		s.importEntries(ctx, NewNoteService(repos.Notes, repos.Folders), entries, folder.ID, userID, report)
+		if report.Failed > 0 {
+			return ErrImportRolledBack
+		}
+		return nil
+	})
+	if err != nil {
+		for i := range report.Results {
+			if report.Results[i].Status == models.ImportStatusCreated {
+				report.Results[i].Status = models.ImportStatusRolledBack
+				report.Results[i].NoteID = nil
+			}
+		}
+		report.Created = 0
+		if !errors.Is(err, ErrImportRolledBack) {
+			return report, fmt.Errorf("failed to import notes: %w", err)
+		}
+		return report, ErrImportRolledBack
+	}
+
+	return report, nil
+}
+
+// collectEntries expands the uploads into Markdown entries without reading
+// their content. Unsupported files and broken archives are recorded in the
+// report straight away.
+func (s *importService) collectEntries(files []ImportFile, report *models.ImportReport) ([]importEntry, error) {
+	var entries []importEntry
+
+	for _, file := range files {
+		switch strings.ToLower(path.Ext(file.Name)) {
+		case ".md", ".markdown":
+			body := io.NewSectionReader(file.Body, 0, file.Size)
+			entries = append(entries, importEntry{
+				name: file.Name,
+				open: func() (io.ReadCloser, error) { return io.NopCloser(body), nil },
+			})
+
+		case ".zip":
+			archive, err := zip.NewReader(file.Body, file.Size)
+			if err != nil {
+				addImportResult(report, models.ImportResult{File: file.Name, Status: models.ImportStatusFailed, Error: "invalid ZIP archive: " + err.Error()})
+				continue
+			}
+
+			for _, f := range archive.File {
+				name := file.Name + "/" + f.Name
+				if f.FileInfo().IsDir() || isHiddenPath(f.Name) {
+					continue
+				}
+				ext := strings.ToLower(path.Ext(f.Name))
+				if ext != ".md" && ext != ".markdown" {
+					addImportResult(report, models.ImportResult{File: name, Status: models.ImportStatusSkipped, Error: "not a Markdown file"})
+					continue
+				}
+				entries = append(entries, importEntry{name: name, open: f.Open})
+			}
+
+		default:
+			addImportResult(report, models.ImportResult{File: file.Name, Status: models.ImportStatusSkipped, Error: "unsupported file type; expected .md or .zip"})
+		}
+
+		if len(entries) > s.limits.MaxFiles {
+			return nil, apperror.TooLarge("cannot import more than %d files at once", s.limits.MaxFiles)
+		}
+	}
+
+	return entries, nil
+}
+
+func (s *importService) importEntries(ctx context.Context, notes NoteService, entries []importEntry, folderID, userID uuid.UUID, report *models.ImportReport) {
+	for _, entry := range entries {
+		result := models.ImportResult{File: entry.name}
+
+		name, content, err := readImportEntry(entry)
+		switch {
+		case err != nil:
+			result.Status, result.Error = models.ImportStatusFailed, err.Error()
+		case report.Atomic && report.Failed > 0:
+			// The transaction is going to be rolled back, and after a
+			// database error Postgres rejects further statements anyway.
+			result.Status, result.NoteName = models.ImportStatusRolledBack, name
+		default:
+			note, err := notes.CreateNote(ctx, name, content, folderID, userID)
+			if err != nil {
+				result.Status, result.Error = models.ImportStatusFailed, err.Error()
+			} else {
+				result.Status, result.NoteID, result.NoteName = models.ImportStatusCreated, &note.ID, note.Name
+			}
+		}
+
+		addImportResult(report, result)
+	}
+}
+
+// readImportEntry reads and parses a Markdown entry. The note name comes
+// from the front-matter title, falling back to the file name.
+func readImportEntry(entry importEntry) (name, content string, err error) {
+	rc, err := entry.open()
+	if err != nil {
+		return "", "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxNoteContentLength+1))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read file: %w", err)
+	}
+	if len(data) > maxNoteContentLength {
+		return "", "", fmt.Errorf("file exceeds %d bytes", maxNoteContentLength)
+	}
+	if !utf8.Valid(data) {
+		return "", "", errors.New("file is not valid UTF-8 text")
+	}
+
+	title, content, err := parseNoteMarkdown(data)
+	if err != nil {
+		return "", "", err
+	}
+
+	if title == "" {
+		base := path.Base(entry.name)
+		title = strings.TrimSpace(strings.TrimSuffix(base, path.Ext(base)))
+	}
+	if runes := []rune(title); len(runes) > maxNoteNameLength {
+		title = strings.TrimSpace(string(runes[:maxNoteNameLength]))
+	}
+
+	return title, content, nil
+}
+
+func addImportResult(report *models.ImportReport, result models.ImportResult) {
+	switch result.Status {
+	case models.ImportStatusCreated:
+		report.Created++
+	case models.ImportStatusFailed:
+		report.Failed++
+	case models.ImportStatusSkipped:
+		report.Skipped++
+	}
+	report.Results = append(report.Results, result)
+}
+
+// isHiddenPath reports whether any path element starts with a dot or is the
+// "__MACOSX" resource fork directory that macOS adds to archives.
+func isHiddenPath(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") || part == "__MACOSX" {
+			return true
+		}
+	}
+	return false
+}
@@ -3,13 +3,14 @@ package services
 import (
 	"asset-service/internal/models"
 	"asset-service/internal/repository"
+	"context"
 
 	"github.com/google/uuid"
 )
 
 type ManagerService interface {
-	GetTeamAssets(teamID uuid.UUID, managerID uuid.UUID) ([]models.Folder, error)
-	GetUserAssets(userID uuid.UUID, managerID uuid.UUID) ([]models.Folder, error)
+	GetTeamAssets(ctx context.Context, teamID uuid.UUID, managerID uuid.UUID) ([]models.Folder, error)
+	GetUserAssets(ctx context.Context, userID uuid.UUID, managerID uuid.UUID) ([]models.Folder, error)
 }
 
 type managerService struct {
@@ -28,7 +29,7 @@ func NewManagerService(folderRepo repository.FolderRepository, sharingRepo repos
 // 1. A team repository to verify that the manager belongs to the team
 // 2. A user repository to get team members
 // 3. Logic to fetch all assets owned or shared with team members
-func (s *managerService) GetTeamAssets(teamID uuid.UUID, managerID uuid.UUID) ([]models.Folder, error) {
+func (s *managerService) GetTeamAssets(ctx context.Context, teamID uuid.UUID, managerID uuid.UUID) ([]models.Folder, error) {
 	// TODO: Implement team asset listing
 	// This would involve:
 	// 1. Verify manager is part of the team
@@ -41,7 +42,7 @@ func (s *managerService) GetTeamAssets(teamID uuid.UUID, managerID uuid.UUID) ([
 	return []models.Folder{}, nil
 }
 
-func (s *managerService) GetUserAssets(userID uuid.UUID, managerID uuid.UUID) ([]models.Folder, error) {
+func (s *managerService) GetUserAssets(ctx context.Context, userID uuid.UUID, managerID uuid.UUID) ([]models.Folder, error) {
 	// TODO: Implement user asset listing for managers
 	// This would involve:
 	// 1. Verify manager has permission to view this user's assets
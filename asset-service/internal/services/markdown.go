@@ -0,0 +1,121 @@
+package services
+
+import (
+	"asset-service/internal/models"
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+const frontMatterDelimiter = "---"
+
+// noteFrontMatter is the YAML header written at the top of exported Markdown
+// files.
+type noteFrontMatter struct {
+	ID        uuid.UUID `yaml:"id"`
+	Title     string    `yaml:"title"`
+	FolderID  uuid.UUID `yaml:"folderId"`
+	CreatedAt time.Time `yaml:"createdAt"`
+	UpdatedAt time.Time `yaml:"updatedAt"`
+	Tags      []string  `yaml:"tags"`
+}
+
+// renderNoteMarkdown returns the note as a Markdown document with YAML
+// front-matter followed by the note content.
+func renderNoteMarkdown(note models.Note) ([]byte, error) {
+	tags := make([]string, len(note.Tags))
+	for i, tag := range note.Tags {
+		tags[i] = tag.Name
+	}
+
+	header, err := yaml.Marshal(noteFrontMatter{
+		ID:        note.ID,
+		Title:     note.Name,
+		FolderID:  note.FolderID,
+		CreatedAt: note.CreatedAt.UTC(),
+		UpdatedAt: note.UpdatedAt.UTC(),
+		Tags:      tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(frontMatterDelimiter + "\n")
+	buf.Write(header)
+	buf.WriteString(frontMatterDelimiter + "\n\n")
+	buf.WriteString(note.Content)
+	if note.Content != "" && !strings.HasSuffix(note.Content, "\n") {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+const maxFileNameLength = 100
+
+// markdownFileName turns a note name into a file name that is safe on common
+// file systems, e.g. "Q3 plan / draft?" becomes "Q3 plan - draft-.md".
+func markdownFileName(name string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case strings.ContainsRune(`/\:*?"<>|`, r), unicode.IsControl(r):
+			return '-'
+		}
+		return r
+	}, name)
+
+	cleaned = strings.Trim(strings.TrimSpace(cleaned), ".")
+	if runes := []rune(cleaned); len(runes) > maxFileNameLength {
+		cleaned = strings.TrimSpace(string(runes[:maxFileNameLength]))
+	}
+	if cleaned == "" {
+		cleaned = "untitled"
+	}
+	return cleaned + ".md"
+}
+
+// parseNoteMarkdown splits a Markdown document into its front-matter title
+// and body. Documents without front-matter are returned unchanged with an
+// empty title; "name" is accepted as an alias for "title".
+func parseNoteMarkdown(data []byte) (title, body string, err error) {
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	if !strings.HasPrefix(text, frontMatterDelimiter+"\n") {
+		return "", text, nil
+	}
+
+	rest := text[len(frontMatterDelimiter)+1:]
+	end := strings.Index(rest, "\n"+frontMatterDelimiter+"\n")
+	header := ""
+	switch {
+	case strings.HasPrefix(rest, frontMatterDelimiter+"\n"):
+		rest = rest[len(frontMatterDelimiter)+1:]
+	case end >= 0:
+		header, rest = rest[:end], rest[end+len(frontMatterDelimiter)+2:]
+	case strings.HasSuffix(rest, "\n"+frontMatterDelimiter):
+		header, rest = strings.TrimSuffix(rest, "\n"+frontMatterDelimiter), ""
+	default:
+		// An opening delimiter without a closing one is just a horizontal rule.
+		return "", text, nil
+	}
+
+	var fm struct {
+		Title string `yaml:"title"`
+		Name  string `yaml:"name"`
+	}
+	if err := yaml.Unmarshal([]byte(header), &fm); err != nil {
+		return "", "", fmt.Errorf("invalid front-matter: %w", err)
+	}
+
+	title = fm.Title
+	if title == "" {
+		title = fm.Name
+	}
+	return strings.TrimSpace(title), strings.TrimPrefix(rest, "\n"), nil
+}
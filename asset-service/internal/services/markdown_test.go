@@ -0,0 +1,68 @@
+package services
+
+import (
+	"asset-service/internal/models"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestNoteMarkdown_RoundTrip(t *testing.T) {
+	note := models.Note{
+		ID:        uuid.New(),
+		Name:      "Weekly: sync",
+		Content:   "# Agenda\n\n---\n\n- item\n",
+		FolderID:  uuid.New(),
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	doc, err := renderNoteMarkdown(note)
+	if err != nil {
+		t.Fatalf("Failed to render note: %v", err)
+	}
+
+	title, body, err := parseNoteMarkdown(doc)
+	if err != nil {
+		t.Fatalf("Failed to parse note: %v", err)
+	}
+	if title != note.Name {
+		t.Errorf("Expected title %q, got %q", note.Name, title)
+	}
+	if body != note.Content {
+		t.Errorf("Expected body %q, got %q", note.Content, body)
+	}
+}
+
+func TestParseNoteMarkdown_WithoutFrontMatter(t *testing.T) {
+	for _, doc := range []string{"plain text", "---\nhorizontal rule without a closing delimiter"} {
+		title, body, err := parseNoteMarkdown([]byte(doc))
+		if err != nil || title != "" || body != doc {
+			t.Errorf("Expected %q to be returned as-is, got title=%q body=%q err=%v", doc, title, body, err)
+		}
+	}
+
+	if _, _, err := parseNoteMarkdown([]byte("---\ntitle: [unclosed\n---\nbody")); err == nil {
+		t.Error("Expected invalid front-matter to be rejected")
+	}
+}
+
+func TestReadImportEntry_FallsBackToFileName(t *testing.T) {
+	entry := importEntry{
+		name: "archive.zip/docs/Meeting notes.md",
+		open: func() (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader("---\ntags: [a]\n---\nhello")), nil
+		},
+	}
+
+	name, content, err := readImportEntry(entry)
+	if err != nil {
+		t.Fatalf("Expected entry to be read, got: %v", err)
+	}
+	if name != "Meeting notes" || content != "hello" {
+		t.Errorf("Unexpected result: name=%q content=%q", name, content)
+	}
+}
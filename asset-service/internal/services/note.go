@@ -3,17 +3,25 @@ package services
 import (
 	"asset-service/internal/models"
 	"asset-service/internal/repository"
+	"context"
 	"fmt"
+	"shared/pkg/apperror"
+	"shared/pkg/listing"
 
 	"github.com/google/uuid"
 )
 
 type NoteService interface {
-	CreateNote(name string, content string, folderId uuid.UUID, userID uuid.UUID) (*models.Note, error)
-	GetNote(id string, userID uuid.UUID) (models.Note, error)
-	ListNotes(userID uuid.UUID) ([]models.Note, error)
-	UpdateNote(id string, note any, userID uuid.UUID) (models.Note, error)
-	DeleteNote(id string, userID uuid.UUID) error
+	CreateNote(ctx context.Context, name string, content string, folderId uuid.UUID, userID uuid.UUID) (*models.Note, error)
+	ListFolderNotes(ctx context.Context, folderID string, q models.ListQuery, userID uuid.UUID) ([]models.NoteSummary, *listing.Cursor, error)
+	GetNote(ctx context.Context, id string, userID uuid.UUID) (models.Note, error)
+	ListNotes(ctx context.Context, userID uuid.UUID, q models.ListQuery) ([]models.Note, *listing.Cursor, error)
+	ListNotesByTags(ctx context.Context, userID uuid.UUID, tags []string, matchAll bool, q models.ListQuery) ([]models.Note, *listing.Cursor, error)
+	UpdateNote(ctx context.Context, id string, update models.NoteUpdate, userID uuid.UUID) (models.Note, error)
+	PatchNote(ctx context.Context, id string, format models.PatchFormat, patch []byte, userID uuid.UUID) (models.Note, error)
+	MoveNote(ctx context.Context, id string, targetFolderID uuid.UUID, userID uuid.UUID) (models.Note, error)
+	CopyNote(ctx context.Context, id string, targetFolderID uuid.UUID, userID uuid.UUID) (*models.Note, error)
+	DeleteNote(ctx context.Context, id string, userID uuid.UUID) error
 }
 
 type noteService struct {
@@ -25,92 +33,160 @@ func NewNoteService(noteRepo repository.NoteRepository, folderRepo repository.Fo
 	return &noteService{repo: noteRepo, folderRepo: folderRepo}
 }
 
-func (s *noteService) CreateNote(name string, content string, folderId uuid.UUID, userID uuid.UUID) (*models.Note, error) {
+func (s *noteService) CreateNote(ctx context.Context, name string, content string, folderId uuid.UUID, userID uuid.UUID) (*models.Note, error) {
 	if name == "" {
-		return nil, fmt.Errorf("note name cannot be empty")
+		return nil, apperror.Validation("invalid note", map[string]string{"noteName": "cannot be empty"})
+	}
+
+	folder, err := s.folder(ctx, folderId)
+	if err != nil {
+		return nil, err
+	}
+	if !canWriteFolder(folder, userID) {
+		return nil, apperror.Forbidden("access denied: you don't have write permission for this folder")
 	}
 
 	note := &models.Note{
-		Name:     name,
-		Content:  content,
-		FolderID: folderId,
+		Name:      name,
+		Content:   content,
+		FolderID:  folderId,
+		CreatedBy: userID,
+		UpdatedBy: userID,
 	}
 
-	if err := s.repo.CreateNote(note); err != nil {
+	if err := s.repo.CreateNote(ctx, note); err != nil {
 		return nil, err
 	}
 
 	return note, nil
 }
 
-func (s *noteService) ListNotes(userID uuid.UUID) ([]models.Note, error) {
-	notes, err := s.repo.ListNotesByUserAccess(userID.String())
+// ListFolderNotes returns one page of the folder's notes without their
+// content.
+func (s *noteService) ListFolderNotes(ctx context.Context, folderID string, q models.ListQuery, userID uuid.UUID) ([]models.NoteSummary, *listing.Cursor, error) {
+	id, err := parseID(folderID, "folder")
 	if err != nil {
-		return nil, fmt.Errorf("failed to list notes: %w", err)
+		return nil, nil, err
 	}
 
-	return notes, nil
+	folder, err := s.folder(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !canReadFolder(folder, userID) {
+		return nil, nil, apperror.Forbidden("access denied: you don't have permission to view this folder")
+	}
+
+	notes, cursor, err := s.repo.ListNoteSummaries(ctx, folder.ID, q)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list notes: %w", err)
+	}
+
+	return notes, cursor, nil
 }
 
-func (s *noteService) GetNote(id string, userID uuid.UUID) (models.Note, error) {
-	note, err := s.repo.GetNote(id)
+// getNote loads a note by the ID received from the client.
+func (s *noteService) getNote(ctx context.Context, id string) (models.Note, error) {
+	if _, err := parseID(id, "note"); err != nil {
+		return models.Note{}, err
+	}
+	note, err := s.repo.GetNote(ctx, id)
 	if err != nil {
-		return models.Note{}, fmt.Errorf("failed to get note: %w", err)
+		return models.Note{}, apperror.Lookup(err, "note")
 	}
+	return note, nil
+}
 
-	// Check if user has access to the folder containing this note
-	folder, err := s.folderRepo.GetFolderByID(note.FolderID)
+// folder loads a live folder with its sharings for access checks.
+func (s *noteService) folder(ctx context.Context, id uuid.UUID) (*models.Folder, error) {
+	folder, err := s.folderRepo.GetFolderWithSharings(ctx, id)
+	if err != nil {
+		return nil, apperror.Lookup(err, "folder")
+	}
+	return folder, nil
+}
+
+func (s *noteService) ListNotes(ctx context.Context, userID uuid.UUID, q models.ListQuery) ([]models.Note, *listing.Cursor, error) {
+	notes, cursor, err := s.repo.ListNotesByUserAccess(ctx, userID.String(), q)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list notes: %w", err)
+	}
+
+	return notes, cursor, nil
+}
+
+func (s *noteService) ListNotesByTags(ctx context.Context, userID uuid.UUID, tags []string, matchAll bool, q models.ListQuery) ([]models.Note, *listing.Cursor, error) {
+	tags, err := NormalizeTags(tags)
 	if err != nil {
-		return models.Note{}, fmt.Errorf("failed to verify folder access: %w", err)
+		return nil, nil, err
 	}
 
-	// Check if user owns the folder or has it shared with them
-	hasAccess := folder.OwnerID == userID
-	if !hasAccess {
-		for _, sharing := range folder.Sharings {
-			if sharing.UserID == userID {
-				hasAccess = true
-				break
-			}
-		}
+	notes, cursor, err := s.repo.ListNotesByTags(ctx, userID.String(), tags, matchAll, q)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list notes: %w", err)
 	}
 
-	if !hasAccess {
-		return models.Note{}, fmt.Errorf("access denied: you don't have permission to view this note")
+	return notes, cursor, nil
+}
+
+func (s *noteService) GetNote(ctx context.Context, id string, userID uuid.UUID) (models.Note, error) {
+	note, err := s.getNote(ctx, id)
+	if err != nil {
+		return models.Note{}, err
+	}
+
+	// Check if user has access to the folder containing this note
+	folder, err := s.folderRepo.GetFolderByID(ctx, note.FolderID)
+	if err != nil {
+		return models.Note{}, apperror.Lookup(err, "folder")
+	}
+
+	if !canReadFolder(folder, userID) {
+		return models.Note{}, apperror.Forbidden("access denied: you don't have permission to view this note")
 	}
 
 	return note, nil
 }
 
-func (s *noteService) UpdateNote(id string, note any, userID uuid.UUID) (models.Note, error) {
-	// First get the existing note to check folder access
-	existingNote, err := s.repo.GetNote(id)
+func (s *noteService) UpdateNote(ctx context.Context, id string, update models.NoteUpdate, userID uuid.UUID) (models.Note, error) {
+	if _, err := s.writableNote(ctx, id, userID); err != nil {
+		return models.Note{}, err
+	}
+
+	changes, err := noteChanges(update)
 	if err != nil {
-		return models.Note{}, fmt.Errorf("failed to get note: %w", err)
+		return models.Note{}, err
 	}
 
-	// Check if user has write access to the folder containing this note
-	folder, err := s.folderRepo.GetFolderByID(existingNote.FolderID)
+	return s.applyNoteChanges(ctx, id, changes, userID)
+}
+
+// writableNote loads the note and checks that the user has write access to
+// the folder containing it.
+func (s *noteService) writableNote(ctx context.Context, id string, userID uuid.UUID) (models.Note, error) {
+	note, err := s.getNote(ctx, id)
+	if err != nil {
+		return models.Note{}, err
+	}
+
+	folder, err := s.folderRepo.GetFolderByID(ctx, note.FolderID)
 	if err != nil {
-		return models.Note{}, fmt.Errorf("failed to verify folder access: %w", err)
+		return models.Note{}, apperror.Lookup(err, "folder")
 	}
 
-	// Check if user owns the folder or has write permission shared with them
-	hasWriteAccess := folder.OwnerID == userID
-	if !hasWriteAccess {
-		for _, sharing := range folder.Sharings {
-			if sharing.UserID == userID && sharing.Permission == "write" {
-				hasWriteAccess = true
-				break
-			}
-		}
+	if !canWriteFolder(folder, userID) {
+		return models.Note{}, apperror.Forbidden("access denied: you don't have write permission for this note")
 	}
 
-	if !hasWriteAccess {
-		return models.Note{}, fmt.Errorf("access denied: you don't have write permission for this note")
+	return note, nil
+}
+
+func (s *noteService) applyNoteChanges(ctx context.Context, id string, changes map[string]any, userID uuid.UUID) (models.Note, error) {
+	if len(changes) == 0 {
+		return s.repo.GetNote(ctx, id)
 	}
 
-	updatedNote, err := s.repo.UpdateNote(id, note)
+	updatedNote, err := s.repo.UpdateNote(ctx, id, changes, userID)
 	if err != nil {
 		return models.Note{}, fmt.Errorf("failed to update note: %w", err)
 	}
@@ -118,37 +194,102 @@ func (s *noteService) UpdateNote(id string, note any, userID uuid.UUID) (models.
 	return updatedNote, nil
 }
 
-func (s *noteService) DeleteNote(id string, userID uuid.UUID) error {
+func (s *noteService) DeleteNote(ctx context.Context, id string, userID uuid.UUID) error {
 	// First get the existing note to check folder access
-	existingNote, err := s.repo.GetNote(id)
+	existingNote, err := s.getNote(ctx, id)
 	if err != nil {
-		return fmt.Errorf("failed to get note: %w", err)
+		return err
 	}
 
 	// Check if user has write access to the folder containing this note
-	folder, err := s.folderRepo.GetFolderByID(existingNote.FolderID)
+	folder, err := s.folderRepo.GetFolderByID(ctx, existingNote.FolderID)
 	if err != nil {
-		return fmt.Errorf("failed to verify folder access: %w", err)
+		return apperror.Lookup(err, "folder")
 	}
 
-	// Check if user owns the folder or has write permission shared with them
-	hasWriteAccess := folder.OwnerID == userID
-	if !hasWriteAccess {
-		for _, sharing := range folder.Sharings {
-			if sharing.UserID == userID && sharing.Permission == "write" {
-				hasWriteAccess = true
-				break
-			}
-		}
+	if !canWriteFolder(folder, userID) {
+		return apperror.Forbidden("access denied: you don't have write permission to delete this note")
 	}
 
-	if !hasWriteAccess {
-		return fmt.Errorf("access denied: you don't have write permission to delete this note")
-	}
-
-	if err := s.repo.DeleteNote(id); err != nil {
+	if err := s.repo.DeleteNote(ctx, id, userID); err != nil {
 		return fmt.Errorf("failed to delete note: %w", err)
 	}
 
 	return nil
 }
+
+// MoveNote moves a note to another folder. The caller needs write access on
+// both folders. Note-level sharings are kept when both folders belong to the
+// same owner; otherwise they are revoked, because only the new owner may
+// decide who the note is shared with, and the note's tags move over to the new
+// owner's vocabulary.
+func (s *noteService) MoveNote(ctx context.Context, id string, targetFolderID uuid.UUID, userID uuid.UUID) (models.Note, error) {
+	note, source, target, err := s.transferFolders(ctx, id, targetFolderID, userID)
+	if err != nil {
+		return models.Note{}, err
+	}
+
+	if source.ID == target.ID {
+		return models.Note{}, apperror.Conflict("note is already in the target folder")
+	}
+
+	ownerChanged := source.OwnerID != target.OwnerID
+	moved, err := s.repo.MoveNote(ctx, note.ID.String(), target, userID, ownerChanged)
+	if err != nil {
+		return models.Note{}, fmt.Errorf("failed to move note: %w", err)
+	}
+
+	return moved, nil
+}
+
+// CopyNote creates a copy of the note in the target folder. Sharings are not
+// copied; the copy is only visible through the target folder's access rules.
+func (s *noteService) CopyNote(ctx context.Context, id string, targetFolderID uuid.UUID, userID uuid.UUID) (*models.Note, error) {
+	note, _, target, err := s.transferFolders(ctx, id, targetFolderID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := &models.Note{
+		Name:      note.Name,
+		Content:   note.Content,
+		FolderID:  target.ID,
+		CreatedBy: userID,
+		UpdatedBy: userID,
+	}
+
+	if err := s.repo.CreateNote(ctx, copied); err != nil {
+		return nil, fmt.Errorf("failed to copy note: %w", err)
+	}
+
+	return copied, nil
+}
+
+// transferFolders loads the note together with its current folder and the
+// target folder, checking that the user can write to both.
+func (s *noteService) transferFolders(ctx context.Context, id string, targetFolderID uuid.UUID, userID uuid.UUID) (models.Note, *models.Folder, *models.Folder, error) {
+	note, err := s.getNote(ctx, id)
+	if err != nil {
+		return models.Note{}, nil, nil, err
+	}
+
+	source, err := s.folderRepo.GetFolderByID(ctx, note.FolderID)
+	if err != nil {
+		return models.Note{}, nil, nil, apperror.Lookup(err, "folder")
+	}
+
+	if !canWriteFolder(source, userID) {
+		return models.Note{}, nil, nil, apperror.Forbidden("access denied: you don't have write permission for the source folder")
+	}
+
+	target, err := s.folderRepo.GetFolderByID(ctx, targetFolderID)
+	if err != nil {
+		return models.Note{}, nil, nil, apperror.Lookup(err, "target folder")
+	}
+
+	if !canWriteFolder(target, userID) {
+		return models.Note{}, nil, nil, apperror.Forbidden("access denied: you don't have write permission for the target folder")
+	}
+
+	return note, source, target, nil
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"asset-service/internal/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"shared/pkg/apperror"
+	"strings"
+	"unicode/utf8"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	maxNoteNameLength    = 255
+	maxNoteContentLength = 1 << 20 // 1 MiB
+)
+
+// patchableNoteFields maps the JSON fields of a note that clients may change
+// to their database columns. Every other field is read-only.
+var patchableNoteFields = map[string]string{
+	"noteName":    "name",
+	"noteContent": "content",
+}
+
+// PatchNote applies a JSON Merge Patch or JSON Patch document to the note's
+// JSON representation. Changes to fields outside patchableNoteFields are
+// rejected rather than silently dropped.
+func (s *noteService) PatchNote(ctx context.Context, id string, format models.PatchFormat, patch []byte, userID uuid.UUID) (models.Note, error) {
+	note, err := s.writableNote(ctx, id, userID)
+	if err != nil {
+		return models.Note{}, err
+	}
+
+	original, err := json.Marshal(note)
+	if err != nil {
+		return models.Note{}, err
+	}
+
+	patched, err := applyPatch(format, original, patch)
+	if err != nil {
+		return models.Note{}, err
+	}
+
+	update, err := noteUpdateFromPatch(original, patched)
+	if err != nil {
+		return models.Note{}, err
+	}
+
+	changes, err := noteChanges(update)
+	if err != nil {
+		return models.Note{}, err
+	}
+
+	return s.applyNoteChanges(ctx, id, changes, userID)
+}
+
+func applyPatch(format models.PatchFormat, original, patch []byte) ([]byte, error) {
+	switch format {
+	case models.PatchFormatMerge:
+		patched, err := jsonpatch.MergePatch(original, patch)
+		if err != nil {
+			return nil, apperror.BadRequest("invalid merge patch").Wrap(err)
+		}
+		return patched, nil
+	case models.PatchFormatJSONPatch:
+		ops, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, apperror.BadRequest("invalid JSON patch").Wrap(err)
+		}
+		patched, err := ops.Apply(original)
+		if err != nil {
+			return nil, apperror.Validation("failed to apply JSON patch", nil).Wrap(err)
+		}
+		return patched, nil
+	default:
+		return nil, apperror.BadRequest("unsupported patch format %q", format)
+	}
+}
+
+// noteUpdateFromPatch compares the document before and after patching and
+// turns the differences into a NoteUpdate, reporting every field that was
+// touched but may not be.
+func noteUpdateFromPatch(original, patched []byte) (models.NoteUpdate, error) {
+	var before, after map[string]any
+	if err := json.Unmarshal(original, &before); err != nil {
+		return models.NoteUpdate{}, err
+	}
+	if err := json.Unmarshal(patched, &after); err != nil {
+		return models.NoteUpdate{}, apperror.Validation("patch must produce a JSON object", nil).Wrap(err)
+	}
+
+	verr := fieldErrors{}
+	var update models.NoteUpdate
+
+	for field, value := range after {
+		old, existed := before[field]
+		if existed && reflect.DeepEqual(old, value) {
+			continue
+		}
+
+		if _, ok := patchableNoteFields[field]; !ok {
+			if existed {
+				verr.add(field, "field is read-only")
+			} else {
+				verr.add(field, "unknown field")
+			}
+			continue
+		}
+
+		str, ok := value.(string)
+		if !ok {
+			verr.add(field, "must be a string")
+			continue
+		}
+
+		switch field {
+		case "noteName":
+			update.Name = &str
+		case "noteContent":
+			update.Content = &str
+		}
+	}
+
+	for field := range before {
+		if _, ok := after[field]; ok {
+			continue
+		}
+		if _, ok := patchableNoteFields[field]; ok {
+			verr.add(field, "field cannot be removed")
+		} else {
+			verr.add(field, "field is read-only")
+		}
+	}
+
+	return update, verr.orNil()
+}
+
+// noteChanges validates the update and converts it into a column map.
+func noteChanges(update models.NoteUpdate) (map[string]any, error) {
+	verr := fieldErrors{}
+	changes := map[string]any{}
+
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		switch {
+		case name == "":
+			verr.add("noteName", "cannot be empty")
+		case utf8.RuneCountInString(name) > maxNoteNameLength:
+			verr.add("noteName", fmt.Sprintf("cannot exceed %d characters", maxNoteNameLength))
+		default:
+			changes[patchableNoteFields["noteName"]] = name
+		}
+	}
+
+	if update.Content != nil {
+		if len(*update.Content) > maxNoteContentLength {
+			verr.add("noteContent", fmt.Sprintf("cannot exceed %d bytes", maxNoteContentLength))
+		} else {
+			changes[patchableNoteFields["noteContent"]] = *update.Content
+		}
+	}
+
+	if err := verr.orNil(); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
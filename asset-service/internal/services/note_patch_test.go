@@ -0,0 +1,99 @@
+package services
+
+import (
+	"asset-service/internal/models"
+	"encoding/json"
+	"errors"
+	"shared/pkg/apperror"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func patchTestNote(t *testing.T) []byte {
+	t.Helper()
+	doc, err := json.Marshal(models.Note{
+		ID:       uuid.New(),
+		Name:     "Original",
+		Content:  "body",
+		FolderID: uuid.New(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal note: %v", err)
+	}
+	return doc
+}
+
+func TestNotePatch_MergePatchUpdatesAllowedFields(t *testing.T) {
+	original := patchTestNote(t)
+
+	patched, err := applyPatch(models.PatchFormatMerge, original, []byte(`{"noteName":"Renamed"}`))
+	if err != nil {
+		t.Fatalf("Expected merge patch to apply, got: %v", err)
+	}
+
+	update, err := noteUpdateFromPatch(original, patched)
+	if err != nil {
+		t.Fatalf("Expected no validation error, got: %v", err)
+	}
+	if update.Name == nil || *update.Name != "Renamed" {
+		t.Errorf("Expected noteName to be updated, got %v", update.Name)
+	}
+	if update.Content != nil {
+		t.Errorf("Expected noteContent to be untouched, got %q", *update.Content)
+	}
+}
+
+func TestNotePatch_JSONPatchRejectsReadOnlyFields(t *testing.T) {
+	original := patchTestNote(t)
+	ops := `[
+		{"op": "replace", "path": "/folderId", "value": "` + uuid.New().String() + `"},
+		{"op": "add", "path": "/ownerId", "value": "x"},
+		{"op": "replace", "path": "/noteContent", "value": "new body"}
+	]`
+
+	patched, err := applyPatch(models.PatchFormatJSONPatch, original, []byte(ops))
+	if err != nil {
+		t.Fatalf("Expected JSON patch to apply, got: %v", err)
+	}
+
+	_, err = noteUpdateFromPatch(original, patched)
+	var verr *apperror.Error
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected validation error, got: %v", err)
+	}
+	if verr.Fields["folderId"] != "field is read-only" {
+		t.Errorf("Expected folderId to be read-only, got %q", verr.Fields["folderId"])
+	}
+	if verr.Fields["ownerId"] != "unknown field" {
+		t.Errorf("Expected ownerId to be unknown, got %q", verr.Fields["ownerId"])
+	}
+	if _, ok := verr.Fields["noteContent"]; ok {
+		t.Errorf("Expected noteContent to be accepted, got %q", verr.Fields["noteContent"])
+	}
+}
+
+func TestNotePatch_RemovingOrNullingFieldsIsRejected(t *testing.T) {
+	original := patchTestNote(t)
+
+	patched, err := applyPatch(models.PatchFormatMerge, original, []byte(`{"noteName":null}`))
+	if err != nil {
+		t.Fatalf("Expected merge patch to apply, got: %v", err)
+	}
+
+	_, err = noteUpdateFromPatch(original, patched)
+	var verr *apperror.Error
+	if !errors.As(err, &verr) || verr.Fields["noteName"] != "field cannot be removed" {
+		t.Errorf("Expected noteName removal to be rejected, got: %v", err)
+	}
+}
+
+func TestNoteChanges_ValidatesPerField(t *testing.T) {
+	empty := "   "
+	_, err := noteChanges(models.NoteUpdate{Name: &empty})
+
+	var verr *apperror.Error
+	if !errors.As(err, &verr) || verr.Fields["noteName"] != "cannot be empty" {
+		t.Errorf("Expected empty noteName to be rejected, got: %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"asset-service/internal/models"
+	"asset-service/internal/repository"
+	"context"
+	"shared/pkg/apperror"
+	"shared/pkg/listing"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// memoryNoteRepo keeps notes in memory and records how they were moved.
+type memoryNoteRepo struct {
+	repository.NoteRepository
+	notes        map[uuid.UUID]*models.Note
+	ownerChanged bool
+}
+
+func (r *memoryNoteRepo) GetNote(_ context.Context, id string) (models.Note, error) {
+	if n, ok := r.notes[uuid.MustParse(id)]; ok {
+		return *n, nil
+	}
+	return models.Note{}, gorm.ErrRecordNotFound
+}
+
+func (r *memoryNoteRepo) CreateNote(_ context.Context, note *models.Note) error {
+	note.ID = uuid.New()
+	r.notes[note.ID] = note
+	return nil
+}
+
+func (r *memoryNoteRepo) MoveNote(_ context.Context, id string, target *models.Folder, updatedBy uuid.UUID, ownerChanged bool) (models.Note, error) {
+	note := r.notes[uuid.MustParse(id)]
+	note.FolderID, note.UpdatedBy = target.ID, updatedBy
+	r.ownerChanged = ownerChanged
+	return *note, nil
+}
+
+func (r *memoryNoteRepo) ListNoteSummaries(_ context.Context, folderID uuid.UUID, _ models.ListQuery) ([]models.NoteSummary, *listing.Cursor, error) {
+	var summaries []models.NoteSummary
+	for _, n := range r.notes {
+		if n.FolderID == folderID {
+			summaries = append(summaries, models.NoteSummary{ID: n.ID, Name: n.Name, FolderID: n.FolderID})
+		}
+	}
+	return summaries, nil, nil
+}
+
+func TestNoteService_FolderNotes(t *testing.T) {
+	folders, folder, owner, writer, reader := sharedFolder()
+	stranger := uuid.New()
+	notes := &memoryNoteRepo{notes: map[uuid.UUID]*models.Note{}}
+	svc := NewNoteService(notes, folders)
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		userID       uuid.UUID
+		noteName     string
+		folderID     uuid.UUID
+		wantKind     apperror.Kind
+		wantListKind apperror.Kind
+	}{
+		{name: "owner", userID: owner, noteName: "Roadmap", folderID: folder.ID},
+		{name: "writer", userID: writer, noteName: "Retro", folderID: folder.ID},
+		{name: "reader lists but cannot create", userID: reader, noteName: "Ideas", folderID: folder.ID, wantKind: apperror.KindForbidden},
+		{name: "stranger", userID: stranger, noteName: "Ideas", folderID: folder.ID, wantKind: apperror.KindForbidden, wantListKind: apperror.KindForbidden},
+		{name: "empty name", userID: owner, noteName: "", folderID: folder.ID, wantKind: apperror.KindValidation},
+		{name: "missing folder", userID: owner, noteName: "Ideas", folderID: uuid.New(), wantKind: apperror.KindNotFound, wantListKind: apperror.KindNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note, err := svc.CreateNote(ctx, tt.noteName, "body", tt.folderID, tt.userID)
+			if tt.wantKind != "" {
+				assertKind(t, err, tt.wantKind)
+			} else if err != nil {
+				t.Fatalf("Expected the note to be created, got %v", err)
+			} else if note.FolderID != folder.ID || note.CreatedBy != tt.userID {
+				t.Errorf("Expected the note in the folder created by the user, got %+v", note)
+			}
+
+			_, _, err = svc.ListFolderNotes(ctx, tt.folderID.String(), models.ListQuery{}, tt.userID)
+			if tt.wantListKind != "" {
+				assertKind(t, err, tt.wantListKind)
+			} else if err != nil {
+				t.Errorf("Expected the folder's notes to be listed, got %v", err)
+			}
+		})
+	}
+
+	summaries, _, err := svc.ListFolderNotes(ctx, folder.ID.String(), models.ListQuery{}, reader)
+	if err != nil || len(summaries) != 2 {
+		t.Errorf("Expected the two created notes, got %d (%v)", len(summaries), err)
+	}
+	_, _, err = svc.ListFolderNotes(ctx, "not-a-uuid", models.ListQuery{}, owner)
+	assertKind(t, err, apperror.KindBadRequest)
+}
+
+func TestNoteService_MoveAndCopyNeedWriteAccessOnBothFolders(t *testing.T) {
+	folders, source, owner, writer, reader := sharedFolder()
+	sameOwner := &models.Folder{ID: uuid.New(), OwnerID: owner, Sharings: []models.FolderSharing{
+		{UserID: writer, Permission: models.PermissionWrite},
+		{UserID: reader, Permission: models.PermissionWrite},
+	}}
+	otherOwner := &models.Folder{ID: uuid.New(), OwnerID: writer}
+	readOnly := &models.Folder{ID: uuid.New(), OwnerID: uuid.New(), Sharings: []models.FolderSharing{
+		{UserID: writer, Permission: models.PermissionRead},
+	}}
+	for _, f := range []*models.Folder{sameOwner, otherOwner, readOnly} {
+		folders.folders[f.ID] = f
+	}
+
+	tests := []struct {
+		name             string
+		userID           uuid.UUID
+		target           uuid.UUID
+		wantKind         apperror.Kind
+		wantOwnerChanged bool
+	}{
+		{name: "writer moves within owner", userID: writer, target: sameOwner.ID},
+		{name: "writer moves to own folder", userID: writer, target: otherOwner.ID, wantOwnerChanged: true},
+		{name: "reader of source", userID: reader, target: sameOwner.ID, wantKind: apperror.KindForbidden},
+		{name: "reader of target", userID: writer, target: readOnly.ID, wantKind: apperror.KindForbidden},
+		{name: "stranger to target", userID: owner, target: otherOwner.ID, wantKind: apperror.KindForbidden},
+		{name: "missing target", userID: owner, target: uuid.New(), wantKind: apperror.KindNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			note := &models.Note{ID: uuid.New(), Name: "Roadmap", Content: "# Roadmap", FolderID: source.ID}
+			notes := &memoryNoteRepo{notes: map[uuid.UUID]*models.Note{note.ID: note}}
+			svc := NewNoteService(notes, folders)
+			ctx := context.Background()
+
+			copied, err := svc.CopyNote(ctx, note.ID.String(), tt.target, tt.userID)
+			if tt.wantKind != "" {
+				assertKind(t, err, tt.wantKind)
+			} else if err != nil {
+				t.Fatalf("Expected the copy to succeed, got %v", err)
+			} else if copied.FolderID != tt.target || copied.Content != note.Content || copied.CreatedBy != tt.userID {
+				t.Errorf("Expected a copy in the target folder created by the user, got %+v", copied)
+			}
+
+			moved, err := svc.MoveNote(ctx, note.ID.String(), tt.target, tt.userID)
+			if tt.wantKind != "" {
+				assertKind(t, err, tt.wantKind)
+				if note.FolderID != source.ID {
+					t.Errorf("Expected the note to stay in its folder, got %s", note.FolderID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected the move to succeed, got %v", err)
+			}
+			if moved.FolderID != tt.target || notes.ownerChanged != tt.wantOwnerChanged {
+				t.Errorf("Expected a move with ownerChanged %t, got folder %s and %t", tt.wantOwnerChanged, moved.FolderID, notes.ownerChanged)
+			}
+		})
+	}
+}
+
+func TestNoteService_MoveToSameFolderConflicts(t *testing.T) {
+	folders, source, owner, _, _ := sharedFolder()
+	note := &models.Note{ID: uuid.New(), FolderID: source.ID}
+	svc := NewNoteService(&memoryNoteRepo{notes: map[uuid.UUID]*models.Note{note.ID: note}}, folders)
+
+	_, err := svc.MoveNote(context.Background(), note.ID.String(), source.ID, owner)
+	assertKind(t, err, apperror.KindConflict)
+	_, err = svc.MoveNote(context.Background(), "not-a-uuid", source.ID, owner)
+	assertKind(t, err, apperror.KindBadRequest)
+}
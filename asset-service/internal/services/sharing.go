@@ -3,22 +3,24 @@ package services
 import (
 	"asset-service/internal/models"
 	"asset-service/internal/repository"
-	"errors"
-	"fmt"
+	"context"
+	"shared/pkg/apperror"
 
 	"github.com/google/uuid"
 )
 
 type SharingService interface {
-	ShareFolder(folderID, userID uuid.UUID, permission models.Permission, ownerID uuid.UUID) error
-	RevokeFolderSharing(folderID, userID uuid.UUID, ownerID uuid.UUID) error
-	GetFolderSharing(folderID, userID uuid.UUID) (*models.FolderSharing, error)
-	ListFolderSharings(folderID uuid.UUID, ownerID uuid.UUID) ([]models.FolderSharing, error)
-
-	ShareNote(noteID, userID uuid.UUID, permission models.Permission, ownerID uuid.UUID) error
-	RevokeNoteSharing(noteID, userID uuid.UUID, ownerID uuid.UUID) error
-	GetNoteSharing(noteID, userID uuid.UUID) (*models.NoteSharing, error)
-	ListNoteSharings(noteID uuid.UUID, ownerID uuid.UUID) ([]models.NoteSharing, error)
+	ShareFolder(ctx context.Context, folderID, userID uuid.UUID, permission models.Permission, ownerID uuid.UUID) error
+	RevokeFolderSharing(ctx context.Context, folderID, userID uuid.UUID, ownerID uuid.UUID) error
+	GetFolderSharing(ctx context.Context, folderID, userID uuid.UUID) (*models.FolderSharing, error)
+	ListFolderSharings(ctx context.Context, folderID uuid.UUID, ownerID uuid.UUID) ([]models.FolderSharing, error)
+
+	ShareNote(ctx context.Context, noteID, userID uuid.UUID, permission models.Permission, ownerID uuid.UUID) error
+	RevokeNoteSharing(ctx context.Context, noteID, userID uuid.UUID, ownerID uuid.UUID) error
+	GetNoteSharing(ctx context.Context, noteID, userID uuid.UUID) (*models.NoteSharing, error)
+	ListNoteSharings(ctx context.Context, noteID uuid.UUID, ownerID uuid.UUID) ([]models.NoteSharing, error)
+
+	RevokeAllSharings(ctx context.Context, userID uuid.UUID) (folders, notes int64, err error)
 }
 
 type sharingService struct {
@@ -36,25 +38,25 @@ func NewSharingService(sharingRepo repository.SharingRepository, folderRepo repo
 }
 
 // Folder sharing methods
-func (s *sharingService) ShareFolder(folderID, userID uuid.UUID, permission models.Permission, ownerID uuid.UUID) error {
+func (s *sharingService) ShareFolder(ctx context.Context, folderID, userID uuid.UUID, permission models.Permission, ownerID uuid.UUID) error {
 	// Verify folder exists and user is the owner
-	folder, err := s.folderRepo.GetFolderByID(folderID)
+	folder, err := s.folderRepo.GetFolderByID(ctx, folderID)
 	if err != nil {
-		return fmt.Errorf("folder not found: %w", err)
+		return apperror.Lookup(err, "folder")
 	}
 
 	if folder.OwnerID != ownerID {
-		return errors.New("only the folder owner can share the folder")
+		return apperror.Forbidden("only the folder owner can share the folder")
 	}
 
 	// Don't allow owner to share with themselves
 	if folder.OwnerID == userID {
-		return errors.New("cannot share folder with yourself")
+		return apperror.BadRequest("cannot share folder with yourself")
 	}
 
 	// Validate permission
 	if permission != models.PermissionRead && permission != models.PermissionWrite {
-		return errors.New("invalid permission type")
+		return apperror.Validation("invalid sharing", map[string]string{"permission": "must be read or write"})
 	}
 
 	sharing := &models.FolderSharing{
@@ -63,67 +65,67 @@ func (s *sharingService) ShareFolder(folderID, userID uuid.UUID, permission mode
 		Permission: permission,
 	}
 
-	return s.sharingRepo.ShareFolder(sharing)
+	return s.sharingRepo.ShareFolder(ctx, sharing)
 }
 
-func (s *sharingService) RevokeFolderSharing(folderID, userID uuid.UUID, ownerID uuid.UUID) error {
+func (s *sharingService) RevokeFolderSharing(ctx context.Context, folderID, userID uuid.UUID, ownerID uuid.UUID) error {
 	// Verify folder exists and user is the owner
-	folder, err := s.folderRepo.GetFolderByID(folderID)
+	folder, err := s.folderRepo.GetFolderByID(ctx, folderID)
 	if err != nil {
-		return fmt.Errorf("folder not found: %w", err)
+		return apperror.Lookup(err, "folder")
 	}
 
 	if folder.OwnerID != ownerID {
-		return errors.New("only the folder owner can revoke folder sharing")
+		return apperror.Forbidden("only the folder owner can revoke folder sharing")
 	}
 
-	return s.sharingRepo.RevokeFolderSharing(folderID, userID)
+	return s.sharingRepo.RevokeFolderSharing(ctx, folderID, userID)
 }
 
-func (s *sharingService) GetFolderSharing(folderID, userID uuid.UUID) (*models.FolderSharing, error) {
-	return s.sharingRepo.GetFolderSharing(folderID, userID)
+func (s *sharingService) GetFolderSharing(ctx context.Context, folderID, userID uuid.UUID) (*models.FolderSharing, error) {
+	return s.sharingRepo.GetFolderSharing(ctx, folderID, userID)
 }
 
-func (s *sharingService) ListFolderSharings(folderID uuid.UUID, ownerID uuid.UUID) ([]models.FolderSharing, error) {
+func (s *sharingService) ListFolderSharings(ctx context.Context, folderID uuid.UUID, ownerID uuid.UUID) ([]models.FolderSharing, error) {
 	// Verify folder exists and user is the owner
-	folder, err := s.folderRepo.GetFolderByID(folderID)
+	folder, err := s.folderRepo.GetFolderByID(ctx, folderID)
 	if err != nil {
-		return nil, fmt.Errorf("folder not found: %w", err)
+		return nil, apperror.Lookup(err, "folder")
 	}
 
 	if folder.OwnerID != ownerID {
-		return nil, errors.New("only the folder owner can view folder sharings")
+		return nil, apperror.Forbidden("only the folder owner can view folder sharings")
 	}
 
-	return s.sharingRepo.ListFolderSharings(folderID)
+	return s.sharingRepo.ListFolderSharings(ctx, folderID)
 }
 
 // Note sharing methods
-func (s *sharingService) ShareNote(noteID, userID uuid.UUID, permission models.Permission, ownerID uuid.UUID) error {
+func (s *sharingService) ShareNote(ctx context.Context, noteID, userID uuid.UUID, permission models.Permission, ownerID uuid.UUID) error {
 	// Verify note exists and get the folder to check ownership
-	note, err := s.noteRepo.GetNote(noteID.String())
+	note, err := s.noteRepo.GetNote(ctx, noteID.String())
 	if err != nil {
-		return fmt.Errorf("note not found: %w", err)
+		return apperror.Lookup(err, "note")
 	}
 
 	// Get folder to check ownership
-	folder, err := s.folderRepo.GetFolderByID(note.FolderID)
+	folder, err := s.folderRepo.GetFolderByID(ctx, note.FolderID)
 	if err != nil {
-		return fmt.Errorf("parent folder not found: %w", err)
+		return apperror.Lookup(err, "parent folder")
 	}
 
 	if folder.OwnerID != ownerID {
-		return errors.New("only the note owner can share the note")
+		return apperror.Forbidden("only the note owner can share the note")
 	}
 
 	// Don't allow owner to share with themselves
 	if folder.OwnerID == userID {
-		return errors.New("cannot share note with yourself")
+		return apperror.BadRequest("cannot share note with yourself")
 	}
 
 	// Validate permission
 	if permission != models.PermissionRead && permission != models.PermissionWrite {
-		return errors.New("invalid permission type")
+		return apperror.Validation("invalid sharing", map[string]string{"permission": "must be read or write"})
 	}
 
 	sharing := &models.NoteSharing{
@@ -132,49 +134,55 @@ func (s *sharingService) ShareNote(noteID, userID uuid.UUID, permission models.P
 		Permission: permission,
 	}
 
-	return s.sharingRepo.ShareNote(sharing)
+	return s.sharingRepo.ShareNote(ctx, sharing)
 }
 
-func (s *sharingService) RevokeNoteSharing(noteID, userID uuid.UUID, ownerID uuid.UUID) error {
+func (s *sharingService) RevokeNoteSharing(ctx context.Context, noteID, userID uuid.UUID, ownerID uuid.UUID) error {
 	// Verify note exists and get the folder to check ownership
-	note, err := s.noteRepo.GetNote(noteID.String())
+	note, err := s.noteRepo.GetNote(ctx, noteID.String())
 	if err != nil {
-		return fmt.Errorf("note not found: %w", err)
+		return apperror.Lookup(err, "note")
 	}
 
 	// Get folder to check ownership
-	folder, err := s.folderRepo.GetFolderByID(note.FolderID)
+	folder, err := s.folderRepo.GetFolderByID(ctx, note.FolderID)
 	if err != nil {
-		return fmt.Errorf("parent folder not found: %w", err)
+		return apperror.Lookup(err, "parent folder")
 	}
 
 	if folder.OwnerID != ownerID {
-		return errors.New("only the note owner can revoke note sharing")
+		return apperror.Forbidden("only the note owner can revoke note sharing")
 	}
 
-	return s.sharingRepo.RevokeNoteSharing(noteID, userID)
+	return s.sharingRepo.RevokeNoteSharing(ctx, noteID, userID)
 }
 
-func (s *sharingService) GetNoteSharing(noteID, userID uuid.UUID) (*models.NoteSharing, error) {
-	return s.sharingRepo.GetNoteSharing(noteID, userID)
+func (s *sharingService) GetNoteSharing(ctx context.Context, noteID, userID uuid.UUID) (*models.NoteSharing, error) {
+	return s.sharingRepo.GetNoteSharing(ctx, noteID, userID)
 }
 
-func (s *sharingService) ListNoteSharings(noteID uuid.UUID, ownerID uuid.UUID) ([]models.NoteSharing, error) {
+func (s *sharingService) ListNoteSharings(ctx context.Context, noteID uuid.UUID, ownerID uuid.UUID) ([]models.NoteSharing, error) {
 	// Verify note exists and get the folder to check ownership
-	note, err := s.noteRepo.GetNote(noteID.String())
+	note, err := s.noteRepo.GetNote(ctx, noteID.String())
 	if err != nil {
-		return nil, fmt.Errorf("note not found: %w", err)
+		return nil, apperror.Lookup(err, "note")
 	}
 
 	// Get folder to check ownership
-	folder, err := s.folderRepo.GetFolderByID(note.FolderID)
+	folder, err := s.folderRepo.GetFolderByID(ctx, note.FolderID)
 	if err != nil {
-		return nil, fmt.Errorf("parent folder not found: %w", err)
+		return nil, apperror.Lookup(err, "parent folder")
 	}
 
 	if folder.OwnerID != ownerID {
-		return nil, errors.New("only the note owner can view note sharings")
+		return nil, apperror.Forbidden("only the note owner can view note sharings")
 	}
 
-	return s.sharingRepo.ListNoteSharings(noteID)
+	return s.sharingRepo.ListNoteSharings(ctx, noteID)
+}
+
+// RevokeAllSharings removes every folder and note sharing granted to the
+// user. Like TransferOwnership it is an operator task without an owner check.
+func (s *sharingService) RevokeAllSharings(ctx context.Context, userID uuid.UUID) (folders, notes int64, err error) {
+	return s.sharingRepo.RevokeAllForUser(ctx, userID)
 }
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"asset-service/internal/models"
+	"asset-service/internal/repository"
+	"context"
+	"fmt"
+	"shared/pkg/apperror"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxTagLength      = 64
+	maxTagsPerRequest = 20
+)
+
+type TagService interface {
+	AddNoteTags(ctx context.Context, noteID string, names []string, userID uuid.UUID) ([]models.Tag, error)
+	RemoveNoteTag(ctx context.Context, noteID string, name string, userID uuid.UUID) error
+	CountTags(ctx context.Context, userID uuid.UUID) ([]models.TagCount, error)
+}
+
+type tagService struct {
+	repo       repository.TagRepository
+	noteRepo   repository.NoteRepository
+	folderRepo repository.FolderRepository
+}
+
+func NewTagService(repo repository.TagRepository, noteRepo repository.NoteRepository, folderRepo repository.FolderRepository) TagService {
+	return &tagService{repo: repo, noteRepo: noteRepo, folderRepo: folderRepo}
+}
+
+// AddNoteTags tags the note, creating missing tags in the vocabulary of the
+// folder owner. It returns the note's full tag list.
+func (s *tagService) AddNoteTags(ctx context.Context, noteID string, names []string, userID uuid.UUID) ([]models.Tag, error) {
+	names, err := NormalizeTags(names)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, apperror.Validation("invalid tags", map[string]string{"tags": "at least one tag is required"})
+	}
+	if len(names) > maxTagsPerRequest {
+		return nil, apperror.Validation("invalid tags", map[string]string{"tags": fmt.Sprintf("cannot add more than %d tags at once", maxTagsPerRequest)})
+	}
+
+	note, folder, err := s.writableNote(ctx, noteID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := s.repo.FindOrCreateTags(ctx, folder.OwnerID, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tags: %w", err)
+	}
+
+	if err := s.repo.AddNoteTags(ctx, note.ID, tags); err != nil {
+		return nil, fmt.Errorf("failed to tag note: %w", err)
+	}
+
+	return s.repo.ListNoteTags(ctx, note.ID)
+}
+
+func (s *tagService) RemoveNoteTag(ctx context.Context, noteID string, name string, userID uuid.UUID) error {
+	names, err := NormalizeTags([]string{name})
+	if err != nil {
+		return err
+	}
+
+	note, folder, err := s.writableNote(ctx, noteID, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.RemoveNoteTag(ctx, note.ID, folder.OwnerID, names[0])
+}
+
+func (s *tagService) CountTags(ctx context.Context, userID uuid.UUID) ([]models.TagCount, error) {
+	counts, err := s.repo.CountTagsByUserAccess(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tags: %w", err)
+	}
+	return counts, nil
+}
+
+func (s *tagService) writableNote(ctx context.Context, noteID string, userID uuid.UUID) (models.Note, *models.Folder, error) {
+	if _, err := parseID(noteID, "note"); err != nil {
+		return models.Note{}, nil, err
+	}
+	note, err := s.noteRepo.GetNote(ctx, noteID)
+	if err != nil {
+		return models.Note{}, nil, apperror.Lookup(err, "note")
+	}
+
+	folder, err := s.folderRepo.GetFolderByID(ctx, note.FolderID)
+	if err != nil {
+		return models.Note{}, nil, apperror.Lookup(err, "folder")
+	}
+
+	if !canWriteFolder(folder, userID) {
+		return models.Note{}, nil, apperror.Forbidden("access denied: you don't have write permission for this note")
+	}
+
+	return note, folder, nil
+}
+
+// NormalizeTags trims and lower-cases tag names, drops duplicates and rejects
+// names that are empty, too long or contain commas (used as the list
+// separator in query strings).
+func NormalizeTags(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case name == "":
+			return nil, apperror.Validation("invalid tags", map[string]string{"tags": "tag names cannot be empty"})
+		case utf8.RuneCountInString(name) > maxTagLength:
+			return nil, apperror.Validation("invalid tags", map[string]string{"tags": fmt.Sprintf("tag names cannot exceed %d characters", maxTagLength)})
+		case strings.Contains(name, ","):
+			return nil, apperror.Validation("invalid tags", map[string]string{"tags": "tag names cannot contain commas"})
+		}
+
+		if !seen[name] {
+			seen[name] = true
+			normalized = append(normalized, name)
+		}
+	}
+
+	return normalized, nil
+}
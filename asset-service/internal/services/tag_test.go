@@ -0,0 +1,145 @@
+package services
+
+import (
+	"asset-service/internal/models"
+	"asset-service/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"shared/pkg/apperror"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// memoryTagRepo keeps each owner's vocabulary and the notes' tags in memory.
+type memoryTagRepo struct {
+	repository.TagRepository
+	tags     map[uuid.UUID][]models.Tag
+	noteTags map[uuid.UUID][]models.Tag
+}
+
+func newMemoryTagRepo() *memoryTagRepo {
+	return &memoryTagRepo{tags: map[uuid.UUID][]models.Tag{}, noteTags: map[uuid.UUID][]models.Tag{}}
+}
+
+func (r *memoryTagRepo) FindOrCreateTags(_ context.Context, ownerID uuid.UUID, names []string) ([]models.Tag, error) {
+	var found []models.Tag
+	for _, name := range names {
+		i := slices.IndexFunc(r.tags[ownerID], func(tag models.Tag) bool { return tag.Name == name })
+		if i < 0 {
+			r.tags[ownerID] = append(r.tags[ownerID], models.Tag{ID: uuid.New(), OwnerID: ownerID, Name: name})
+			i = len(r.tags[ownerID]) - 1
+		}
+		found = append(found, r.tags[ownerID][i])
+	}
+	return found, nil
+}
+
+func (r *memoryTagRepo) AddNoteTags(_ context.Context, noteID uuid.UUID, tags []models.Tag) error {
+	for _, tag := range tags {
+		if !slices.Contains(r.noteTags[noteID], tag) {
+			r.noteTags[noteID] = append(r.noteTags[noteID], tag)
+		}
+	}
+	return nil
+}
+
+func (r *memoryTagRepo) RemoveNoteTag(_ context.Context, noteID, ownerID uuid.UUID, name string) error {
+	r.noteTags[noteID] = slices.DeleteFunc(r.noteTags[noteID], func(tag models.Tag) bool {
+		return tag.OwnerID == ownerID && tag.Name == name
+	})
+	return nil
+}
+
+func (r *memoryTagRepo) ListNoteTags(_ context.Context, noteID uuid.UUID) ([]models.Tag, error) {
+	return r.noteTags[noteID], nil
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      []string
+		want    []string
+		wantErr string
+	}{
+		{name: "trims, lower-cases and dedupes", in: []string{" Go ", "go", "Ops"}, want: []string{"go", "ops"}},
+		{name: "counts characters", in: []string{strings.Repeat("ü", maxTagLength)}, want: []string{strings.Repeat("ü", maxTagLength)}},
+		{name: "empty", in: []string{"go", "  "}, wantErr: "tag names cannot be empty"},
+		{name: "too long", in: []string{strings.Repeat("a", maxTagLength+1)}, wantErr: fmt.Sprintf("tag names cannot exceed %d characters", maxTagLength)},
+		{name: "comma", in: []string{"a,b"}, wantErr: "tag names cannot contain commas"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeTags(tt.in)
+			if tt.wantErr != "" {
+				var appErr *apperror.Error
+				if !errors.As(err, &appErr) || appErr.Fields["tags"] != tt.wantErr {
+					t.Errorf("Expected %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil || !slices.Equal(got, tt.want) {
+				t.Errorf("Expected %v, got %v (%v)", tt.want, got, err)
+			}
+		})
+	}
+}
+
+func TestTagService_TagsNeedWriteAccess(t *testing.T) {
+	folders, folder, owner, writer, reader := sharedFolder()
+	note := &models.Note{ID: uuid.New(), FolderID: folder.ID}
+	notes := &memoryNoteRepo{notes: map[uuid.UUID]*models.Note{note.ID: note}}
+	tags := newMemoryTagRepo()
+	svc := NewTagService(tags, notes, folders)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		names    []string
+		userID   uuid.UUID
+		noteID   string
+		wantKind apperror.Kind
+	}{
+		{name: "reader", names: []string{"go"}, userID: reader, noteID: note.ID.String(), wantKind: apperror.KindForbidden},
+		{name: "no tags", names: nil, userID: owner, noteID: note.ID.String(), wantKind: apperror.KindValidation},
+		{name: "too many tags", names: manyTags(maxTagsPerRequest + 1), userID: owner, noteID: note.ID.String(), wantKind: apperror.KindValidation},
+		{name: "invalid note ID", names: []string{"go"}, userID: owner, noteID: "not-a-uuid", wantKind: apperror.KindBadRequest},
+		{name: "missing note", names: []string{"go"}, userID: owner, noteID: uuid.NewString(), wantKind: apperror.KindNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.AddNoteTags(ctx, tt.noteID, tt.names, tt.userID)
+			assertKind(t, err, tt.wantKind)
+		})
+	}
+	if len(tags.noteTags[note.ID]) != 0 {
+		t.Fatalf("Expected rejected requests not to tag the note, got %v", tags.noteTags[note.ID])
+	}
+
+	got, err := svc.AddNoteTags(ctx, note.ID.String(), []string{"Go", "ops"}, writer)
+	if err != nil {
+		t.Fatalf("Expected the writer to tag the note, got %v", err)
+	}
+	if len(got) != 2 || got[0].OwnerID != owner || len(tags.tags[writer]) != 0 {
+		t.Errorf("Expected the tags in the folder owner's vocabulary, got %+v", got)
+	}
+
+	assertKind(t, svc.RemoveNoteTag(ctx, note.ID.String(), "go", reader), apperror.KindForbidden)
+	if err := svc.RemoveNoteTag(ctx, note.ID.String(), " GO ", writer); err != nil {
+		t.Fatalf("Expected the writer to untag the note, got %v", err)
+	}
+	if remaining := tags.noteTags[note.ID]; len(remaining) != 1 || remaining[0].Name != "ops" {
+		t.Errorf("Expected only ops to remain, got %+v", remaining)
+	}
+}
+
+func manyTags(n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("tag%d", i)
+	}
+	return names
+}
@@ -0,0 +1,230 @@
+package services
+
+import (
+	"asset-service/internal/models"
+	"asset-service/internal/repository"
+	"asset-service/internal/storage"
+	"context"
+	"errors"
+	"fmt"
+	"shared/pkg/apperror"
+	"shared/pkg/log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TrashService interface {
+	ListTrash(ctx context.Context, userID uuid.UUID) (*models.Trash, error)
+	RestoreFolder(ctx context.Context, id string, userID uuid.UUID) error
+	RestoreNote(ctx context.Context, id string, userID uuid.UUID) error
+	DeleteFolderPermanently(ctx context.Context, id string, userID uuid.UUID) error
+	DeleteNotePermanently(ctx context.Context, id string, userID uuid.UUID) error
+	PurgeExpired(ctx context.Context, retention time.Duration) (int, error)
+}
+
+type trashService struct {
+	repo           repository.TrashRepository
+	attachmentRepo repository.AttachmentRepository
+	blobs          storage.BlobStore
+}
+
+func NewTrashService(repo repository.TrashRepository, attachmentRepo repository.AttachmentRepository, blobs storage.BlobStore) TrashService {
+	return &trashService{repo: repo, attachmentRepo: attachmentRepo, blobs: blobs}
+}
+
+func (s *trashService) ListTrash(ctx context.Context, userID uuid.UUID) (*models.Trash, error) {
+	folders, err := s.repo.ListDeletedFolders(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted folders: %w", err)
+	}
+
+	notes, err := s.repo.ListDeletedNotes(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted notes: %w", err)
+	}
+
+	return &models.Trash{Folders: folders, Notes: notes}, nil
+}
+
+func (s *trashService) RestoreFolder(ctx context.Context, id string, userID uuid.UUID) error {
+	folder, err := s.deletedFolder(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.RestoreFolder(ctx, folder, userID); err != nil {
+		return fmt.Errorf("failed to restore folder: %w", err)
+	}
+	return nil
+}
+
+func (s *trashService) RestoreNote(ctx context.Context, id string, userID uuid.UUID) error {
+	note, folder, err := s.deletedNote(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if folder.DeletedAt.Valid {
+		return apperror.Conflict("the note's folder is in the trash; restore the folder first")
+	}
+
+	if err := s.repo.RestoreNote(ctx, note, userID); err != nil {
+		return fmt.Errorf("failed to restore note: %w", err)
+	}
+	return nil
+}
+
+func (s *trashService) DeleteFolderPermanently(ctx context.Context, id string, userID uuid.UUID) error {
+	folder, err := s.deletedFolder(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.purgeFolder(ctx, folder.ID); err != nil {
+		return fmt.Errorf("failed to delete folder: %w", err)
+	}
+	return nil
+}
+
+func (s *trashService) DeleteNotePermanently(ctx context.Context, id string, userID uuid.UUID) error {
+	note, _, err := s.deletedNote(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.purgeNote(ctx, note.ID); err != nil {
+		return fmt.Errorf("failed to delete note: %w", err)
+	}
+	return nil
+}
+
+// PurgeExpired permanently deletes every folder and note that has been in the
+// trash for longer than retention and returns how many items were removed.
+func (s *trashService) PurgeExpired(ctx context.Context, retention time.Duration) (int, error) {
+	cutoff := time.Now().Add(-retention)
+
+	folderIDs, err := s.repo.ListExpiredFolderIDs(ctx, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired folders: %w", err)
+	}
+
+	purged := 0
+	for _, id := range folderIDs {
+		if err := s.purgeFolder(ctx, id); err != nil {
+			return purged, fmt.Errorf("failed to purge folder %s: %w", id, err)
+		}
+		purged++
+	}
+
+	// Folders purged above took their notes with them, so this only picks up
+	// notes that were trashed on their own.
+	noteIDs, err := s.repo.ListExpiredNoteIDs(ctx, cutoff)
+	if err != nil {
+		return purged, fmt.Errorf("failed to list expired notes: %w", err)
+	}
+
+	for _, id := range noteIDs {
+		if err := s.purgeNote(ctx, id); err != nil {
+			return purged, fmt.Errorf("failed to purge note %s: %w", id, err)
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// purgeFolder removes the folder rows and then the blobs of every attachment
+// they referenced. Blob failures are logged; the rows are already gone.
+func (s *trashService) purgeFolder(ctx context.Context, id uuid.UUID) error {
+	keys, err := s.attachmentRepo.ListStorageKeysByFolder(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.PurgeFolder(ctx, id); err != nil {
+		return err
+	}
+	s.deleteBlobs(ctx, keys)
+	return nil
+}
+
+func (s *trashService) purgeNote(ctx context.Context, id uuid.UUID) error {
+	keys, err := s.attachmentRepo.ListStorageKeysByNote(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.PurgeNote(ctx, id); err != nil {
+		return err
+	}
+	s.deleteBlobs(ctx, keys)
+	return nil
+}
+
+// deleteBlobs runs to the end even when ctx is cancelled, since the rows
+// referencing the blobs are already gone.
+func (s *trashService) deleteBlobs(ctx context.Context, keys []string) {
+	ctx = context.WithoutCancel(ctx)
+	for _, key := range keys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			log.Error(ctx, "failed to delete attachment blob", "key", key, "error", err)
+		}
+	}
+}
+
+func (s *trashService) deletedFolder(ctx context.Context, id string, userID uuid.UUID) (*models.Folder, error) {
+	folderID, err := parseID(id, "folder")
+	if err != nil {
+		return nil, err
+	}
+
+	folder, err := s.repo.GetDeletedFolder(ctx, folderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("folder not found in trash")
+		}
+		return nil, err
+	}
+
+	if folder.OwnerID != userID {
+		return nil, apperror.Forbidden("only the folder owner can manage this folder in the trash")
+	}
+
+	return folder, nil
+}
+
+func (s *trashService) deletedNote(ctx context.Context, id string, userID uuid.UUID) (*models.Note, *models.Folder, error) {
+	noteID, err := parseID(id, "note")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	note, err := s.repo.GetDeletedNote(ctx, noteID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, apperror.NotFound("note not found in trash")
+		}
+		return nil, nil, err
+	}
+
+	folder, err := s.repo.GetFolderIncludingDeleted(ctx, note.FolderID)
+	if err != nil {
+		return nil, nil, apperror.Lookup(err, "parent folder")
+	}
+
+	if !canManageTrashedNote(note, folder, userID) {
+		return nil, nil, apperror.Forbidden("only the folder owner or whoever deleted the note can manage it in the trash")
+	}
+
+	return note, folder, nil
+}
+
+// canManageTrashedNote matches ListDeletedNotes: the folder owner, or the user
+// who deleted the note while they can still write to the folder. Deleting a
+// note records the deleter in UpdatedBy.
+func canManageTrashedNote(note *models.Note, folder *models.Folder, userID uuid.UUID) bool {
+	if folder.OwnerID == userID {
+		return true
+	}
+	return note.UpdatedBy == userID && canWriteFolder(folder, userID)
+}
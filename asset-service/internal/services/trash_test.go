@@ -0,0 +1,248 @@
+package services
+
+import (
+	"asset-service/internal/models"
+	"asset-service/internal/repository"
+	"asset-service/internal/storage"
+	"context"
+	"errors"
+	"shared/pkg/apperror"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// memoryTrashRepo keeps trashed folders and notes in memory and records what
+// was restored and purged.
+type memoryTrashRepo struct {
+	repository.TrashRepository
+	folders  map[uuid.UUID]*models.Folder
+	notes    map[uuid.UUID]*models.Note
+	restored []uuid.UUID
+	purged   []uuid.UUID
+}
+
+func newMemoryTrashRepo() *memoryTrashRepo {
+	return &memoryTrashRepo{folders: map[uuid.UUID]*models.Folder{}, notes: map[uuid.UUID]*models.Note{}}
+}
+
+func (r *memoryTrashRepo) GetDeletedFolder(_ context.Context, id uuid.UUID) (*models.Folder, error) {
+	if f, ok := r.folders[id]; ok && f.DeletedAt.Valid {
+		return f, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryTrashRepo) GetDeletedNote(_ context.Context, id uuid.UUID) (*models.Note, error) {
+	if n, ok := r.notes[id]; ok && n.DeletedAt.Valid {
+		return n, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryTrashRepo) GetFolderIncludingDeleted(_ context.Context, id uuid.UUID) (*models.Folder, error) {
+	if f, ok := r.folders[id]; ok {
+		return f, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryTrashRepo) RestoreFolder(_ context.Context, folder *models.Folder, _ uuid.UUID) error {
+	r.restored = append(r.restored, folder.ID)
+	return nil
+}
+
+func (r *memoryTrashRepo) RestoreNote(_ context.Context, note *models.Note, _ uuid.UUID) error {
+	r.restored = append(r.restored, note.ID)
+	return nil
+}
+
+func (r *memoryTrashRepo) PurgeFolder(_ context.Context, id uuid.UUID) error {
+	r.purged = append(r.purged, id)
+	return nil
+}
+
+func (r *memoryTrashRepo) PurgeNote(_ context.Context, id uuid.UUID) error {
+	r.purged = append(r.purged, id)
+	return nil
+}
+
+func (r *memoryTrashRepo) ListExpiredFolderIDs(_ context.Context, cutoff time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for id, f := range r.folders {
+		if f.DeletedAt.Valid && f.DeletedAt.Time.Before(cutoff) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (r *memoryTrashRepo) ListExpiredNoteIDs(_ context.Context, cutoff time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for id, n := range r.notes {
+		if n.DeletedAt.Valid && n.DeletedAt.Time.Before(cutoff) && !slices.Contains(r.purged, n.FolderID) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// keyedAttachmentRepo returns one storage key per folder or note.
+type keyedAttachmentRepo struct {
+	repository.AttachmentRepository
+}
+
+func (keyedAttachmentRepo) ListStorageKeysByNote(_ context.Context, noteID uuid.UUID) ([]string, error) {
+	return []string{"note/" + noteID.String()}, nil
+}
+
+func (keyedAttachmentRepo) ListStorageKeysByFolder(_ context.Context, folderID uuid.UUID) ([]string, error) {
+	return []string{"folder/" + folderID.String()}, nil
+}
+
+// deletedBlobs records the keys it is asked to delete.
+type deletedBlobs struct {
+	storage.BlobStore
+	keys []string
+}
+
+func (b *deletedBlobs) Delete(_ context.Context, key string) error {
+	b.keys = append(b.keys, key)
+	return nil
+}
+
+func trashedAt(t time.Time) gorm.DeletedAt {
+	return gorm.DeletedAt{Time: t, Valid: true}
+}
+
+func TestTrashService_NoteAccess(t *testing.T) {
+	owner, writer, reader, stranger := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	folder := &models.Folder{ID: uuid.New(), OwnerID: owner, Sharings: []models.FolderSharing{
+		{UserID: writer, Permission: models.PermissionWrite},
+		{UserID: reader, Permission: models.PermissionRead},
+	}}
+	trashedFolder := &models.Folder{ID: uuid.New(), OwnerID: owner, DeletedAt: trashedAt(time.Now())}
+
+	tests := []struct {
+		name     string
+		note     models.Note
+		userID   uuid.UUID
+		wantKind apperror.Kind
+	}{
+		{name: "owner restores any note", note: models.Note{UpdatedBy: writer}, userID: owner},
+		{name: "writer restores own deletion", note: models.Note{UpdatedBy: writer}, userID: writer},
+		{name: "writer cannot restore owner's deletion", note: models.Note{UpdatedBy: owner}, userID: writer, wantKind: apperror.KindForbidden},
+		{name: "reader cannot restore own deletion", note: models.Note{UpdatedBy: reader}, userID: reader, wantKind: apperror.KindForbidden},
+		{name: "stranger is forbidden", note: models.Note{UpdatedBy: stranger}, userID: stranger, wantKind: apperror.KindForbidden},
+		{name: "folder must be restored first", note: models.Note{FolderID: trashedFolder.ID, UpdatedBy: owner}, userID: owner, wantKind: apperror.KindConflict},
+		{name: "active note is not in the trash", note: models.Note{UpdatedBy: owner}, userID: owner, wantKind: apperror.KindNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryTrashRepo()
+			repo.folders[folder.ID], repo.folders[trashedFolder.ID] = folder, trashedFolder
+			note := tt.note
+			note.ID = uuid.New()
+			if note.FolderID == uuid.Nil {
+				note.FolderID = folder.ID
+			}
+			if tt.wantKind != apperror.KindNotFound {
+				note.DeletedAt = trashedAt(time.Now())
+			}
+			repo.notes[note.ID] = &note
+			svc := NewTrashService(repo, keyedAttachmentRepo{}, &deletedBlobs{})
+
+			err := svc.RestoreNote(context.Background(), note.ID.String(), tt.userID)
+			if tt.wantKind != "" {
+				assertKind(t, err, tt.wantKind)
+				if len(repo.restored) != 0 {
+					t.Errorf("Expected nothing to be restored, got %v", repo.restored)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected the note to be restored, got %v", err)
+			}
+			if len(repo.restored) != 1 || repo.restored[0] != note.ID {
+				t.Errorf("Expected the note to be restored, got %v", repo.restored)
+			}
+		})
+	}
+}
+
+func TestTrashService_OnlyOwnerManagesTrashedFolder(t *testing.T) {
+	owner, writer := uuid.New(), uuid.New()
+	folder := &models.Folder{ID: uuid.New(), OwnerID: owner, DeletedAt: trashedAt(time.Now()),
+		Sharings: []models.FolderSharing{{UserID: writer, Permission: models.PermissionWrite}}}
+	repo := newMemoryTrashRepo()
+	repo.folders[folder.ID] = folder
+	blobs := &deletedBlobs{}
+	svc := NewTrashService(repo, keyedAttachmentRepo{}, blobs)
+	ctx := context.Background()
+
+	assertKind(t, svc.RestoreFolder(ctx, folder.ID.String(), writer), apperror.KindForbidden)
+	assertKind(t, svc.DeleteFolderPermanently(ctx, folder.ID.String(), writer), apperror.KindForbidden)
+	assertKind(t, svc.RestoreFolder(ctx, "not-a-uuid", owner), apperror.KindBadRequest)
+
+	if err := svc.DeleteFolderPermanently(ctx, folder.ID.String(), owner); err != nil {
+		t.Fatalf("Expected the owner to purge the folder, got %v", err)
+	}
+	if len(repo.purged) != 1 || repo.purged[0] != folder.ID {
+		t.Errorf("Expected the folder to be purged, got %v", repo.purged)
+	}
+	if want := []string{"folder/" + folder.ID.String()}; !slices.Equal(blobs.keys, want) {
+		t.Errorf("Expected the folder's blobs to be deleted, got %v", blobs.keys)
+	}
+}
+
+func TestTrashService_WriterPurgesOwnDeletion(t *testing.T) {
+	owner, writer := uuid.New(), uuid.New()
+	folder := &models.Folder{ID: uuid.New(), OwnerID: owner,
+		Sharings: []models.FolderSharing{{UserID: writer, Permission: models.PermissionWrite}}}
+	note := &models.Note{ID: uuid.New(), FolderID: folder.ID, UpdatedBy: writer, DeletedAt: trashedAt(time.Now())}
+	repo := newMemoryTrashRepo()
+	repo.folders[folder.ID], repo.notes[note.ID] = folder, note
+	blobs := &deletedBlobs{}
+	svc := NewTrashService(repo, keyedAttachmentRepo{}, blobs)
+
+	if err := svc.DeleteNotePermanently(context.Background(), note.ID.String(), writer); err != nil {
+		t.Fatalf("Expected the writer to purge their deletion, got %v", err)
+	}
+	if len(repo.purged) != 1 || repo.purged[0] != note.ID || len(blobs.keys) != 1 {
+		t.Errorf("Expected the note and its blobs to be purged, got %v and %v", repo.purged, blobs.keys)
+	}
+}
+
+func TestTrashService_PurgeExpiredSkipsRecentItems(t *testing.T) {
+	now := time.Now()
+	repo := newMemoryTrashRepo()
+	expired := &models.Folder{ID: uuid.New(), DeletedAt: trashedAt(now.Add(-48 * time.Hour))}
+	recent := &models.Folder{ID: uuid.New(), DeletedAt: trashedAt(now)}
+	repo.folders[expired.ID], repo.folders[recent.ID] = expired, recent
+	inExpired := &models.Note{ID: uuid.New(), FolderID: expired.ID, DeletedAt: expired.DeletedAt}
+	alone := &models.Note{ID: uuid.New(), FolderID: recent.ID, DeletedAt: trashedAt(now.Add(-48 * time.Hour))}
+	repo.notes[inExpired.ID], repo.notes[alone.ID] = inExpired, alone
+	svc := NewTrashService(repo, keyedAttachmentRepo{}, &deletedBlobs{})
+
+	purged, err := svc.PurgeExpired(context.Background(), 24*time.Hour)
+	if err != nil {
+		t.Fatalf("Expected the purge to succeed, got %v", err)
+	}
+	if purged != 2 || !slices.Contains(repo.purged, expired.ID) || !slices.Contains(repo.purged, alone.ID) {
+		t.Errorf("Expected the expired folder and note to be purged, got %d: %v", purged, repo.purged)
+	}
+	if slices.Contains(repo.purged, recent.ID) {
+		t.Error("Expected the recently trashed folder to be kept")
+	}
+}
+
+func assertKind(t *testing.T, err error, want apperror.Kind) {
+	t.Helper()
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) || appErr.Kind != want {
+		t.Errorf("Expected a %s error, got %v", want, err)
+	}
+}
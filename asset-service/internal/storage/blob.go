@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrNotFound is returned when a blob does not exist.
+var ErrNotFound = errors.New("blob not found")
+
+// Blob is an open stored object. It is seekable so that callers can serve
+// HTTP Range requests without reading the whole object.
+type Blob interface {
+	io.ReadSeekCloser
+	ModTime() time.Time
+}
+
+// BlobStore persists opaque binary objects under caller-chosen keys.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (Blob, error)
+	Delete(ctx context.Context, key string) error
+}
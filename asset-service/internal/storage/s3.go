@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+type S3Config struct {
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	Region    string
+	UseSSL    bool
+}
+
+// S3Store keeps blobs in an S3-compatible bucket (AWS S3, MinIO, ...).
+type S3Store struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3Store connects to the endpoint and creates the bucket if it does not
+// exist yet, which keeps local MinIO setups zero-configuration.
+func NewS3Store(ctx context.Context, cfg S3Config) (*S3Store, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	exists, err := client.BucketExists(ctx, cfg.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check bucket %q: %w", cfg.Bucket, err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, cfg.Bucket, minio.MakeBucketOptions{Region: cfg.Region}); err != nil {
+			return nil, fmt.Errorf("failed to create bucket %q: %w", cfg.Bucket, err)
+		}
+	}
+
+	return &S3Store{client: client, bucket: cfg.Bucket}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+func (s *S3Store) Open(ctx context.Context, key string) (Blob, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// GetObject is lazy; Stat surfaces a missing key before anything is read.
+	info, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return &s3Blob{Object: obj, modTime: info.LastModified}, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
+
+type s3Blob struct {
+	*minio.Object
+	modTime time.Time
+}
+
+func (b *s3Blob) ModTime() time.Time {
+	return b.modTime
+}
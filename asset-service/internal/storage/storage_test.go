@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// exerciseStore runs the same round trip against any BlobStore.
+func exerciseStore(t *testing.T, store BlobStore) {
+	t.Helper()
+	ctx := context.Background()
+	key := "attachments/test/blob.txt"
+	content := "hello attachment"
+
+	if err := store.Put(ctx, key, strings.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	blob, err := store.Open(ctx, key)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	// Seek past the first word as http.ServeContent does for Range requests.
+	if _, err := blob.Seek(6, io.SeekStart); err != nil {
+		t.Fatalf("Seek failed: %v", err)
+	}
+	rest, err := io.ReadAll(blob)
+	blob.Close()
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if string(rest) != "attachment" {
+		t.Errorf("Expected %q after seek, got %q", "attachment", rest)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := store.Open(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got: %v", err)
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create local store: %v", err)
+	}
+
+	exerciseStore(t, store)
+
+	if err := store.Put(context.Background(), "../escape", strings.NewReader("x"), 1, "text/plain"); err == nil {
+		t.Error("Expected keys escaping the root to be rejected")
+	}
+}
+
+// TestS3Store runs against a MinIO (or other S3-compatible) server when
+// S3_TEST_ENDPOINT is set, e.g. one started with
+// `docker run -p 9000:9000 minio/minio server /data`.
+func TestS3Store(t *testing.T) {
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT not set")
+	}
+
+	store, err := NewS3Store(context.Background(), S3Config{
+		Endpoint:  endpoint,
+		AccessKey: envOr("S3_TEST_ACCESS_KEY", "minioadmin"),
+		SecretKey: envOr("S3_TEST_SECRET_KEY", "minioadmin"),
+		Bucket:    envOr("S3_TEST_BUCKET", "asset-service-test"),
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create S3 store: %v", err)
+	}
+
+	exerciseStore(t, store)
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -1,51 +1,97 @@
 package middlewares
 
 import (
-	"net/http"
+	"context"
+	"errors"
 	"strings"
 
+	"shared/pkg/apperror"
+	"shared/pkg/tenant"
 	"shared/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// AccountCheck rejects a valid token whose user may no longer act, such as
+// one disabled after the token was issued. It runs with the request context
+// already scoped to the token's organisation.
+type AccountCheck func(ctx context.Context, userID uuid.UUID) error
+
+// AuthMiddleware authenticates the request's bearer token and runs checks
+// against its user. Services without the user records rely on the token's
+// expiry instead; see utils.TokenTTL.
+func AuthMiddleware(checks ...AccountCheck) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
+			WriteProblem(c, apperror.Unauthenticated("authorization header required"))
 			return
 		}
 
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header format"})
-			c.Abort()
+			WriteProblem(c, apperror.Unauthenticated("invalid authorization header format"))
 			return
 		}
 
 		tokenString := parts[1]
 		claims, err := utils.ValidateToken(tokenString)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
+			WriteProblem(c, apperror.Unauthenticated("invalid token"))
 			return
 		}
 
 		userID, err := uuid.Parse(claims.UserID)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID in token: " + err.Error()})
-			c.Abort()
+			WriteProblem(c, apperror.Unauthenticated("invalid user ID in token"))
+			return
+		}
+
+		// Tokens issued before organisations existed carry none
+		orgID, err := uuid.Parse(claims.OrgID)
+		if err != nil || orgID == uuid.Nil {
+			WriteProblem(c, apperror.Unauthenticated("token has no organisation; log in again"))
 			return
 		}
 
 		c.Set("userID", userID)
+		c.Set("orgID", orgID)
 		c.Set("email", claims.Email)
 		c.Set("role", claims.Role)
 		c.Set("username", claims.Username)
+		c.Request = c.Request.WithContext(tenant.WithOrg(c.Request.Context(), orgID))
+
+		for _, check := range checks {
+			if err := check(c.Request.Context(), userID); err != nil {
+				WriteProblem(c, err)
+				return
+			}
+		}
 
 		c.Next()
 	}
 }
+
+var (
+	ErrUserNotAuthenticated = apperror.Unauthenticated("user not authenticated")
+	ErrInvalidUserIDFormat  = errors.New("invalid user ID format")
+)
+
+// ExtractUserID returns the user set by AuthMiddleware. On failure the error
+// is recorded on the context and the caller only needs to return.
+func ExtractUserID(c *gin.Context) (uuid.UUID, error) {
+	userIDValue, exists := c.Get("userID")
+	if !exists {
+		c.Error(ErrUserNotAuthenticated)
+		return uuid.Nil, ErrUserNotAuthenticated
+	}
+
+	userID, ok := userIDValue.(uuid.UUID)
+	if !ok {
+		c.Error(ErrInvalidUserIDFormat)
+		return uuid.Nil, ErrInvalidUserIDFormat
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,95 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"shared/pkg/apperror"
+	"shared/pkg/tenant"
+	"shared/utils"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestAuthMiddleware_ScopesRequestToOrg(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(AuthMiddleware())
+
+	var seen uuid.UUID
+	r.GET("/thing", func(c *gin.Context) {
+		seen, _ = tenant.OrgID(c.Request.Context())
+	})
+
+	orgID := uuid.New()
+	for name, org := range map[string]string{"with org": orgID.String(), "without org": ""} {
+		token, err := utils.GenerateToken(uuid.NewString(), org, "a@example.com", "member", "a")
+		if err != nil {
+			t.Fatalf("Expected a token, got %v", err)
+		}
+		req := httptest.NewRequest(http.MethodGet, "/thing", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		want := http.StatusOK
+		if org == "" {
+			want = http.StatusUnauthorized
+		}
+		if w.Code != want {
+			t.Errorf("Expected %d %s, got %d", want, name, w.Code)
+		}
+	}
+
+	if seen != orgID {
+		t.Errorf("Expected the token's org %s in the context, got %s", orgID, seen)
+	}
+}
+
+func TestAuthMiddleware_RunsAccountChecks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	disabled := uuid.New()
+	r := gin.New()
+	r.Use(AuthMiddleware(func(ctx context.Context, userID uuid.UUID) error {
+		if _, ok := tenant.OrgID(ctx); !ok {
+			t.Error("Expected the check to run with the token's org")
+		}
+		if userID == disabled {
+			return apperror.Forbidden("account is disabled")
+		}
+		return nil
+	}))
+	r.GET("/thing", func(c *gin.Context) {})
+
+	for userID, want := range map[uuid.UUID]int{uuid.New(): http.StatusOK, disabled: http.StatusForbidden} {
+		token, err := utils.GenerateToken(userID.String(), uuid.NewString(), "a@example.com", "member", "a")
+		if err != nil {
+			t.Fatalf("Expected a token, got %v", err)
+		}
+		req := httptest.NewRequest(http.MethodGet, "/thing", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != want {
+			t.Errorf("Expected %d for %s, got %d", want, userID, w.Code)
+		}
+	}
+}
+
+func TestExtractUserID_RecordsMissingUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	if _, err := ExtractUserID(c); err != ErrUserNotAuthenticated || len(c.Errors) != 1 {
+		t.Errorf("Expected an unauthenticated error recorded on the context, got %v and %v", err, c.Errors)
+	}
+
+	userID := uuid.New()
+	c.Set("userID", userID)
+	if got, err := ExtractUserID(c); err != nil || got != userID {
+		t.Errorf("Expected %s, got %s (%v)", userID, got, err)
+	}
+}
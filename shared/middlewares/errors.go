@@ -0,0 +1,34 @@
+package middlewares
+
+import (
+	"shared/pkg/apperror"
+	"shared/pkg/log"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorHandler turns the last error a handler recorded with c.Error into an
+// RFC 7807 problem+json response. Register it before the routes so it runs
+// after them; handlers that already wrote a response are left alone.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		WriteProblem(c, c.Errors.Last().Err)
+	}
+}
+
+// WriteProblem writes err as a problem+json response and aborts the chain.
+// Internal errors are logged and answered with a generic detail.
+func WriteProblem(c *gin.Context, err error) {
+	problem := apperror.ProblemFor(err, c.Request.URL.Path)
+	if problem.Code == apperror.KindInternal {
+		log.Error(c.Request.Context(), "request failed", "method", c.Request.Method, "path", c.Request.URL.Path, "error", err)
+	}
+
+	c.Header("Content-Type", apperror.ProblemContentType)
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
@@ -0,0 +1,75 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"shared/pkg/apperror"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestErrorHandler_WritesProblemForTypedErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   apperror.Kind
+		detail string
+	}{
+		{"not found", apperror.NotFound("note not found"), http.StatusNotFound, apperror.KindNotFound, "note not found"},
+		{"forbidden", apperror.Forbidden("access denied"), http.StatusForbidden, apperror.KindForbidden, "access denied"},
+		{"conflict", apperror.Conflict("already a member"), http.StatusConflict, apperror.KindConflict, "already a member"},
+		{"validation", apperror.Validation("validation failed", map[string]string{"name": "required"}), http.StatusUnprocessableEntity, apperror.KindValidation, "validation failed"},
+		{"internal", errors.New("connection refused"), http.StatusInternalServerError, apperror.KindInternal, "internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.Use(ErrorHandler())
+			r.GET("/thing", func(c *gin.Context) {
+				c.Error(tt.err)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/thing", nil))
+
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != apperror.ProblemContentType {
+				t.Errorf("Expected problem content type, got %q", ct)
+			}
+
+			var problem apperror.Problem
+			if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("Expected a problem body, got %s", w.Body.String())
+			}
+			if problem.Code != tt.code || problem.Detail != tt.detail || problem.Instance != "/thing" {
+				t.Errorf("Unexpected problem: %+v", problem)
+			}
+		})
+	}
+}
+
+func TestErrorHandler_LeavesWrittenResponsesAlone(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ErrorHandler())
+	r.GET("/thing", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+		c.Error(apperror.Conflict("too late"))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/thing", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected the handler's response to stand, got %d", w.Code)
+	}
+}
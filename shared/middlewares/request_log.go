@@ -0,0 +1,52 @@
+package middlewares
+
+import (
+	"log/slog"
+	"shared/pkg/log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestLogger assigns every request an ID, reusing the caller's
+// X-Request-ID when present, echoes it in the response and stores it in the
+// request context so downstream logs and Kafka messages carry it. When the
+// request completes it logs the method, route, status, latency and user.
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		id := c.GetHeader(log.RequestIDHeader)
+		if id == "" || len(id) > 128 {
+			id = uuid.NewString()
+		}
+		c.Header(log.RequestIDHeader, id)
+		c.Request = c.Request.WithContext(log.WithRequestID(c.Request.Context(), id))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []any{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"route", c.FullPath(),
+			"status", status,
+			"latency_ms", time.Since(start).Milliseconds(),
+			"bytes", c.Writer.Size(),
+			"client_ip", c.ClientIP(),
+		}
+		if userID, ok := c.Get("userID"); ok {
+			attrs = append(attrs, "user_id", userID)
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+		slog.Log(c.Request.Context(), level, "request", attrs...)
+	}
+}
@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"shared/pkg/log"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestLogger_PropagatesRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestLogger())
+
+	var seen string
+	r.GET("/thing", func(c *gin.Context) {
+		seen = log.RequestID(c.Request.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/thing", nil)
+	req.Header.Set(log.RequestIDHeader, "abc-123")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if seen != "abc-123" {
+		t.Errorf("Expected the caller's request ID in the context, got %q", seen)
+	}
+	if got := w.Header().Get(log.RequestIDHeader); got != "abc-123" {
+		t.Errorf("Expected the request ID to be echoed, got %q", got)
+	}
+}
+
+func TestRequestLogger_AssignsRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestLogger())
+	r.GET("/thing", func(c *gin.Context) {})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/thing", nil))
+
+	if w.Header().Get(log.RequestIDHeader) == "" {
+		t.Error("Expected a generated request ID")
+	}
+}
@@ -0,0 +1,174 @@
+// Package apperror defines the typed errors services return so that
+// transports can answer with the right status without parsing messages.
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+type Kind string
+
+const (
+	KindInternal        Kind = "INTERNAL"
+	KindBadRequest      Kind = "BAD_REQUEST"
+	KindUnauthenticated Kind = "UNAUTHENTICATED"
+	KindForbidden       Kind = "FORBIDDEN"
+	KindNotFound        Kind = "NOT_FOUND"
+	KindConflict        Kind = "CONFLICT"
+	KindValidation      Kind = "VALIDATION"
+	KindTooLarge        Kind = "TOO_LARGE"
+	KindUnsupported     Kind = "UNSUPPORTED_MEDIA_TYPE"
+)
+
+// Status returns the HTTP status code for the kind.
+func (k Kind) Status() int {
+	switch k {
+	case KindBadRequest:
+		return http.StatusBadRequest
+	case KindUnauthenticated:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindValidation:
+		return http.StatusUnprocessableEntity
+	case KindTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case KindUnsupported:
+		return http.StatusUnsupportedMediaType
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Error is a domain error with a kind and a message that is safe to show to
+// clients. Err, when set, is the underlying cause and is never exposed.
+type Error struct {
+	Kind    Kind
+	Message string
+	// Fields maps invalid input fields to what is wrong with them.
+	Fields map[string]string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches another *Error of the same kind with an empty message, so
+// errors.Is(err, apperror.ErrNotFound) works for any not-found error.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Message == "" && t.Kind == e.Kind
+}
+
+// Kind sentinels for errors.Is.
+var (
+	ErrBadRequest      = &Error{Kind: KindBadRequest}
+	ErrUnauthenticated = &Error{Kind: KindUnauthenticated}
+	ErrForbidden       = &Error{Kind: KindForbidden}
+	ErrNotFound        = &Error{Kind: KindNotFound}
+	ErrConflict        = &Error{Kind: KindConflict}
+	ErrValidation      = &Error{Kind: KindValidation}
+	ErrTooLarge        = &Error{Kind: KindTooLarge}
+	ErrUnsupported     = &Error{Kind: KindUnsupported}
+)
+
+func newf(kind Kind, format string, args ...any) *Error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+func BadRequest(format string, args ...any) *Error {
+	return newf(KindBadRequest, format, args...)
+}
+
+func Unauthenticated(format string, args ...any) *Error {
+	return newf(KindUnauthenticated, format, args...)
+}
+
+func Forbidden(format string, args ...any) *Error {
+	return newf(KindForbidden, format, args...)
+}
+
+func NotFound(format string, args ...any) *Error {
+	return newf(KindNotFound, format, args...)
+}
+
+func Conflict(format string, args ...any) *Error {
+	return newf(KindConflict, format, args...)
+}
+
+func TooLarge(format string, args ...any) *Error {
+	return newf(KindTooLarge, format, args...)
+}
+
+func Unsupported(format string, args ...any) *Error {
+	return newf(KindUnsupported, format, args...)
+}
+
+// Validation reports invalid input, field by field when fields is non-empty.
+func Validation(message string, fields map[string]string) *Error {
+	return &Error{Kind: KindValidation, Message: message, Fields: fields}
+}
+
+// Wrap attaches a cause to the error.
+func (e *Error) Wrap(err error) *Error {
+	e.Err = err
+	return e
+}
+
+// From returns the *Error in err's chain, or an internal error wrapping err.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return &Error{Kind: KindInternal, Message: "internal server error", Err: err}
+}
+
+// KindOf returns the kind of the *Error in err's chain, or KindInternal.
+func KindOf(err error) Kind {
+	return From(err).Kind
+}
+
+// ProblemContentType is the media type of RFC 7807 problem responses.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object. Code and Errors are
+// extension members carrying the error kind and per-field messages.
+type Problem struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Instance string            `json:"instance,omitempty"`
+	Code     Kind              `json:"code"`
+	Errors   map[string]string `json:"errors,omitempty"`
+}
+
+// ProblemFor describes err as a problem for the request path instance.
+func ProblemFor(err error, instance string) Problem {
+	appErr := From(err)
+	status := appErr.Kind.Status()
+	return Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   appErr.Message,
+		Instance: instance,
+		Code:     appErr.Kind,
+		Errors:   appErr.Fields,
+	}
+}
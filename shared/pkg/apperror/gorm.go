@@ -0,0 +1,17 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Lookup turns a missing record into a not-found error and wraps any other
+// repository failure; what names the record in the message.
+func Lookup(err error, what string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return NotFound("%s not found", what)
+	}
+	return fmt.Errorf("failed to get %s: %w", what, err)
+}
@@ -0,0 +1,79 @@
+// Package cli dispatches the subcommands of the admin binaries. Commands
+// have multi-word names such as "user create"; each parses its own flags.
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// ErrUsage is returned when the arguments name no command; the usage has
+// already been written.
+var ErrUsage = errors.New("usage")
+
+// Command is one subcommand.
+type Command struct {
+	// Name is the words selecting the command, e.g. "user set-role".
+	Name string
+	// Usage lists the command's flags and arguments, e.g. "--email <email>".
+	Usage   string
+	Summary string
+	Run     func(ctx context.Context, args []string) error
+}
+
+// Run runs the command named by the leading words of args with the rest of
+// them. Without a match it writes the usage of program to w.
+func Run(ctx context.Context, program string, commands []Command, args []string, w io.Writer) error {
+	var match *Command
+	var words int
+	for i := range commands {
+		name := strings.Fields(commands[i].Name)
+		if len(name) > len(args) || len(name) <= words {
+			continue
+		}
+		if strings.Join(args[:len(name)], " ") == strings.Join(name, " ") {
+			match, words = &commands[i], len(name)
+		}
+	}
+	if match == nil {
+		writeUsage(w, program, commands)
+		return ErrUsage
+	}
+	return match.Run(ctx, args[words:])
+}
+
+func writeUsage(w io.Writer, program string, commands []Command) {
+	fmt.Fprintf(w, "usage: %s <command> [flags]\n\ncommands:\n", program)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, c := range commands {
+		fmt.Fprintf(tw, "  %s %s\t%s\n", c.Name, c.Usage, c.Summary)
+	}
+	tw.Flush()
+}
+
+// Flags returns a flag set for the command that reports errors instead of
+// exiting, so Run's caller decides how to fail.
+func Flags(name string, w io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(w)
+	return fs
+}
+
+// Required returns an error naming the flags in names left empty.
+func Required(fs *flag.FlagSet, names ...string) error {
+	var missing []string
+	for _, name := range names {
+		if f := fs.Lookup(name); f != nil && f.Value.String() == "" {
+			missing = append(missing, "--"+name)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s: missing %s", fs.Name(), strings.Join(missing, ", "))
+	}
+	return nil
+}
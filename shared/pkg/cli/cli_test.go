@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRun_DispatchesLongestMatch(t *testing.T) {
+	var ran string
+	var got []string
+	record := func(name string) func(context.Context, []string) error {
+		return func(_ context.Context, args []string) error {
+			ran, got = name, args
+			return nil
+		}
+	}
+	commands := []Command{
+		{Name: "user", Run: record("user")},
+		{Name: "user create", Run: record("user create")},
+	}
+
+	if err := Run(context.Background(), "admin", commands, []string{"user", "create", "--email", "a@b.c"}, &bytes.Buffer{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ran != "user create" {
+		t.Errorf("Expected user create to run, got %q", ran)
+	}
+	if strings.Join(got, " ") != "--email a@b.c" {
+		t.Errorf("Expected the remaining args, got %v", got)
+	}
+}
+
+func TestRun_WritesUsageWithoutMatch(t *testing.T) {
+	commands := []Command{{Name: "user create", Usage: "--email <email>", Summary: "create a user"}}
+
+	for _, args := range [][]string{nil, {"user"}, {"team", "create"}} {
+		var out bytes.Buffer
+		err := Run(context.Background(), "admin", commands, args, &out)
+		if !errors.Is(err, ErrUsage) {
+			t.Errorf("Expected ErrUsage for %v, got %v", args, err)
+		}
+		if !strings.Contains(out.String(), "user create --email <email>") {
+			t.Errorf("Expected usage listing the command, got %q", out.String())
+		}
+	}
+}
+
+func TestRequired_NamesMissingFlags(t *testing.T) {
+	fs := Flags("user create", &bytes.Buffer{})
+	fs.String("email", "", "")
+	fs.String("role", "", "")
+	fs.String("username", "", "")
+	if err := fs.Parse([]string{"--email", "a@b.c"}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := Required(fs, "email", "role", "username")
+	if err == nil || !strings.Contains(err.Error(), "--role, --username") {
+		t.Errorf("Expected role and username to be reported, got %v", err)
+	}
+	if err := Required(fs, "email"); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package health
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// DB checks that the database behind db answers a ping.
+func DB(db *gorm.DB) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	})
+}
+
+// Redis checks that the Redis server at addr answers PING. It speaks the
+// protocol directly so services don't need a Redis client just to probe it;
+// password, when set, is sent with AUTH first.
+func Redis(addr, password string) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		if deadline, ok := ctx.Deadline(); ok {
+			if err := conn.SetDeadline(deadline); err != nil {
+				return err
+			}
+		}
+
+		r := bufio.NewReader(conn)
+		if password != "" {
+			if err := redisCommand(conn, r, "+OK", "AUTH", password); err != nil {
+				return err
+			}
+		}
+		return redisCommand(conn, r, "+PONG", "PING")
+	})
+}
+
+func redisCommand(conn net.Conn, r *bufio.Reader, want string, args ...string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if _, err := conn.Write([]byte(b.String())); err != nil {
+		return err
+	}
+
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if line = strings.TrimRight(line, "\r\n"); line != want {
+		return fmt.Errorf("unexpected reply to %s: %q", args[0], line)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package health
+
+import (
+	"shared/utils"
+	"time"
+)
+
+// Config is the probe configuration every service reads from the
+// environment.
+type Config struct {
+	// CheckTimeout bounds each readiness check.
+	CheckTimeout time.Duration
+	// DrainDelay is how long /readyz reports shutting_down before the HTTP
+	// server stops accepting connections, giving load balancers time to
+	// take the instance out of rotation.
+	DrainDelay time.Duration
+
+	// RedisAddr enables the Redis readiness check when set.
+	RedisAddr     string
+	RedisPassword string
+}
+
+func LoadConfig() Config {
+	return Config{
+		CheckTimeout:  utils.AsDuration("HEALTH_CHECK_TIMEOUT", DefaultTimeout),
+		DrainDelay:    utils.AsDuration("SHUTDOWN_DRAIN_DELAY", 5*time.Second),
+		RedisAddr:     utils.GetEnv("REDIS_ADDR", ""),
+		RedisPassword: utils.GetEnv("REDIS_PASSWORD", ""),
+	}
+}
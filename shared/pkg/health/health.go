@@ -0,0 +1,169 @@
+// Package health serves the liveness and readiness probes. Liveness only
+// says the process is up; readiness runs the registered dependency checks
+// and fails once the service starts shutting down, so load balancers stop
+// routing to it before in-flight requests are drained.
+package health
+
+import (
+	"context"
+	"net/http"
+	"shared/pkg/lifecycle"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	StatusOK           = "ok"
+	StatusFail         = "fail"
+	StatusShuttingDown = "shutting_down"
+)
+
+// DefaultTimeout bounds a single check when the Health has no timeout set.
+const DefaultTimeout = 2 * time.Second
+
+// Checker reports whether a dependency is usable.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function to Checker.
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// CheckResult is the outcome of one check in a readiness response.
+type CheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the body of a readiness response.
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+type namedChecker struct {
+	name    string
+	checker Checker
+}
+
+// Health holds the readiness checks of a service.
+type Health struct {
+	timeout      time.Duration
+	mu           sync.RWMutex
+	checks       []namedChecker
+	shuttingDown atomic.Bool
+}
+
+// New returns a Health whose checks each get timeout to complete.
+func New(timeout time.Duration) *Health {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Health{timeout: timeout}
+}
+
+// Register adds a readiness check reported under name.
+func (h *Health) Register(name string, c Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks = append(h.checks, namedChecker{name: name, checker: c})
+}
+
+// SetShuttingDown makes readiness fail from now on.
+func (h *Health) SetShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// Readiness returns a lifecycle component failing readiness when stopped and
+// then holding the shutdown for delay, so load balancers notice before the
+// HTTP server stops accepting connections. Add it last so it stops first.
+func (h *Health) Readiness(delay time.Duration) lifecycle.Component {
+	c := lifecycle.Hook("readiness", nil, func(ctx context.Context) error {
+		h.SetShuttingDown()
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+		}
+		return nil
+	})
+	return lifecycle.WithStopTimeout(c, delay+time.Second)
+}
+
+// Check runs every registered check concurrently and reports their results.
+// The report is failing if any check fails; once the service is shutting
+// down the checks are skipped, as their dependencies may already be closed.
+func (h *Health) Check(ctx context.Context) Report {
+	if h.shuttingDown.Load() {
+		return Report{Status: StatusShuttingDown}
+	}
+
+	h.mu.RLock()
+	checks := append([]namedChecker(nil), h.checks...)
+	h.mu.RUnlock()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, nc := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = h.run(ctx, nc.checker)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(checks))}
+	for i, nc := range checks {
+		report.Checks[nc.name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+func (h *Health) run(ctx context.Context, c Checker) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.Check(ctx)
+	res := CheckResult{
+		Status:    StatusOK,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		res.Status = StatusFail
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// Livez answers 200 as long as the process can serve requests. It doesn't
+// look at dependencies: an outage elsewhere is no reason to restart us.
+func (h *Health) Livez() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, Report{Status: StatusOK})
+	}
+}
+
+// Readyz answers 200 when every check passes and 503 otherwise, with the
+// status and latency of each check.
+func (h *Health) Readyz() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := h.Check(c.Request.Context())
+		status := http.StatusOK
+		if report.Status != StatusOK {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, report)
+	}
+}
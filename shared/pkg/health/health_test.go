@@ -0,0 +1,80 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func serve(t *testing.T, h gin.HandlerFunc) (int, Report) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/probe", h)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/probe", nil))
+
+	var report Report
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("Failed to decode report: %v", err)
+	}
+	return w.Code, report
+}
+
+func TestReadyz_ReportsEachCheck(t *testing.T) {
+	h := New(time.Second)
+	h.Register("postgres", CheckerFunc(func(context.Context) error { return nil }))
+	h.Register("kafka", CheckerFunc(func(context.Context) error { return errors.New("no brokers") }))
+
+	code, report := serve(t, h.Readyz())
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 with a failing check, got %d", code)
+	}
+	if report.Status != StatusFail {
+		t.Errorf("Expected status %q, got %q", StatusFail, report.Status)
+	}
+	if got := report.Checks["postgres"].Status; got != StatusOK {
+		t.Errorf("Expected postgres ok, got %q", got)
+	}
+	if got := report.Checks["kafka"]; got.Status != StatusFail || got.Error != "no brokers" {
+		t.Errorf("Expected kafka to fail with its error, got %+v", got)
+	}
+}
+
+func TestReadyz_TimesOutSlowChecks(t *testing.T) {
+	h := New(10 * time.Millisecond)
+	h.Register("redis", CheckerFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	code, report := serve(t, h.Readyz())
+	if code != http.StatusServiceUnavailable || report.Checks["redis"].Status != StatusFail {
+		t.Errorf("Expected the slow check to fail, got %d %+v", code, report)
+	}
+}
+
+func TestReadyz_FailsWhileShuttingDown(t *testing.T) {
+	h := New(time.Second)
+	h.Register("postgres", CheckerFunc(func(context.Context) error { return nil }))
+
+	if code, _ := serve(t, h.Readyz()); code != http.StatusOK {
+		t.Fatalf("Expected 200 before shutdown, got %d", code)
+	}
+
+	h.SetShuttingDown()
+	code, report := serve(t, h.Readyz())
+	if code != http.StatusServiceUnavailable || report.Status != StatusShuttingDown {
+		t.Errorf("Expected 503 shutting_down, got %d %q", code, report.Status)
+	}
+	if code, _ := serve(t, h.Livez()); code != http.StatusOK {
+		t.Errorf("Expected liveness to stay up during shutdown, got %d", code)
+	}
+}
@@ -0,0 +1,153 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+type hook struct {
+	name    string
+	onStart func(ctx context.Context) error
+	onStop  func(ctx context.Context) error
+}
+
+// Hook returns a component calling onStart and onStop; either may be nil.
+func Hook(name string, onStart, onStop func(ctx context.Context) error) Component {
+	return &hook{name: name, onStart: onStart, onStop: onStop}
+}
+
+// Closer returns a component that only needs closing, such as a producer or
+// a connection pool.
+func Closer(name string, closeFn func() error) Component {
+	return Hook(name, nil, func(context.Context) error { return closeFn() })
+}
+
+// WithStopTimeout gives c its own stop timeout instead of the Manager's.
+func WithStopTimeout(c Component, timeout time.Duration) Component {
+	return &timed{Component: c, timeout: timeout}
+}
+
+func (h *hook) Name() string { return h.name }
+
+func (h *hook) Start(ctx context.Context) error {
+	if h.onStart == nil {
+		return nil
+	}
+	return h.onStart(ctx)
+}
+
+func (h *hook) Stop(ctx context.Context) error {
+	if h.onStop == nil {
+		return nil
+	}
+	return h.onStop(ctx)
+}
+
+type timed struct {
+	Component
+	timeout time.Duration
+}
+
+func (t *timed) StopTimeout() time.Duration { return t.timeout }
+
+func (t *timed) Failed() <-chan error {
+	if f, ok := t.Component.(failer); ok {
+		return f.Failed()
+	}
+	return nil
+}
+
+type runner struct {
+	name     string
+	run      func(ctx context.Context) error
+	cancel   context.CancelFunc
+	done     chan struct{}
+	err      error
+	failed   chan error
+	stopping atomic.Bool
+}
+
+// Runner returns a component running run in a goroutine until Stop cancels
+// its context. The context is detached from the one passed to Start, so the
+// work keeps going until the Manager gets to stopping it. If run returns an
+// error before then, the service shuts down.
+func Runner(name string, run func(ctx context.Context) error) Component {
+	return &runner{name: name, run: run, failed: make(chan error, 1)}
+}
+
+func (r *runner) Name() string { return r.name }
+
+func (r *runner) Start(ctx context.Context) error {
+	ctx, r.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		r.err = r.run(ctx)
+		if r.err != nil && !r.stopping.Load() {
+			r.failed <- r.err
+		}
+	}()
+	return nil
+}
+
+func (r *runner) Stop(ctx context.Context) error {
+	r.stopping.Store(true)
+	r.cancel()
+	select {
+	case <-r.done:
+		if r.err != nil && !errors.Is(r.err, context.Canceled) {
+			return r.err
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *runner) Failed() <-chan error { return r.failed }
+
+type httpServer struct {
+	srv     *http.Server
+	drain   time.Duration
+	failed  chan error
+	stopped atomic.Bool
+}
+
+// HTTPServer returns a component serving srv. Start binds the listener so an
+// unavailable port fails startup; Stop stops accepting connections and waits
+// up to drain for in-flight requests before closing the rest.
+func HTTPServer(srv *http.Server, drain time.Duration) Component {
+	return &httpServer{srv: srv, drain: drain, failed: make(chan error, 1)}
+}
+
+func (s *httpServer) Name() string { return "http " + s.srv.Addr }
+
+func (s *httpServer) Start(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.srv.Addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := s.srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) && !s.stopped.Load() {
+			s.failed <- err
+		}
+	}()
+	return nil
+}
+
+func (s *httpServer) Stop(ctx context.Context) error {
+	s.stopped.Store(true)
+	if err := s.srv.Shutdown(ctx); err != nil {
+		// Out of drain time: drop the remaining connections.
+		return errors.Join(err, s.srv.Close())
+	}
+	return nil
+}
+
+func (s *httpServer) StopTimeout() time.Duration { return s.drain }
+
+func (s *httpServer) Failed() <-chan error { return s.failed }
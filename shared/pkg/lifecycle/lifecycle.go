@@ -0,0 +1,123 @@
+// Package lifecycle starts a service's components in order and stops them in
+// reverse when the service is asked to shut down or a component fails, so
+// the HTTP server drains before the consumers, producers and pools it uses
+// are closed.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"shared/pkg/log"
+	"time"
+)
+
+// DefaultStopTimeout bounds a component's Stop when neither the component nor
+// the Manager sets a timeout.
+const DefaultStopTimeout = 10 * time.Second
+
+// Component is a part of the service with a lifetime. Start must not block
+// for the component's lifetime; long-running work belongs in a goroutine,
+// see Runner. Stop should release everything Start acquired and return once
+// the component is fully stopped or ctx expires.
+type Component interface {
+	Name() string
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+// stopTimeouter is implemented by components needing a different drain
+// timeout than the Manager's.
+type stopTimeouter interface {
+	StopTimeout() time.Duration
+}
+
+// failer is implemented by components that can fail after starting; a
+// failure shuts the service down.
+type failer interface {
+	Failed() <-chan error
+}
+
+// Manager runs components in the order they were added.
+type Manager struct {
+	stopTimeout time.Duration
+	components  []Component
+}
+
+// New returns a Manager giving each component stopTimeout to stop.
+func New(stopTimeout time.Duration) *Manager {
+	if stopTimeout <= 0 {
+		stopTimeout = DefaultStopTimeout
+	}
+	return &Manager{stopTimeout: stopTimeout}
+}
+
+// Add appends components; they start after those already added and stop
+// before them.
+func (m *Manager) Add(components ...Component) {
+	m.components = append(m.components, components...)
+}
+
+// Run starts every component, waits until ctx is done or a component fails
+// and then stops the started components in reverse order. If a component
+// fails to start, the ones before it are stopped and the error returned.
+func (m *Manager) Run(ctx context.Context) error {
+	failed := make(chan error, len(m.components))
+	// done releases the goroutines watching components that never fail.
+	done := make(chan struct{})
+	defer close(done)
+
+	for i, c := range m.components {
+		if err := c.Start(ctx); err != nil {
+			err = fmt.Errorf("start %s: %w", c.Name(), err)
+			log.Error(ctx, "component failed to start", "component", c.Name(), "error", err)
+			return errors.Join(err, m.stop(m.components[:i]))
+		}
+		log.Info(ctx, "component started", "component", c.Name())
+
+		if f, ok := c.(failer); ok && f.Failed() != nil {
+			go func(name string, ch <-chan error) {
+				select {
+				case err, ok := <-ch:
+					if ok && err != nil {
+						failed <- fmt.Errorf("%s: %w", name, err)
+					}
+				case <-done:
+				}
+			}(c.Name(), f.Failed())
+		}
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		log.Info(ctx, "shutting down")
+	case runErr = <-failed:
+		log.Error(ctx, "component failed, shutting down", "error", runErr)
+	}
+
+	return errors.Join(runErr, m.stop(m.components))
+}
+
+func (m *Manager) stop(components []Component) error {
+	var errs []error
+	for i := len(components) - 1; i >= 0; i-- {
+		c := components[i]
+
+		timeout := m.stopTimeout
+		if t, ok := c.(stopTimeouter); ok && t.StopTimeout() > 0 {
+			timeout = t.StopTimeout()
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+
+		start := time.Now()
+		if err := c.Stop(ctx); err != nil {
+			log.Error(ctx, "component failed to stop", "component", c.Name(), "error", err)
+			errs = append(errs, fmt.Errorf("stop %s: %w", c.Name(), err))
+		} else {
+			log.Info(ctx, "component stopped", "component", c.Name(), "duration_ms", time.Since(start).Milliseconds())
+		}
+		cancel()
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,155 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) hook(name string, startErr error) Component {
+	return Hook(name,
+		func(context.Context) error {
+			r.add("start " + name)
+			return startErr
+		},
+		func(context.Context) error {
+			r.add("stop " + name)
+			return nil
+		},
+	)
+}
+
+func (r *recorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func TestManager_StopsInReverseOrder(t *testing.T) {
+	rec := &recorder{}
+	m := New(time.Second)
+	m.Add(rec.hook("db", nil), rec.hook("consumer", nil), rec.hook("http", nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := m.Run(ctx); err != nil {
+		t.Fatalf("Expected clean shutdown, got %v", err)
+	}
+
+	want := []string{"start db", "start consumer", "start http", "stop http", "stop consumer", "stop db"}
+	if !reflect.DeepEqual(rec.events, want) {
+		t.Errorf("Expected %v, got %v", want, rec.events)
+	}
+}
+
+func TestManager_StartFailureStopsStartedComponents(t *testing.T) {
+	rec := &recorder{}
+	m := New(time.Second)
+	m.Add(rec.hook("db", nil), rec.hook("http", errors.New("address in use")), rec.hook("readiness", nil))
+
+	err := m.Run(context.Background())
+	if err == nil {
+		t.Fatal("Expected the start error")
+	}
+
+	want := []string{"start db", "start http", "stop db"}
+	if !reflect.DeepEqual(rec.events, want) {
+		t.Errorf("Expected %v, got %v", want, rec.events)
+	}
+}
+
+func TestManager_RunnerFailureShutsDown(t *testing.T) {
+	rec := &recorder{}
+	m := New(time.Second)
+	m.Add(rec.hook("db", nil), Runner("consumer", func(context.Context) error {
+		return errors.New("broker gone")
+	}))
+
+	done := make(chan error, 1)
+	go func() { done <- m.Run(context.Background()) }()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Expected the runner's error")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the runner failure to shut the manager down")
+	}
+	if rec.events[len(rec.events)-1] != "stop db" {
+		t.Errorf("Expected db to be stopped, got %v", rec.events)
+	}
+}
+
+// quietFailer can fail but never does, and never closes its channel.
+type quietFailer struct {
+	Component
+	failed chan error
+}
+
+func (q quietFailer) Failed() <-chan error { return q.failed }
+
+func TestManager_RunReleasesFailureWatchers(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	m := New(time.Second)
+	m.Add(quietFailer{Component: (&recorder{}).hook("consumer", nil), failed: make(chan error)})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := m.Run(ctx); err != nil {
+		t.Fatalf("Expected clean shutdown, got %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the failure watcher to exit, got %d goroutines (was %d)", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHTTPServer_DrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	srv := &http.Server{Addr: "127.0.0.1:0", Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusNoContent)
+	})}
+	ts := httptest.NewUnstartedServer(srv.Handler)
+	ts.Config = srv
+	ts.Start()
+	defer ts.Close()
+
+	c := HTTPServer(srv, time.Second)
+	result := make(chan int, 1)
+	go func() {
+		resp, err := http.Get(ts.URL)
+		if err != nil {
+			result <- 0
+			return
+		}
+		resp.Body.Close()
+		result <- resp.StatusCode
+	}()
+
+	<-started
+	if err := c.Stop(context.Background()); err != nil {
+		t.Fatalf("Expected graceful stop, got %v", err)
+	}
+	if got := <-result; got != http.StatusNoContent {
+		t.Errorf("Expected the in-flight request to complete, got status %d", got)
+	}
+}
@@ -0,0 +1,75 @@
+// Package listing pages API listings by keyset cursor. A listing responds
+// with a JSON array and, unless it is the last page, the cursor of the next
+// page in NextCursorHeader and a Link header.
+package listing
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"shared/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// NextCursorHeader carries the cursor for the next page of a listing. It is
+// absent on the last page.
+const NextCursorHeader = "X-Next-Cursor"
+
+// Cursor marks the last item of a page: its sort value and id. Listings with
+// more than one order also record the order, since a cursor is only valid
+// for the order it was issued for.
+type Cursor struct {
+	Sort  string    `json:"s,omitempty"`
+	Desc  bool      `json:"d,omitempty"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+var ErrInvalidCursor = apperror.BadRequest("invalid cursor")
+
+// Encode returns the cursor as an opaque URL-safe token.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodeCursor(token string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// CursorParam returns the cursor query parameter, or nil when it is absent.
+func CursorParam(c *gin.Context) (*Cursor, error) {
+	v := c.Query("cursor")
+	if v == "" {
+		return nil, nil
+	}
+	return DecodeCursor(v)
+}
+
+// WriteList responds with the items as a JSON array and advertises the next
+// page through NextCursorHeader and a Link header.
+func WriteList(c *gin.Context, items any, next *Cursor) {
+	if next != nil {
+		token := next.Encode()
+		c.Header(NextCursorHeader, token)
+
+		nextURL := *c.Request.URL
+		query := nextURL.Query()
+		query.Set("cursor", token)
+		nextURL.RawQuery = query.Encode()
+		c.Header("Link", fmt.Sprintf(`<%s>; rel="next"`, nextURL.RequestURI()))
+	}
+	c.JSON(http.StatusOK, items)
+}
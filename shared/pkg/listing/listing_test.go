@@ -0,0 +1,69 @@
+package listing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func testContext(target string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	return c, w
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	for _, cursor := range []Cursor{
+		{Value: "Ops", ID: uuid.New()},
+		{Sort: "updatedAt", Desc: true, Value: "2026-01-02T03:04:05Z", ID: uuid.New()},
+	} {
+		c, _ := testContext("/items?cursor=" + cursor.Encode())
+		got, err := CursorParam(c)
+		if err != nil || got == nil || *got != cursor {
+			t.Errorf("Expected %+v, got %+v, %v", cursor, got, err)
+		}
+	}
+}
+
+func TestCursorParam(t *testing.T) {
+	c, _ := testContext("/items")
+	if cursor, err := CursorParam(c); cursor != nil || err != nil {
+		t.Errorf("Expected no cursor, got %+v, %v", cursor, err)
+	}
+
+	for _, token := range []string{"not-a-cursor", Cursor{Value: "x"}.Encode()} {
+		c, _ := testContext("/items?cursor=" + token)
+		if _, err := CursorParam(c); err != ErrInvalidCursor {
+			t.Errorf("Expected %q to be rejected, got %v", token, err)
+		}
+	}
+}
+
+func TestWriteList_AdvertisesNextPage(t *testing.T) {
+	c, w := testContext("/items?limit=1")
+	next := &Cursor{Value: "Ops", ID: uuid.New()}
+	WriteList(c, []string{"Ops"}, next)
+
+	if w.Code != http.StatusOK || w.Body.String() != `["Ops"]` {
+		t.Errorf("Expected a JSON array, got %d %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get(NextCursorHeader) != next.Encode() {
+		t.Errorf("Expected next cursor header, got %q", w.Header().Get(NextCursorHeader))
+	}
+	want := `</items?` + url.Values{"cursor": {next.Encode()}, "limit": {"1"}}.Encode() + `>; rel="next"`
+	if got := w.Header().Get("Link"); got != want {
+		t.Errorf("Expected Link %s, got %s", want, got)
+	}
+
+	c, w = testContext("/items")
+	WriteList(c, []string{}, nil)
+	if w.Header().Get(NextCursorHeader) != "" || w.Header().Get("Link") != "" {
+		t.Error("Expected no next page on the last page")
+	}
+}
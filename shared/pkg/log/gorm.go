@@ -0,0 +1,68 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// SlowQueryThreshold is the duration above which queries are logged at warn
+// level.
+const SlowQueryThreshold = 200 * time.Millisecond
+
+// GormLogger sends GORM's logs through the structured logger, so queries run
+// with a request context are tagged with the request ID. Queries are logged
+// at debug level, slow ones at warn and failures at error; a missing record
+// is not a failure.
+type GormLogger struct {
+	level logger.LogLevel
+}
+
+func NewGormLogger() *GormLogger {
+	return &GormLogger{level: logger.Info}
+}
+
+func (l *GormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &GormLogger{level: level}
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, args ...any) {
+	if l.level >= logger.Info {
+		Info(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, args ...any) {
+	if l.level >= logger.Warn {
+		Warn(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, args ...any) {
+	if l.level >= logger.Error {
+		Error(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	sql, rows := fc()
+	attrs := []any{"sql", sql, "rows", rows, "duration_ms", elapsed.Milliseconds()}
+
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= logger.Error:
+		Error(ctx, "query failed", append(attrs, "error", err)...)
+	case elapsed > SlowQueryThreshold && l.level >= logger.Warn:
+		Warn(ctx, "slow query", attrs...)
+	case l.level >= logger.Info:
+		Debug(ctx, "query", attrs...)
+	}
+}
@@ -0,0 +1,24 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestNew_AddsServiceAndRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := New(&buf, "asset-service", "info", "json")
+
+	logger.InfoContext(WithRequestID(context.Background(), "req-1"), "request", "status", 200)
+	logger.DebugContext(context.Background(), "hidden")
+
+	var record map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &record); err != nil {
+		t.Fatalf("Expected exactly one JSON record, got %s", buf.String())
+	}
+	if record["service"] != "asset-service" || record["request_id"] != "req-1" || record["msg"] != "request" {
+		t.Errorf("Unexpected record: %v", record)
+	}
+}
@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gorm.io/gorm"
+)
+
+var dbQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "db_query_duration_seconds",
+	Help:    "Database query latency by operation, table and result.",
+	Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+}, []string{"operation", "table", "result"})
+
+const startKey = "metrics:start"
+
+// GormPlugin times every GORM operation and exports the connection pool
+// statistics of the database under the db_name label.
+type GormPlugin struct {
+	DBName string
+}
+
+func NewGormPlugin(dbName string) *GormPlugin {
+	return &GormPlugin{DBName: dbName}
+}
+
+func (p *GormPlugin) Name() string {
+	return "metrics"
+}
+
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	if err := prometheus.Register(collectors.NewDBStatsCollector(sqlDB, p.DBName)); err != nil {
+		var already prometheus.AlreadyRegisteredError
+		if !errors.As(err, &already) {
+			return err
+		}
+	}
+
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("metrics:before_create", startTimer),
+		cb.Create().After("gorm:create").Register("metrics:after_create", observe("create")),
+		cb.Query().Before("gorm:query").Register("metrics:before_query", startTimer),
+		cb.Query().After("gorm:query").Register("metrics:after_query", observe("query")),
+		cb.Update().Before("gorm:update").Register("metrics:before_update", startTimer),
+		cb.Update().After("gorm:update").Register("metrics:after_update", observe("update")),
+		cb.Delete().Before("gorm:delete").Register("metrics:before_delete", startTimer),
+		cb.Delete().After("gorm:delete").Register("metrics:after_delete", observe("delete")),
+		cb.Row().Before("gorm:row").Register("metrics:before_row", startTimer),
+		cb.Row().After("gorm:row").Register("metrics:after_row", observe("row")),
+		cb.Raw().Before("gorm:raw").Register("metrics:before_raw", startTimer),
+		cb.Raw().After("gorm:raw").Register("metrics:after_raw", observe("raw")),
+	)
+}
+
+func startTimer(db *gorm.DB) {
+	db.InstanceSet(startKey, time.Now())
+}
+
+func observe(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+		start, ok := v.(time.Time)
+		if !ok {
+			return
+		}
+
+		result := "ok"
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			result = "error"
+		}
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		dbQueryDuration.WithLabelValues(operation, table, result).Observe(time.Since(start).Seconds())
+	}
+}
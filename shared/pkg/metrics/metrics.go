@@ -0,0 +1,59 @@
+// Package metrics exposes Prometheus metrics for the HTTP and database layers
+// shared by the services. Metrics are registered with the default registry
+// and served by Handler.
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by method, route and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	httpInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "http_requests_in_flight",
+		Help: "HTTP requests currently being served.",
+	})
+)
+
+// Handler serves the metrics in the Prometheus exposition format.
+func Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.Handler())
+}
+
+// HTTPMiddleware records request counts and latencies. Requests are labelled
+// with the matched route pattern rather than the raw path, so IDs in paths
+// don't create a series each; requests matching no route share "unmatched".
+func HTTPMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		httpInFlight.Inc()
+		defer httpInFlight.Dec()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		httpRequests.WithLabelValues(c.Request.Method, route, status).Inc()
+		httpDuration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,36 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestHTTPMiddleware_LabelsByRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(HTTPMiddleware())
+	r.GET("/notes/:noteId", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/metrics", Handler())
+
+	for _, path := range []string{"/notes/1", "/notes/2", "/missing"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if got := testutil.ToFloat64(httpRequests.WithLabelValues(http.MethodGet, "/notes/:noteId", "200")); got != 2 {
+		t.Errorf("Expected 2 requests for the route, got %v", got)
+	}
+	if got := testutil.ToFloat64(httpRequests.WithLabelValues(http.MethodGet, "unmatched", "404")); got != 1 {
+		t.Errorf("Expected 1 unmatched request, got %v", got)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(w.Body.String(), "http_request_duration_seconds_bucket") {
+		t.Error("Expected the latency histogram in the exposition")
+	}
+}
@@ -0,0 +1,82 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// Usage describes the migrate subcommand.
+const Usage = `usage: migrate <command>
+
+commands:
+  up            apply every pending migration
+  down          revert the most recent migration
+  to <version>  migrate up or down to version (0 reverts everything)
+  status        list migrations and when they were applied
+  verify        check that the models match the database schema`
+
+// Command runs the migrate subcommand of a service binary.
+type Command struct {
+	Migrator *Migrator
+	// Verify checks the service's models against the schema.
+	Verify func() error
+	Out    io.Writer
+}
+
+func (c Command) Run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\n%s", Usage)
+	}
+
+	switch args[0] {
+	case "up":
+		return c.Migrator.Up(ctx)
+	case "down":
+		return c.Migrator.Down(ctx)
+	case "to":
+		if len(args) != 2 {
+			return fmt.Errorf("to needs a version\n%s", Usage)
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		return c.Migrator.To(ctx, version)
+	case "status":
+		return c.status(ctx)
+	case "verify":
+		if err := c.Verify(); err != nil {
+			return fmt.Errorf("schema doesn't match the models:\n%w", err)
+		}
+		fmt.Fprintln(c.Out, "schema matches the models")
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], Usage)
+	}
+}
+
+func (c Command) status(ctx context.Context) error {
+	statuses, err := c.Migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+	for _, s := range statuses {
+		applied := "pending"
+		if s.AppliedAt != nil {
+			applied = s.AppliedAt.Format(time.RFC3339)
+		}
+		name := s.Name
+		if s.Missing {
+			name = "(not in this binary)"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, name, applied)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,296 @@
+// Package migrate applies versioned SQL migrations embedded in the service
+// binaries. Migrations are pairs of files named
+// <version>_<name>.up.sql and <version>_<name>.down.sql; each runs in its own
+// transaction and is recorded in the schema_migrations table. A Postgres
+// advisory lock serialises replicas migrating the same database at once.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"path"
+	"regexp"
+	"shared/pkg/log"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DefaultTable records the applied migrations.
+const DefaultTable = "schema_migrations"
+
+// Migration is one versioned schema change.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status reports whether a migration has been applied. Missing is set for
+// versions recorded in the database that the binary has no file for, e.g.
+// after a newer release has migrated the database.
+type Status struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+	Missing   bool
+}
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Load reads the migrations in dir of fsys, sorted by version. Every
+// migration needs an up file; a missing down file makes it irreversible.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		match := fileName.FindStringSubmatch(e.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration file %q doesn't match <version>_<name>.(up|down).sql", e.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration file %q has an invalid version", e.Name())
+		}
+		body, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %q and %q", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrator applies migrations to a Postgres database.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	table      string
+}
+
+func New(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations, table: DefaultTable}
+}
+
+// Latest returns the highest version known to the binary, or 0.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.To(ctx, m.Latest())
+}
+
+// Down reverts the most recently applied migration.
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			if _, ok := applied[m.migrations[i].Version]; ok {
+				return m.revert(ctx, conn, m.migrations[i])
+			}
+		}
+		return nil
+	})
+}
+
+// To applies pending migrations up to and including version and reverts
+// applied ones above it, so To(ctx, 0) reverts everything.
+func (m *Migrator) To(ctx context.Context, version int64) error {
+	if version != 0 && m.find(version) == nil {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; ok && mig.Version > version {
+				if err := m.revert(ctx, conn, mig); err != nil {
+					return err
+				}
+			}
+		}
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; !ok && mig.Version <= version {
+				if err := m.apply(ctx, conn, mig); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Status lists the migrations known to the binary or the database.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mig := range m.migrations {
+			s := Status{Version: mig.Version, Name: mig.Name}
+			if at, ok := applied[mig.Version]; ok {
+				s.AppliedAt = &at
+				delete(applied, mig.Version)
+			}
+			statuses = append(statuses, s)
+		}
+		for version, at := range applied {
+			statuses = append(statuses, Status{Version: version, AppliedAt: &at, Missing: true})
+		}
+		sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+		return nil
+	})
+	return statuses, err
+}
+
+func (m *Migrator) find(version int64) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+// withLock runs fn on a single connection holding the migration lock, so
+// concurrent replicas apply migrations one at a time.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	key := m.lockKey()
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", key); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", key); err != nil {
+			log.Error(ctx, "failed to release migration lock", "error", err)
+		}
+	}()
+
+	create := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		version bigint PRIMARY KEY,
+		name text NOT NULL,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`, m.table)
+	if _, err := conn.ExecContext(ctx, create); err != nil {
+		return fmt.Errorf("create %s: %w", m.table, err)
+	}
+	return fn(conn)
+}
+
+func (m *Migrator) lockKey() int64 {
+	h := fnv.New64a()
+	h.Write([]byte("migrate:" + m.table))
+	return int64(h.Sum64())
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("SELECT version, applied_at FROM %s", m.table))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int64]time.Time{}
+	for rows.Next() {
+		var version int64
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, err
+		}
+		applied[version] = at
+	}
+	return applied, rows.Err()
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mig Migration) error {
+	start := time.Now()
+	err := m.inTx(ctx, conn, mig.Up, fmt.Sprintf("INSERT INTO %s (version, name) VALUES ($1, $2)", m.table), mig.Version, mig.Name)
+	if err != nil {
+		return fmt.Errorf("apply migration %d_%s: %w", mig.Version, mig.Name, err)
+	}
+	log.Info(ctx, "migration applied", "version", mig.Version, "name", mig.Name, "duration_ms", time.Since(start).Milliseconds())
+	return nil
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, mig Migration) error {
+	if mig.Down == "" {
+		return fmt.Errorf("migration %d_%s is irreversible", mig.Version, mig.Name)
+	}
+	start := time.Now()
+	err := m.inTx(ctx, conn, mig.Down, fmt.Sprintf("DELETE FROM %s WHERE version = $1", m.table), mig.Version)
+	if err != nil {
+		return fmt.Errorf("revert migration %d_%s: %w", mig.Version, mig.Name, err)
+	}
+	log.Info(ctx, "migration reverted", "version", mig.Version, "name", mig.Name, "duration_ms", time.Since(start).Milliseconds())
+	return nil
+}
+
+func (m *Migrator) inTx(ctx context.Context, conn *sql.Conn, script, record string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// Without arguments the script runs over the simple protocol, which
+	// accepts several statements at once.
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	return tx.Commit()
+}
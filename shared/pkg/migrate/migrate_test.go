@@ -0,0 +1,61 @@
+package migrate
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad_PairsAndSortsMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/0002_add_owner.up.sql":   {Data: []byte("ALTER TABLE teams ADD owner_id uuid;")},
+		"migrations/0002_add_owner.down.sql": {Data: []byte("ALTER TABLE teams DROP owner_id;")},
+		"migrations/0001_init.up.sql":        {Data: []byte("CREATE TABLE teams (id uuid);")},
+	}
+
+	migrations, err := Load(fsys, "migrations")
+	if err != nil {
+		t.Fatalf("Expected migrations to load, got %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("Expected 2 migrations, got %d", len(migrations))
+	}
+	if migrations[0].Version != 1 || migrations[0].Name != "init" || migrations[0].Down != "" {
+		t.Errorf("Expected irreversible 0001_init first, got %+v", migrations[0])
+	}
+	if migrations[1].Version != 2 || migrations[1].Up == "" || migrations[1].Down == "" {
+		t.Errorf("Expected 0002_add_owner with up and down, got %+v", migrations[1])
+	}
+}
+
+func TestLoad_RejectsInvalidFiles(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"bad name":     {"m/init.sql": {Data: []byte("")}},
+		"missing up":   {"m/0001_init.down.sql": {Data: []byte("")}},
+		"name clash":   {"m/0001_a.up.sql": {Data: []byte("x")}, "m/0001_b.up.sql": {Data: []byte("y")}},
+		"zero version": {"m/0000_init.up.sql": {Data: []byte("x")}},
+	}
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(fsys, "m"); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestCommand_RejectsBadArguments(t *testing.T) {
+	cmd := Command{Migrator: New(nil, []Migration{{Version: 1, Name: "init", Up: "SELECT 1"}})}
+
+	for _, args := range [][]string{nil, {"sideways"}, {"to"}, {"to", "-1"}, {"to", "7"}} {
+		err := cmd.Run(context.Background(), args)
+		if err == nil {
+			t.Errorf("Expected an error for %v", args)
+			continue
+		}
+		if len(args) < 2 && !strings.Contains(err.Error(), "usage:") {
+			t.Errorf("Expected usage for %v, got %v", args, err)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Verify checks that the tables, columns, indexes and foreign keys the GORM
+// models expect exist in the database with the expected types, nullability
+// and referential actions, so CI can catch a model change that shipped
+// without its migration. Columns and constraints the models don't know about
+// are allowed: they are normal while a column is being phased out.
+func Verify(db *gorm.DB, models ...any) error {
+	migrator := db.Migrator()
+
+	var (
+		errs []error
+		fks  = map[string][]*schema.Constraint{}
+	)
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+		sch := stmt.Schema
+
+		if !migrator.HasTable(model) {
+			errs = append(errs, fmt.Errorf("table %s is missing", sch.Table))
+			continue
+		}
+
+		columns, err := tableColumns(db, sch.Table)
+		if err != nil {
+			return err
+		}
+		for _, field := range sch.Fields {
+			if field.DBName == "" || field.IgnoreMigration {
+				continue
+			}
+			col, ok := columns[field.DBName]
+			if !ok {
+				errs = append(errs, fmt.Errorf("column %s.%s is missing", sch.Table, field.DBName))
+				continue
+			}
+			if want := sqlType(db.Dialector.DataTypeOf(field)); col.Type != want {
+				errs = append(errs, fmt.Errorf("column %s.%s is %s, expected %s", sch.Table, field.DBName, col.Type, want))
+			}
+			if want := !field.NotNull && !field.PrimaryKey; col.Nullable != want {
+				errs = append(errs, fmt.Errorf("column %s.%s nullable is %t, expected %t", sch.Table, field.DBName, col.Nullable, want))
+			}
+		}
+		for _, idx := range sch.ParseIndexes() {
+			if !migrator.HasIndex(model, idx.Name) {
+				errs = append(errs, fmt.Errorf("index %s on %s is missing", idx.Name, sch.Table))
+			}
+		}
+
+		for table, constraints := range foreignKeys(sch) {
+			fks[table] = append(fks[table], constraints...)
+		}
+	}
+
+	for table, constraints := range fks {
+		existing, err := tableForeignKeys(db, table)
+		if err != nil {
+			return err
+		}
+		seen := map[string]bool{}
+		for _, c := range constraints {
+			// Both sides of a relationship can declare the same constraint.
+			if seen[c.Name] {
+				continue
+			}
+			seen[c.Name] = true
+
+			fk, ok := existing[c.Name]
+			if !ok {
+				errs = append(errs, fmt.Errorf("foreign key %s on %s is missing", c.Name, table))
+				continue
+			}
+			if want := referentialAction(c.OnDelete); fk.OnDelete != want {
+				errs = append(errs, fmt.Errorf("foreign key %s on %s deletes with %s, expected %s", c.Name, table, fk.OnDelete, want))
+			}
+			if want := referentialAction(c.OnUpdate); fk.OnUpdate != want {
+				errs = append(errs, fmt.Errorf("foreign key %s on %s updates with %s, expected %s", c.Name, table, fk.OnUpdate, want))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type column struct {
+	Type     string
+	Nullable bool
+}
+
+func tableColumns(db *gorm.DB, table string) (map[string]column, error) {
+	rows, err := db.Raw(
+		`SELECT column_name, udt_name, is_nullable = 'YES'
+		FROM information_schema.columns
+		WHERE table_schema = CURRENT_SCHEMA() AND table_name = ?`, table,
+	).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := map[string]column{}
+	for rows.Next() {
+		var (
+			name, udt string
+			nullable  bool
+		)
+		if err := rows.Scan(&name, &udt, &nullable); err != nil {
+			return nil, err
+		}
+		columns[name] = column{Type: sqlType(udt), Nullable: nullable}
+	}
+	return columns, rows.Err()
+}
+
+type foreignKey struct {
+	OnUpdate string
+	OnDelete string
+}
+
+func tableForeignKeys(db *gorm.DB, table string) (map[string]foreignKey, error) {
+	rows, err := db.Raw(
+		`SELECT tc.constraint_name, rc.update_rule, rc.delete_rule
+		FROM information_schema.table_constraints tc
+		JOIN information_schema.referential_constraints rc
+			ON rc.constraint_schema = tc.constraint_schema AND rc.constraint_name = tc.constraint_name
+		WHERE tc.table_schema = CURRENT_SCHEMA() AND tc.table_name = ? AND tc.constraint_type = 'FOREIGN KEY'`, table,
+	).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fks := map[string]foreignKey{}
+	for rows.Next() {
+		var name string
+		var fk foreignKey
+		if err := rows.Scan(&name, &fk.OnUpdate, &fk.OnDelete); err != nil {
+			return nil, err
+		}
+		fks[name] = fk
+	}
+	return fks, rows.Err()
+}
+
+// foreignKeys returns the constraints the model's relationships declare,
+// including those of many-to-many join tables, keyed by the table holding the
+// foreign key.
+func foreignKeys(sch *schema.Schema) map[string][]*schema.Constraint {
+	fks := map[string][]*schema.Constraint{}
+	add := func(rel *schema.Relationship) {
+		if rel.Field.IgnoreMigration {
+			return
+		}
+		if c := rel.ParseConstraint(); c != nil && c.Schema != nil {
+			fks[c.Schema.Table] = append(fks[c.Schema.Table], c)
+		}
+	}
+
+	for _, rel := range sch.Relationships.Relations {
+		if rel.JoinTable != nil {
+			for _, joinRel := range rel.JoinTable.Relationships.Relations {
+				add(joinRel)
+			}
+			continue
+		}
+		add(rel)
+	}
+	return fks
+}
+
+// referentialAction spells a GORM constraint action the way
+// information_schema reports it.
+func referentialAction(action string) string {
+	if action == "" {
+		return "NO ACTION"
+	}
+	return strings.ToUpper(action)
+}
+
+var typeModifiers = regexp.MustCompile(`\s*\(.*\)$`)
+
+// typeAliases maps type names, as written in models or reported as
+// information_schema udt_name, to one spelling.
+var typeAliases = map[string]string{
+	"bigint":                      "int8",
+	"bigserial":                   "int8",
+	"integer":                     "int4",
+	"int":                         "int4",
+	"serial":                      "int4",
+	"smallint":                    "int2",
+	"smallserial":                 "int2",
+	"boolean":                     "bool",
+	"decimal":                     "numeric",
+	"double precision":            "float8",
+	"real":                        "float4",
+	"character varying":           "varchar",
+	"character":                   "bpchar",
+	"char":                        "bpchar",
+	"string":                      "text",
+	"timestamp with time zone":    "timestamptz",
+	"timestamp without time zone": "timestamp",
+}
+
+// sqlType normalises a column type, dropping length and precision, so a
+// model's varchar(64) matches the database's varchar.
+func sqlType(name string) string {
+	name = typeModifiers.ReplaceAllString(strings.ToLower(strings.TrimSpace(name)), "")
+	if alias, ok := typeAliases[name]; ok {
+		return alias
+	}
+	return name
+}
@@ -0,0 +1,59 @@
+package migrate
+
+import (
+	"sync"
+	"testing"
+
+	"gorm.io/gorm/schema"
+)
+
+func TestSQLType_NormalisesSpellings(t *testing.T) {
+	tests := map[string]string{
+		"varchar(64)":              "varchar",
+		"VARCHAR(10)":              "varchar",
+		"character varying":        "varchar",
+		"timestamptz(3)":           "timestamptz",
+		"timestamp with time zone": "timestamptz",
+		"bigint":                   "int8",
+		"numeric(10, 2)":           "numeric",
+		"string":                   "text",
+		"uuid":                     "uuid",
+	}
+	for in, want := range tests {
+		if got := sqlType(in); got != want {
+			t.Errorf("Expected %q to normalise to %q, got %q", in, want, got)
+		}
+	}
+}
+
+type verifyParent struct {
+	ID       uint
+	Children []verifyChild `gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE"`
+	Labels   []verifyLabel `gorm:"many2many:verify_parent_labels;constraint:OnDelete:CASCADE"`
+}
+
+type verifyChild struct {
+	ID       uint
+	ParentID uint
+}
+
+type verifyLabel struct {
+	ID uint
+}
+
+func TestForeignKeys_IncludesJoinTables(t *testing.T) {
+	sch, err := schema.Parse(&verifyParent{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("Expected the model to parse, got %v", err)
+	}
+
+	fks := foreignKeys(sch)
+
+	children := fks["verify_children"]
+	if len(children) != 1 || children[0].Name != "fk_verify_parents_children" || children[0].OnDelete != "CASCADE" {
+		t.Errorf("Expected the cascading children constraint, got %+v", children)
+	}
+	if got := len(fks["verify_parent_labels"]); got != 2 {
+		t.Errorf("Expected both join table constraints, got %d", got)
+	}
+}
@@ -0,0 +1,121 @@
+package tenant
+
+import (
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Field is the model field holding a row's organisation. Models without it
+// are shared by all organisations.
+const Field = "OrgID"
+
+var (
+	// ErrNoOrg is returned when a row is created with neither an
+	// organisation of its own nor one in the context.
+	ErrNoOrg = errors.New("tenant: no organisation for new row")
+	// ErrOtherOrg is returned when a row is created for another organisation
+	// than the context's.
+	ErrOtherOrg = errors.New("tenant: row belongs to another organisation")
+	// ErrNoScope is returned for statements on scoped models whose context
+	// has neither an organisation nor the WithoutOrg opt-out.
+	ErrNoScope = errors.New("tenant: statement has no organisation; use WithOrg or WithoutOrg")
+)
+
+// GormPlugin scopes statements on models with an OrgID field to the
+// organisation in the statement's context: queries, updates and deletes only
+// match its rows, and new rows are assigned to it. Statements that must see
+// every organisation, such as background jobs, run with WithoutOrg; other
+// statements without an organisation fail with ErrNoScope rather than touch
+// every organisation's rows. Raw SQL is not rewritten and must filter by
+// org_id itself.
+type GormPlugin struct{}
+
+func NewGormPlugin() *GormPlugin {
+	return &GormPlugin{}
+}
+
+func (p *GormPlugin) Name() string {
+	return "tenant"
+}
+
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("tenant:create", assignOrg),
+		cb.Query().Before("gorm:query").Register("tenant:query", scopeToOrg),
+		cb.Update().Before("gorm:update").Register("tenant:update", scopeToOrg),
+		cb.Delete().Before("gorm:delete").Register("tenant:delete", scopeToOrg),
+		cb.Row().Before("gorm:row").Register("tenant:row", scopeToOrg),
+	)
+}
+
+func scopeToOrg(db *gorm.DB) {
+	field := orgField(db)
+	if field == nil {
+		return
+	}
+	ctx := db.Statement.Context
+	orgID, ok := OrgID(ctx)
+	if !ok {
+		if !Unscoped(ctx) {
+			db.AddError(ErrNoScope)
+		}
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: orgID},
+	}})
+}
+
+// assignOrg gives new rows the context's organisation. Rows must name their
+// organisation themselves when the context opted out with WithoutOrg.
+func assignOrg(db *gorm.DB) {
+	field := orgField(db)
+	if field == nil {
+		return
+	}
+	ctx := db.Statement.Context
+	orgID, scoped := OrgID(ctx)
+	if !scoped && !Unscoped(ctx) {
+		db.AddError(ErrNoScope)
+		return
+	}
+
+	forEachRow(db.Statement.ReflectValue, func(row reflect.Value) {
+		value, zero := field.ValueOf(ctx, row)
+		switch {
+		case zero && scoped:
+			db.AddError(field.Set(ctx, row, orgID))
+		case zero:
+			db.AddError(ErrNoOrg)
+		case scoped && value != any(orgID):
+			db.AddError(ErrOtherOrg)
+		}
+	})
+}
+
+// orgField returns the organisation field of the statement's model, or nil
+// for models shared by all organisations.
+func orgField(db *gorm.DB) *schema.Field {
+	if db.Statement.Schema == nil {
+		return nil
+	}
+	return db.Statement.Schema.LookUpField(Field)
+}
+
+func forEachRow(rv reflect.Value, fn func(row reflect.Value)) {
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if row := reflect.Indirect(rv.Index(i)); row.Kind() == reflect.Struct {
+				fn(row)
+			}
+		}
+	case reflect.Struct:
+		fn(rv)
+	}
+}
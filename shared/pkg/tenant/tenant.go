@@ -0,0 +1,43 @@
+// Package tenant isolates organisations sharing one deployment. The auth
+// middleware stores the caller's organisation in the request context, and
+// the GORM plugin scopes every statement run with that context to it.
+// Statements that must cross organisations opt out with WithoutOrg; any other
+// statement without an organisation fails.
+package tenant
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type (
+	orgKey      struct{}
+	unscopedKey struct{}
+)
+
+// WithOrg returns a context whose database statements are scoped to orgID.
+func WithOrg(ctx context.Context, orgID uuid.UUID) context.Context {
+	return context.WithValue(ctx, orgKey{}, orgID)
+}
+
+// WithoutOrg returns a context whose statements see every organisation, for
+// the few lookups that must cross them, such as finding a user to log in, and
+// for jobs working on all organisations' rows.
+func WithoutOrg(ctx context.Context) context.Context {
+	return context.WithValue(context.WithValue(ctx, orgKey{}, uuid.Nil), unscopedKey{}, true)
+}
+
+// OrgID returns the organisation stored in ctx, if any.
+func OrgID(ctx context.Context) (uuid.UUID, bool) {
+	id, _ := ctx.Value(orgKey{}).(uuid.UUID)
+	return id, id != uuid.Nil
+}
+
+// Unscoped reports whether ctx opted out of organisation scoping with
+// WithoutOrg. WithOrg scopes it again.
+func Unscoped(ctx context.Context) bool {
+	_, ok := OrgID(ctx)
+	unscoped, _ := ctx.Value(unscopedKey{}).(bool)
+	return unscoped && !ok
+}
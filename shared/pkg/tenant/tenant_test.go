@@ -0,0 +1,112 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type widget struct {
+	ID    uuid.UUID
+	OrgID uuid.UUID
+	Name  string
+}
+
+type colour struct {
+	ID   uuid.UUID
+	Name string
+}
+
+// dryRun returns a database that builds statements without running them.
+func dryRun(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.Open("host=localhost"), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatalf("Expected a dry-run database, got %v", err)
+	}
+	if err := db.Use(NewGormPlugin()); err != nil {
+		t.Fatalf("Expected the plugin to register, got %v", err)
+	}
+	return db
+}
+
+func TestGormPlugin_ScopesStatementsToOrg(t *testing.T) {
+	db := dryRun(t)
+	ctx := WithOrg(context.Background(), uuid.New())
+
+	statements := map[string]*gorm.DB{
+		"query":  db.WithContext(ctx).Where("name = ?", "a").Find(&[]widget{}),
+		"count":  db.WithContext(ctx).Model(&widget{}).Count(new(int64)),
+		"update": db.WithContext(ctx).Model(&widget{}).Where("name = ?", "a").Update("name", "b"),
+		"delete": db.WithContext(ctx).Where("name = ?", "a").Delete(&widget{}),
+	}
+	for name, stmt := range statements {
+		if sql := stmt.Statement.SQL.String(); !strings.Contains(sql, `"widgets"."org_id" = `) {
+			t.Errorf("Expected the %s to be scoped to the org, got %s", name, sql)
+		}
+	}
+
+	for name, stmt := range map[string]*gorm.DB{
+		"shared model": db.WithContext(ctx).Find(&[]colour{}),
+		"without org":  db.WithContext(WithoutOrg(ctx)).Find(&[]widget{}),
+	} {
+		if sql := stmt.Statement.SQL.String(); strings.Contains(sql, "org_id") || stmt.Error != nil {
+			t.Errorf("Expected the %s query to be unscoped, got %s (%v)", name, sql, stmt.Error)
+		}
+	}
+}
+
+func TestGormPlugin_RejectsStatementsWithoutOrg(t *testing.T) {
+	db := dryRun(t)
+	ctx := context.Background()
+
+	statements := map[string]*gorm.DB{
+		"query":  db.WithContext(ctx).Find(&[]widget{}),
+		"update": db.WithContext(ctx).Model(&widget{}).Where("name = ?", "a").Update("name", "b"),
+		"delete": db.WithContext(ctx).Where("name = ?", "a").Delete(&widget{}),
+		"create": db.WithContext(ctx).Create(&widget{ID: uuid.New(), OrgID: uuid.New()}),
+	}
+	for name, stmt := range statements {
+		if !errors.Is(stmt.Error, ErrNoScope) {
+			t.Errorf("Expected the %s to fail with ErrNoScope, got %v", name, stmt.Error)
+		}
+	}
+
+	if err := db.WithContext(ctx).Find(&[]colour{}).Error; err != nil {
+		t.Errorf("Expected shared models to need no org, got %v", err)
+	}
+	if Unscoped(WithOrg(WithoutOrg(ctx), uuid.New())) {
+		t.Error("Expected WithOrg to scope an unscoped context again")
+	}
+}
+
+func TestGormPlugin_AssignsNewRowsToOrg(t *testing.T) {
+	db := dryRun(t)
+	orgID := uuid.New()
+	ctx := WithOrg(context.Background(), orgID)
+
+	widgets := []widget{{ID: uuid.New()}, {ID: uuid.New(), OrgID: orgID}}
+	if err := db.WithContext(ctx).Create(&widgets).Error; err != nil {
+		t.Fatalf("Expected the widgets to be created, got %v", err)
+	}
+	if widgets[0].OrgID != orgID {
+		t.Errorf("Expected the new widget in %s, got %s", orgID, widgets[0].OrgID)
+	}
+
+	err := db.WithContext(ctx).Create(&widget{ID: uuid.New(), OrgID: uuid.New()}).Error
+	if !errors.Is(err, ErrOtherOrg) {
+		t.Errorf("Expected ErrOtherOrg, got %v", err)
+	}
+	unscoped := db.WithContext(WithoutOrg(context.Background()))
+	if err := unscoped.Create(&widget{ID: uuid.New()}).Error; !errors.Is(err, ErrNoOrg) {
+		t.Errorf("Expected ErrNoOrg, got %v", err)
+	}
+	if err := unscoped.Create(&widget{ID: uuid.New(), OrgID: orgID}).Error; err != nil {
+		t.Errorf("Expected a row naming its org to be created without one in the context, got %v", err)
+	}
+}
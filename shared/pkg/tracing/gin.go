@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "shared/pkg/tracing"
+
+// Middleware starts a server span for every request, continuing the trace
+// from the caller's traceparent header when present. Spans are named after
+// the matched route, so IDs in paths don't each get a name; the span context
+// is stored in the request context for handlers, GORM and Kafka to pick up.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		name := c.Request.Method
+		if route != "" {
+			name += " " + route
+		}
+
+		ctx, span := Tracer(instrumentationName).Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("url.path", c.Request.URL.Path),
+				attribute.String("http.route", route),
+				attribute.String("client.address", c.ClientIP()),
+			),
+		)
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if userID, ok := c.Get("userID"); ok {
+			span.SetAttributes(attribute.String("enduser.id", fmt.Sprint(userID)))
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+		if err := c.Errors.Last(); err != nil {
+			span.RecordError(err.Err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const spanKey = "tracing:span"
+
+// GormPlugin records a client span for every GORM operation as a child of
+// the span in the statement's context, so queries run with a request context
+// show up under that request.
+type GormPlugin struct {
+	DBName string
+}
+
+func NewGormPlugin(dbName string) *GormPlugin {
+	return &GormPlugin{DBName: dbName}
+}
+
+func (p *GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (p *GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("tracing:before_create", p.startSpan("create")),
+		cb.Create().After("gorm:create").Register("tracing:after_create", endSpan),
+		cb.Query().Before("gorm:query").Register("tracing:before_query", p.startSpan("query")),
+		cb.Query().After("gorm:query").Register("tracing:after_query", endSpan),
+		cb.Update().Before("gorm:update").Register("tracing:before_update", p.startSpan("update")),
+		cb.Update().After("gorm:update").Register("tracing:after_update", endSpan),
+		cb.Delete().Before("gorm:delete").Register("tracing:before_delete", p.startSpan("delete")),
+		cb.Delete().After("gorm:delete").Register("tracing:after_delete", endSpan),
+		cb.Row().Before("gorm:row").Register("tracing:before_row", p.startSpan("row")),
+		cb.Row().After("gorm:row").Register("tracing:after_row", endSpan),
+		cb.Raw().Before("gorm:raw").Register("tracing:before_raw", p.startSpan("raw")),
+		cb.Raw().After("gorm:raw").Register("tracing:after_raw", endSpan),
+	)
+}
+
+func (p *GormPlugin) startSpan(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx := db.Statement.Context
+		if ctx == nil || !trace.SpanFromContext(ctx).SpanContext().IsValid() {
+			// Only trace queries that belong to a trace; background jobs and
+			// migrations would otherwise each start a root span per query.
+			return
+		}
+
+		name := "db." + operation
+		if db.Statement.Table != "" {
+			name += " " + db.Statement.Table
+		}
+		_, span := Tracer(instrumentationName).Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system.name", "postgresql"),
+				attribute.String("db.namespace", p.DBName),
+				attribute.String("db.operation.name", operation),
+				attribute.String("db.collection.name", db.Statement.Table),
+			),
+		)
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+func endSpan(db *gorm.DB) {
+	v, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.query.text", db.Statement.SQL.String()),
+		attribute.Int64("db.response.returned_rows", db.Statement.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
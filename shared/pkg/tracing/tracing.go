@@ -0,0 +1,82 @@
+// Package tracing wires OpenTelemetry tracing for the services: provider
+// setup, W3C trace context propagation and instrumentation for gin and GORM.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Setup installs the global tracer provider and propagator for service and
+// returns a function flushing pending spans on shutdown.
+//
+// OTEL_TRACES_EXPORTER selects the exporter: "otlp" sends spans over OTLP/HTTP
+// to OTEL_EXPORTER_OTLP_ENDPOINT, "stdout" prints them for local runs and
+// "none" records nothing. It defaults to "otlp" when an endpoint is set and
+// to "none" otherwise. Trace context is propagated either way.
+func Setup(ctx context.Context, service string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	exporterName := strings.ToLower(os.Getenv("OTEL_TRACES_EXPORTER"))
+	if exporterName == "" {
+		exporterName = "none"
+		if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != "" {
+			exporterName = "otlp"
+		}
+	}
+
+	var exporter sdktrace.SpanExporter
+	switch exporterName {
+	case "none":
+		return func(context.Context) error { return nil }, nil
+	case "otlp":
+		exp, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		exporter = exp
+	case "stdout":
+		exp, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+		}
+		exporter = exp
+	default:
+		return nil, fmt.Errorf("unknown OTEL_TRACES_EXPORTER %q", exporterName)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(service),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Tracer returns a tracer from the global provider. Callers should not cache
+// it before Setup has run.
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(name)
+}
@@ -1,8 +1,8 @@
 package utils
 
 import (
-	"log"
 	"os"
+	"shared/pkg/log"
 	"strconv"
 	"time"
 )
@@ -13,7 +13,7 @@ func MustEnv(key, def string) string {
 		if def != "" {
 			return def
 		}
-		log.Fatalf("missing required env %s", key)
+		log.Fatal("missing required env", "key", key)
 	}
 	return v
 }
@@ -25,7 +25,7 @@ func AsInt(key string, def int) int {
 	}
 	i, err := strconv.Atoi(v)
 	if err != nil {
-		log.Fatalf("bad int for %s: %v", key, err)
+		log.Fatal("invalid int env", "key", key, "error", err)
 	}
 	return i
 }
@@ -37,7 +37,7 @@ func AsInt64(key string, def int64) int64 {
 	}
 	i, err := strconv.ParseInt(v, 10, 64)
 	if err != nil {
-		log.Fatalf("bad int64 for %s: %v", key, err)
+		log.Fatal("invalid int64 env", "key", key, "error", err)
 	}
 	return i
 }
@@ -49,11 +49,23 @@ func AsDuration(key string, def time.Duration) time.Duration {
 	}
 	d, err := time.ParseDuration(v)
 	if err != nil {
-		log.Fatalf("bad duration for %s: %v", key, err)
+		log.Fatal("invalid duration env", "key", key, "error", err)
 	}
 	return d
 }
 
+func AsBool(key string, def bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Fatal("invalid bool env", "key", key, "error", err)
+	}
+	return b
+}
+
 func GetEnv(key, defaultVal string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
@@ -9,8 +9,14 @@ import (
 
 var jwtSecret = []byte("secret-key")
 
+// TokenTTL is how long a token stays valid. It also bounds how long a user
+// disabled after logging in keeps access to services that only check the
+// token.
+const TokenTTL = time.Hour
+
 type Claims struct {
 	UserID   string `json:"user_id"`
+	OrgID    string `json:"org_id"`
 	Email    string `json:"email"`
 	Role     string `json:"role"`
 	Username string `json:"username"`
@@ -36,14 +42,15 @@ func ValidateToken(tokenString string) (*Claims, error) {
 	return nil, errors.New("invalid token")
 }
 
-func GenerateToken(userID, email, role, username string) (string, error) {
+func GenerateToken(userID, orgID, email, role, username string) (string, error) {
 	claims := Claims{
 		UserID:   userID,
+		OrgID:    orgID,
 		Email:    email,
 		Role:     role,
 		Username: username,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(TokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
@@ -9,6 +9,21 @@ import type {
   ShareRequest
 } from '../types';
 
+// List endpoints are cursor-paginated; follow X-Next-Cursor until the last
+// page so callers keep receiving complete arrays.
+async function fetchAllPages<T>(url: string, params: Record<string, string> = {}): Promise<T[]> {
+  const items: T[] = [];
+  let cursor: string | undefined;
+  do {
+    const response = await assetApi.get<T[]>(url, {
+      params: { ...params, limit: 200, ...(cursor ? { cursor } : {}) },
+    });
+    items.push(...response.data);
+    cursor = response.headers['x-next-cursor'];
+  } while (cursor);
+  return items;
+}
+
 export const assetService = {
   // Health Check
   async checkHealth(): Promise<{ status: string }> {
@@ -18,8 +33,7 @@ export const assetService = {
 
   // Folder operations
   async getFolders(): Promise<Folder[]> {
-    const response = await assetApi.get<Folder[]>('/folders');
-    return response.data;
+    return fetchAllPages<Folder>('/folders', { include: 'notes,sharings' });
   },
 
   async createFolder(folderData: CreateFolderRequest): Promise<Folder> {
@@ -38,8 +52,7 @@ export const assetService = {
 
   // Note operations
   async getNotes(): Promise<Note[]> {
-    const response = await assetApi.get<Note[]>('/notes');
-    return response.data;
+    return fetchAllPages<Note>('/notes', { include: 'sharings' });
   },
 
   async createNote(noteData: CreateNoteRequest): Promise<Note> {
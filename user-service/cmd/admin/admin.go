@@ -0,0 +1,368 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"shared/pkg/apperror"
+	"shared/pkg/cli"
+	"shared/pkg/log"
+	"shared/pkg/tenant"
+	"time"
+	"user-service/internal/config"
+	"user-service/internal/database"
+	"user-service/internal/kafka"
+	"user-service/internal/models"
+	"user-service/internal/repository"
+	"user-service/internal/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// admin holds the services the commands run through. They are built on first
+// use, so printing the usage needs neither the database nor Kafka.
+type admin struct {
+	out io.Writer
+
+	db       *gorm.DB
+	kafkaCfg *config.KafkaConfig
+	producer kafka.Producer
+	orgs     services.OrganizationService
+	users    services.UserService
+	teams    services.TeamService
+}
+
+func (a *admin) commands() []cli.Command {
+	return []cli.Command{
+		{Name: "org create", Usage: "--name <name>", Summary: "create an organisation", Run: a.orgCreate},
+		{Name: "org list", Usage: "", Summary: "list the organisations", Run: a.orgList},
+		{Name: "user create", Usage: "--org <id> --username <name> --email <email> --role <manager|member> [--password <password>]", Summary: "create a user in an organisation, generating a password unless given", Run: a.userCreate},
+		{Name: "user set-role", Usage: "--email <email> --role <admin|manager|member>", Summary: "change a user's role", Run: a.userSetRole},
+		{Name: "user disable", Usage: "--email <email>", Summary: "stop a user from logging in", Run: a.userDisabled(true)},
+		{Name: "user enable", Usage: "--email <email>", Summary: "let a disabled user log in again", Run: a.userDisabled(false)},
+		{Name: "team add-member", Usage: "--team <id> --user <email> --as <manager email> [--manager]", Summary: "add a user to a team on behalf of one of its managers; only the owner adds managers", Run: a.teamAddMember},
+		{Name: "team transfer", Usage: "--team <id> --to <email>", Summary: "make a user the owner of a team, adding them as manager if needed", Run: a.teamTransfer},
+		{Name: "events replay", Usage: "--since <duration|RFC3339> [--topic <topic>]", Summary: "run past team activity events through the handler again", Run: a.eventsReplay},
+	}
+}
+
+func (a *admin) connect() error {
+	if a.db != nil {
+		return nil
+	}
+
+	db, err := database.Connect(*config.LoadDB())
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	a.db = db
+	a.kafkaCfg = config.LoadKafkaConfig()
+
+	// Built like app.Wire, minus the consumer: a reader in the service's
+	// group would take partitions away from the running service.
+	userRepo := repository.NewUserRepository(db)
+	a.producer = kafka.NewProducer(a.kafkaCfg)
+	a.orgs = services.NewOrganizationService(repository.NewOrganizationRepository(db))
+	a.users = services.NewUserService(userRepo)
+	a.teams = services.NewTeamServiceWithEvents(
+		services.NewTeamService(repository.NewTeamRepository(db), userRepo),
+		a.producer,
+		a.kafkaCfg.KafkaTopicTeamActivity,
+	)
+	return nil
+}
+
+// close flushes pending events and closes the database. It is safe to call
+// more than once.
+func (a *admin) close() {
+	ctx := context.Background()
+	if a.producer != nil {
+		if err := a.producer.Close(); err != nil {
+			log.Error(ctx, "failed to close Kafka producer", "error", err)
+		}
+		a.producer = nil
+	}
+	if a.db != nil {
+		if sqlDB, err := a.db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		a.db = nil
+	}
+}
+
+func (a *admin) orgCreate(ctx context.Context, args []string) error {
+	fs := cli.Flags("org create", a.out)
+	name := fs.String("name", "", "organisation name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := cli.Required(fs, "name"); err != nil {
+		return err
+	}
+	if err := a.connect(); err != nil {
+		return err
+	}
+
+	org, err := a.orgs.CreateOrganization(ctx, *name)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "created organisation %s %s\n", org.ID, org.Name)
+	return nil
+}
+
+func (a *admin) orgList(ctx context.Context, args []string) error {
+	fs := cli.Flags("org list", a.out)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := a.connect(); err != nil {
+		return err
+	}
+
+	orgs, err := a.orgs.ListOrganizations(ctx)
+	if err != nil {
+		return err
+	}
+	for _, org := range orgs {
+		fmt.Fprintf(a.out, "%s %s\n", org.ID, org.Name)
+	}
+	return nil
+}
+
+func (a *admin) userCreate(ctx context.Context, args []string) error {
+	fs := cli.Flags("user create", a.out)
+	orgFlag := fs.String("org", "", "organisation ID")
+	username := fs.String("username", "", "user name")
+	email := fs.String("email", "", "email address")
+	role := fs.String("role", "", "manager or member")
+	password := fs.String("password", "", "password; generated when empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := cli.Required(fs, "org", "username", "email", "role"); err != nil {
+		return err
+	}
+	orgID, err := uuid.Parse(*orgFlag)
+	if err != nil {
+		return apperror.BadRequest("invalid organisation ID %q", *orgFlag)
+	}
+	if err := a.connect(); err != nil {
+		return err
+	}
+	if _, err := a.orgs.GetOrganization(ctx, orgID); err != nil {
+		return err
+	}
+
+	generated := *password == ""
+	if generated {
+		*password = generatePassword()
+	}
+	user, err := a.users.CreateUser(tenant.WithOrg(ctx, orgID), *username, *email, *password, *role)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.out, "created user %s %s (%s)\n", user.ID, user.Email, user.Role)
+	if generated {
+		fmt.Fprintf(a.out, "password: %s\n", *password)
+	}
+	return nil
+}
+
+func (a *admin) userSetRole(ctx context.Context, args []string) error {
+	fs := cli.Flags("user set-role", a.out)
+	email := fs.String("email", "", "email address")
+	role := fs.String("role", "", "admin, manager or member")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := cli.Required(fs, "email", "role"); err != nil {
+		return err
+	}
+	if err := a.connect(); err != nil {
+		return err
+	}
+
+	user, err := a.userByEmail(ctx, *email)
+	if err != nil {
+		return err
+	}
+	if user, err = a.users.SetRole(tenant.WithOrg(ctx, user.OrgID), user.ID, *role); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "%s is now a %s\n", user.Email, user.Role)
+	return nil
+}
+
+func (a *admin) userDisabled(disabled bool) func(context.Context, []string) error {
+	name := "user enable"
+	if disabled {
+		name = "user disable"
+	}
+
+	return func(ctx context.Context, args []string) error {
+		fs := cli.Flags(name, a.out)
+		email := fs.String("email", "", "email address")
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if err := cli.Required(fs, "email"); err != nil {
+			return err
+		}
+		if err := a.connect(); err != nil {
+			return err
+		}
+
+		user, err := a.userByEmail(ctx, *email)
+		if err != nil {
+			return err
+		}
+		if user, err = a.users.SetDisabled(tenant.WithOrg(ctx, user.OrgID), user.ID, disabled); err != nil {
+			return err
+		}
+		if user.DisabledAt != nil {
+			fmt.Fprintf(a.out, "%s disabled\n", user.Email)
+		} else {
+			fmt.Fprintf(a.out, "%s enabled\n", user.Email)
+		}
+		return nil
+	}
+}
+
+func (a *admin) teamAddMember(ctx context.Context, args []string) error {
+	fs := cli.Flags("team add-member", a.out)
+	teamID := fs.String("team", "", "team ID")
+	email := fs.String("user", "", "email of the user to add")
+	as := fs.String("as", "", "email of a manager of the team")
+	manager := fs.Bool("manager", false, "add the user as a manager")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := cli.Required(fs, "team", "user", "as"); err != nil {
+		return err
+	}
+	team, err := uuid.Parse(*teamID)
+	if err != nil {
+		return apperror.BadRequest("invalid team ID %q", *teamID)
+	}
+	if err := a.connect(); err != nil {
+		return err
+	}
+
+	user, err := a.userByEmail(ctx, *email)
+	if err != nil {
+		return err
+	}
+	actor, err := a.userByEmail(ctx, *as)
+	if err != nil {
+		return err
+	}
+	// Act within the manager's organisation, where the team must be too
+	ctx = tenant.WithOrg(ctx, actor.OrgID)
+
+	role := "member"
+	if *manager {
+		role = "manager"
+		err = a.teams.AddManager(ctx, team, user.ID, actor.ID)
+	} else {
+		err = a.teams.AddMember(ctx, team, user.ID, actor.ID)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "added %s to team %s as %s\n", user.Email, team, role)
+	return nil
+}
+
+func (a *admin) teamTransfer(ctx context.Context, args []string) error {
+	fs := cli.Flags("team transfer", a.out)
+	teamID := fs.String("team", "", "team ID")
+	toEmail := fs.String("to", "", "email of the new owner")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := cli.Required(fs, "team", "to"); err != nil {
+		return err
+	}
+	team, err := uuid.Parse(*teamID)
+	if err != nil {
+		return apperror.BadRequest("invalid team ID %q", *teamID)
+	}
+	if err := a.connect(); err != nil {
+		return err
+	}
+
+	to, err := a.userByEmail(ctx, *toEmail)
+	if err != nil {
+		return err
+	}
+	// Ownership stays within the new owner's organisation. The operator path
+	// makes them a manager first when needed and works for teams without an
+	// owner too.
+	ctx = tenant.WithOrg(ctx, to.OrgID)
+	if err := a.teams.AdminTransferOwnership(ctx, team, to.ID); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "team %s is now owned by %s\n", team, to.Email)
+	return nil
+}
+
+func (a *admin) eventsReplay(ctx context.Context, args []string) error {
+	fs := cli.Flags("events replay", a.out)
+	sinceFlag := fs.String("since", "", "how far back to replay, e.g. 2h, or an RFC3339 time")
+	topic := fs.String("topic", "", "topic to replay; defaults to the team activity topic")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := cli.Required(fs, "since"); err != nil {
+		return err
+	}
+	since, err := parseSince(*sinceFlag, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := a.connect(); err != nil {
+		return err
+	}
+	if *topic == "" {
+		*topic = a.kafkaCfg.KafkaTopicTeamActivity
+	}
+
+	handler := kafka.NewTeamActivityEventHandler()
+	result, err := kafka.Replay(ctx, a.kafkaCfg, *topic, since, handler.HandleEvent)
+	fmt.Fprintf(a.out, "replayed %d events from %s since %s, %d failed\n", result.Processed, *topic, since.Format(time.RFC3339), result.Failed)
+	return err
+}
+
+// userByEmail finds the user in any organisation, as emails are unique
+// across them.
+func (a *admin) userByEmail(ctx context.Context, email string) (*models.User, error) {
+	user, err := a.users.GetUserByEmail(tenant.WithoutOrg(ctx), email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.NotFound("user %s not found", email)
+	}
+	return user, err
+}
+
+// parseSince accepts a duration back from now or an absolute RFC3339 time.
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--since %q is neither a duration nor an RFC3339 time", s)
+	}
+	return t, nil
+}
+
+func generatePassword() string {
+	b := make([]byte, 18)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
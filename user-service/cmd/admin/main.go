@@ -0,0 +1,40 @@
+// Command admin runs operator tasks against user-service through its service
+// layer, so the same business rules apply and team events are still
+// published. Run it without arguments for the list of commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"shared/pkg/cli"
+	"shared/pkg/log"
+	"shared/utils"
+)
+
+func main() {
+	// Logs go to stderr so stdout only carries command output.
+	slog.SetDefault(log.New(os.Stderr, "user-admin", utils.GetEnv("LOG_LEVEL", "warn"), "text"))
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	a := &admin{out: os.Stdout}
+	defer a.close()
+
+	err := cli.Run(ctx, "admin", a.commands(), os.Args[1:], os.Stderr)
+	switch {
+	case errors.Is(err, cli.ErrUsage):
+		a.close()
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "error:", err)
+		a.close()
+		os.Exit(1)
+	}
+}
@@ -2,45 +2,76 @@ package main
 
 import (
 	"context"
-	"log"
 	"net/http"
 	"os"
 	"os/signal"
-	"sync"
 	"syscall"
 	"time"
 
+	"shared/pkg/health"
+	"shared/pkg/lifecycle"
+	"shared/pkg/log"
+	"shared/pkg/tracing"
 	"user-service/internal/app"
 	"user-service/internal/config"
 	"user-service/internal/database"
 	httpserver "user-service/internal/http"
+	"user-service/internal/kafka"
 )
 
 func main() {
+	log.Setup("user-service")
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
+	// Cancelled on SIGINT/SIGTERM to start the shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, "user-service")
+	if err != nil {
+		log.Fatal("failed to set up tracing", "error", err)
+	}
+
 	// Load configurations
 	dbCfg := config.LoadDB()
 	srvCfg := config.LoadServerConfig()
 	kafkaCfg := config.LoadKafkaConfig()
+	healthCfg := health.LoadConfig()
 
 	// Connect to database
 	db, err := database.Connect(*dbCfg)
 	if err != nil {
-		log.Fatalf("❌ Failed to connect to database: %v", err)
+		log.Fatal("failed to connect to database", "error", err)
 	}
-	log.Println("✅ Connected to database")
+	log.Info(ctx, "connected to database")
 
-	if err := database.Migrate(db); err != nil {
-		log.Fatalf("❌ Migration failed: %v", err)
+	if dbCfg.MigrateOnStart {
+		if err := database.Migrate(ctx, db); err != nil {
+			log.Fatal("migration failed", "error", err)
+		}
+		log.Info(ctx, "migrations completed")
 	}
-	log.Println("✅ Migrations completed")
 
 	// Wire up dependencies with Kafka support
-	components := app.Wire(kafkaCfg)
-	log.Println("✅ Components wired with Kafka support")
+	components := app.Wire(kafkaCfg, db)
+	log.Info(ctx, "components wired with Kafka support")
+
+	probes := health.New(healthCfg.CheckTimeout)
+	probes.Register("postgres", health.DB(db))
+	probes.Register("kafka", kafka.HealthCheck(kafkaCfg.KafkaBrokers))
+	if healthCfg.RedisAddr != "" {
+		probes.Register("redis", health.Redis(healthCfg.RedisAddr, healthCfg.RedisPassword))
+	}
 
 	engine := httpserver.NewRouter(httpserver.RouterDeps{
-		UserService: components.Users,
-		TeamService: components.Teams,
+		UserService:       components.Users,
+		TeamService:       components.Teams,
+		InvitationService: components.Invitations,
+		Health:            probes,
 	})
 
 	srv := &http.Server{
@@ -52,60 +83,26 @@ func main() {
 		MaxHeaderBytes: 1 << 20,
 	}
 
-	// Context for graceful shutdown
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	var wg sync.WaitGroup
-
-	// Start HTTP server
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		log.Printf("🚀 HTTP server listening on :%s", srvCfg.Port)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Printf("❌ HTTP server error: %v", err)
-		}
-	}()
-
-	// Start Kafka consumer
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		log.Printf("🚀 Starting Kafka consumer for topic: %s", kafkaCfg.KafkaTopicTeamActivity)
-		if err := components.Consumer.Run(ctx); err != nil {
-			log.Printf("❌ Kafka consumer error: %v", err)
-		}
-		log.Println("✅ Kafka consumer stopped")
-	}()
-
-	// Wait for interrupt signal
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
-	<-stop
-
-	log.Println("🛑 Shutting down...")
-
-	// Cancel context to stop Kafka consumer
-	cancel()
-
-	// Shutdown HTTP server gracefully
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer shutdownCancel()
-
-	if err := srv.Shutdown(shutdownCtx); err != nil {
-		log.Printf("❌ HTTP server shutdown error: %v", err)
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatal("failed to get database pool", "error", err)
 	}
 
-	// Close Kafka components
-	if err := components.Consumer.Close(); err != nil {
-		log.Printf("❌ Error closing Kafka consumer: %v", err)
+	// Components stop in reverse: readiness fails first, then the server
+	// drains, the consumer stops fetching, and the producer, pool and
+	// tracer go last once nothing uses them.
+	lc := lifecycle.New(srvCfg.ShutdownTimeout)
+	lc.Add(
+		lifecycle.Hook("tracing", nil, shutdownTracing),
+		lifecycle.Closer("postgres", sqlDB.Close),
+		lifecycle.Closer("kafka producer", components.Producer.Close),
+		lifecycle.Closer("kafka reader", components.Consumer.Close),
+		lifecycle.Runner("kafka consumer "+kafkaCfg.KafkaTopicTeamActivity, components.Consumer.Run),
+		lifecycle.HTTPServer(srv, srvCfg.ShutdownTimeout),
+		probes.Readiness(healthCfg.DrainDelay),
+	)
+	if err := lc.Run(ctx); err != nil {
+		log.Fatal("user-service stopped with errors", "error", err)
 	}
-	if err := components.Producer.Close(); err != nil {
-		log.Printf("❌ Error closing Kafka producer: %v", err)
-	}
-
-	// Wait for all goroutines to finish
-	wg.Wait()
-	log.Println("✅ Shutdown complete")
+	log.Info(context.Background(), "shutdown complete")
 }
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"os"
+	"shared/pkg/log"
+	"shared/pkg/migrate"
+	"user-service/internal/config"
+	"user-service/internal/database"
+)
+
+// runMigrate implements the migrate subcommand, see migrate.Usage.
+func runMigrate(args []string) {
+	ctx := context.Background()
+
+	db, err := database.Connect(*config.LoadDB())
+	if err != nil {
+		log.Fatal("failed to connect to database", "error", err)
+	}
+	m, err := database.NewMigrator(db)
+	if err != nil {
+		log.Fatal("failed to load migrations", "error", err)
+	}
+
+	cmd := migrate.Command{
+		Migrator: m,
+		Verify:   func() error { return database.Verify(db) },
+		Out:      os.Stdout,
+	}
+	if err := cmd.Run(ctx, args); err != nil {
+		log.Fatal("migrate failed", "error", err)
+	}
+}
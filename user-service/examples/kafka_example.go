@@ -8,6 +8,7 @@ import (
 
 	"user-service/internal/app"
 	"user-service/internal/config"
+	"user-service/internal/database"
 	"user-service/internal/models"
 
 	"github.com/google/uuid"
@@ -18,8 +19,13 @@ func main() {
 	// Load Kafka configuration
 	kafkaCfg := config.LoadKafkaConfig()
 
+	db, err := database.Connect(*config.LoadDB())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
 	// Wire up components with Kafka support
-	components := app.Wire(kafkaCfg)
+	components := app.Wire(kafkaCfg, db)
 
 	ctx := context.Background()
 
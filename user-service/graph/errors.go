@@ -0,0 +1,39 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"shared/pkg/apperror"
+	"shared/pkg/log"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ErrorPresenter reports resolver errors with the same codes the REST API
+// uses, under extensions.code, extensions.status and extensions.fields.
+// Internal errors are logged and replaced with a generic message; parse and
+// validation errors raised by gqlgen itself are left untouched.
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	if gqlErr.Err == nil {
+		return gqlErr
+	}
+
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) {
+		log.Error(ctx, "graphql resolver failed", "path", gqlErr.Path.String(), "error", err)
+		appErr = apperror.From(err)
+	}
+
+	gqlErr.Message = appErr.Message
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = map[string]any{}
+	}
+	gqlErr.Extensions["code"] = appErr.Kind
+	gqlErr.Extensions["status"] = appErr.Kind.Status()
+	if len(appErr.Fields) > 0 {
+		gqlErr.Extensions["fields"] = appErr.Fields
+	}
+	return gqlErr
+}
@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	operationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "graphql_operation_duration_seconds",
+		Help:    "GraphQL operation latency by operation type, name and result.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"type", "operation", "result"})
+
+	resolverDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "graphql_resolver_duration_seconds",
+		Help:    "GraphQL resolver latency by object, field and result.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"object", "field", "result"})
+)
+
+// Metrics is a gqlgen extension timing operations and resolvers. Only fields
+// backed by a resolver are timed; plain struct fields are left alone.
+type Metrics struct{}
+
+var (
+	_ graphql.HandlerExtension    = Metrics{}
+	_ graphql.ResponseInterceptor = Metrics{}
+	_ graphql.FieldInterceptor    = Metrics{}
+)
+
+func (Metrics) ExtensionName() string {
+	return "Metrics"
+}
+
+func (Metrics) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (Metrics) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	start := time.Now()
+	resp := next(ctx)
+	if !graphql.HasOperationContext(ctx) {
+		return resp
+	}
+
+	oc := graphql.GetOperationContext(ctx)
+	opType, name := "unknown", oc.OperationName
+	if oc.Operation != nil {
+		opType = string(oc.Operation.Operation)
+		if name == "" {
+			name = oc.Operation.Name
+		}
+	}
+	if name == "" {
+		name = "anonymous"
+	}
+
+	operationDuration.WithLabelValues(opType, name, result(resp != nil && len(resp.Errors) > 0)).Observe(time.Since(start).Seconds())
+	return resp
+}
+
+func (Metrics) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	start := time.Now()
+	res, err := next(ctx)
+	resolverDuration.WithLabelValues(fc.Object, fc.Field.Name, result(err != nil)).Observe(time.Since(start).Seconds())
+	return res, err
+}
+
+func result(failed bool) string {
+	if failed {
+		return "error"
+	}
+	return "ok"
+}
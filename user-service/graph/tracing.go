@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"context"
+	"shared/pkg/tracing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "user-service/graph"
+
+// Tracing is a gqlgen extension recording a span per operation, as a child
+// of the HTTP request span, and a span per resolver. Like Metrics it skips
+// plain struct fields, which would only add noise.
+type Tracing struct{}
+
+var (
+	_ graphql.HandlerExtension    = Tracing{}
+	_ graphql.ResponseInterceptor = Tracing{}
+	_ graphql.FieldInterceptor    = Tracing{}
+)
+
+func (Tracing) ExtensionName() string {
+	return "Tracing"
+}
+
+func (Tracing) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (Tracing) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+
+	oc := graphql.GetOperationContext(ctx)
+	opType, name := "unknown", oc.OperationName
+	if oc.Operation != nil {
+		opType = string(oc.Operation.Operation)
+		if name == "" {
+			name = oc.Operation.Name
+		}
+	}
+
+	spanName := "graphql." + opType
+	if name != "" {
+		spanName += " " + name
+	}
+	ctx, span := tracing.Tracer(instrumentationName).Start(ctx, spanName, trace.WithAttributes(
+		attribute.String("graphql.operation.type", opType),
+		attribute.String("graphql.operation.name", name),
+	))
+	defer span.End()
+
+	resp := next(ctx)
+	if resp != nil && len(resp.Errors) > 0 {
+		span.SetStatus(codes.Error, resp.Errors.Error())
+	}
+	return resp
+}
+
+func (Tracing) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	ctx, span := tracing.Tracer(instrumentationName).Start(ctx, fc.Object+"."+fc.Field.Name, trace.WithAttributes(
+		attribute.String("graphql.field.path", fc.Path().String()),
+	))
+	defer span.End()
+
+	res, err := next(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return res, err
+}
@@ -26,7 +26,7 @@ func (r *mutationResolver) Login(ctx context.Context, email string, password str
 	}
 
 	// Generate JWT token
-	token, err := utils.GenerateToken(user.ID.String(), user.Email, user.Role, user.Username)
+	token, err := utils.GenerateToken(user.ID.String(), user.OrgID.String(), user.Email, user.Role, user.Username)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate token: %w", err)
 	}
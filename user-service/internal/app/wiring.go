@@ -3,28 +3,24 @@ package app
 import (
 	"context"
 	"user-service/internal/config"
-	"user-service/internal/database"
 	"user-service/internal/kafka"
 	"user-service/internal/repository"
 	"user-service/internal/services"
+
+	"gorm.io/gorm"
 )
 
 type Components struct {
-	Cfg      *config.KafkaConfig
-	Producer kafka.Producer
-	Consumer *kafka.Consumer
-	Users    services.UserService
-	Teams    services.TeamService
+	Cfg         *config.KafkaConfig
+	Producer    kafka.Producer
+	Consumer    *kafka.Consumer
+	Users       services.UserService
+	Teams       services.TeamService
+	Invitations services.InvitationService
 }
 
-func Wire(cfg *config.KafkaConfig) *Components {
-	dbCfg := config.LoadDB()
-
-	db, err := database.Connect(*dbCfg)
-	if err != nil {
-		panic("Failed to connect to database: " + err.Error())
-	}
-
+// Wire builds the services on db, which the caller owns and closes.
+func Wire(cfg *config.KafkaConfig, db *gorm.DB) *Components {
 	// Initialize repositories
 	teamRepo := repository.NewTeamRepository(db)
 	userRepo := repository.NewUserRepository(db)
@@ -38,6 +34,13 @@ func Wire(cfg *config.KafkaConfig) *Components {
 	// Wrap base team service with event publishing
 	teamService := services.NewTeamServiceWithEvents(baseTeamService, producer, cfg.KafkaTopicTeamActivity)
 
+	// Initialize invitation service, publishing the members it adds
+	invitationService := services.NewInvitationServiceWithEvents(
+		services.NewInvitationService(repository.NewInvitationRepository(db), teamRepo, userRepo, config.LoadTeamConfig().InvitationTTL),
+		producer,
+		cfg.KafkaTopicTeamActivity,
+	)
+
 	// Initialize user service
 	userService := services.NewUserService(userRepo)
 
@@ -46,11 +49,12 @@ func Wire(cfg *config.KafkaConfig) *Components {
 	consumer := kafka.NewConsumer(cfg, cfg.KafkaTopicTeamActivity, eventHandler.HandleEvent)
 
 	return &Components{
-		Cfg:      cfg,
-		Producer: producer,
-		Teams:    teamService,
-		Users:    userService,
-		Consumer: consumer,
+		Cfg:         cfg,
+		Producer:    producer,
+		Teams:       teamService,
+		Invitations: invitationService,
+		Users:       userService,
+		Consumer:    consumer,
 	}
 }
 
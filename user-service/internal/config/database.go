@@ -2,8 +2,9 @@ package config
 
 import (
 	"fmt"
-	"log"
 	"os"
+	"shared/pkg/log"
+	"shared/utils"
 	"strconv"
 
 	"github.com/joho/godotenv"
@@ -16,6 +17,10 @@ type DatabaseConfig struct {
 	DBName    string
 	DBPort    int
 	DBSSLMode string
+
+	// MigrateOnStart applies pending migrations when the service boots.
+	// Disable it to run `migrate up` as a separate deploy step instead.
+	MigrateOnStart bool
 }
 
 var cfg *DatabaseConfig
@@ -29,7 +34,7 @@ func LoadDB() *DatabaseConfig {
 
 	port, err := strconv.Atoi(getEnv("DB_PORT", "5432"))
 	if err != nil {
-		log.Fatalf("Invalid DB_PORT: %v", err)
+		log.Fatal("invalid DB_PORT", "error", err)
 	}
 
 	cfg = &DatabaseConfig{
@@ -39,6 +44,8 @@ func LoadDB() *DatabaseConfig {
 		DBName:    getEnv("DB_NAME", "user_service"),
 		DBPort:    port,
 		DBSSLMode: getEnv("DB_SSLMODE", "disable"),
+
+		MigrateOnStart: utils.AsBool("DB_MIGRATE_ON_START", true),
 	}
 
 	return cfg
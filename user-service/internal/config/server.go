@@ -1,11 +1,20 @@
 package config
 
+import (
+	"shared/utils"
+	"time"
+)
+
 type ServerConfig struct {
 	Port string
+	// ShutdownTimeout bounds how long in-flight requests get to finish, and
+	// each other component to stop, on shutdown.
+	ShutdownTimeout time.Duration
 }
 
 func LoadServerConfig() ServerConfig {
 	return ServerConfig{
-		Port: getEnv("SERVER_PORT", "8080"),
+		Port:            getEnv("SERVER_PORT", "8080"),
+		ShutdownTimeout: utils.AsDuration("SHUTDOWN_TIMEOUT", 10*time.Second),
 	}
 }
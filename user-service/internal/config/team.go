@@ -0,0 +1,17 @@
+package config
+
+import (
+	"shared/utils"
+	"time"
+)
+
+type TeamConfig struct {
+	// InvitationTTL is how long a team invitation can be accepted.
+	InvitationTTL time.Duration
+}
+
+func LoadTeamConfig() TeamConfig {
+	return TeamConfig{
+		InvitationTTL: utils.AsDuration("TEAM_INVITATION_TTL", 7*24*time.Hour),
+	}
+}
@@ -1,6 +1,10 @@
 package database
 
 import (
+	"shared/pkg/log"
+	"shared/pkg/metrics"
+	"shared/pkg/tenant"
+	"shared/pkg/tracing"
 	"user-service/internal/config"
 
 	"gorm.io/driver/postgres"
@@ -10,10 +14,22 @@ import (
 func Connect(cfg config.DatabaseConfig) (*gorm.DB, error) {
 	dsn := cfg.DSN()
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: log.NewGormLogger(),
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	if err := db.Use(metrics.NewGormPlugin(cfg.DBName)); err != nil {
+		return nil, err
+	}
+	if err := db.Use(tracing.NewGormPlugin(cfg.DBName)); err != nil {
+		return nil, err
+	}
+	if err := db.Use(tenant.NewGormPlugin()); err != nil {
+		return nil, err
+	}
+
 	return db, nil
 }
@@ -0,0 +1,38 @@
+-- Baseline schema, matching what AutoMigrate created before versioned
+-- migrations. IF NOT EXISTS lets databases created by AutoMigrate adopt it.
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS users (
+    id uuid NOT NULL DEFAULT uuid_generate_v4(),
+    username text NOT NULL,
+    email text NOT NULL,
+    role varchar(10) NOT NULL,
+    password_hash text NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT users_pkey PRIMARY KEY (id),
+    CONSTRAINT uni_users_email UNIQUE (email)
+);
+
+CREATE TABLE IF NOT EXISTS teams (
+    id uuid NOT NULL DEFAULT uuid_generate_v4(),
+    team_name text NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT teams_pkey PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS team_members (
+    id uuid NOT NULL DEFAULT uuid_generate_v4(),
+    team_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    role varchar(10) NOT NULL,
+    joined_at timestamptz,
+    CONSTRAINT team_members_pkey PRIMARY KEY (id),
+    CONSTRAINT fk_teams_team_members FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE,
+    CONSTRAINT fk_team_members_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_members_team_id ON team_members (team_id);
+CREATE INDEX IF NOT EXISTS idx_team_members_user_id ON team_members (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_team_user ON team_members (team_id, user_id);
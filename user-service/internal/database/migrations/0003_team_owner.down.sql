@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_teams_owner_id;
+ALTER TABLE teams DROP COLUMN IF EXISTS owner_id;
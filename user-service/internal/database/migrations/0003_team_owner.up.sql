@@ -0,0 +1,12 @@
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS owner_id uuid;
+
+-- Existing teams are owned by their longest-serving manager.
+UPDATE teams SET owner_id = (
+    SELECT user_id FROM team_members
+    WHERE team_members.team_id = teams.id AND team_members.role = 'manager'
+    ORDER BY joined_at, id
+    LIMIT 1
+)
+WHERE owner_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_teams_owner_id ON teams (owner_id);
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS team_invitations (
+    id uuid NOT NULL DEFAULT uuid_generate_v4(),
+    team_id uuid NOT NULL,
+    inviter_id uuid NOT NULL,
+    invitee_id uuid,
+    email text NOT NULL,
+    status varchar(10) NOT NULL,
+    expires_at timestamptz NOT NULL,
+    responded_at timestamptz,
+    created_at timestamptz,
+    CONSTRAINT team_invitations_pkey PRIMARY KEY (id),
+    CONSTRAINT fk_team_invitations_team FOREIGN KEY (team_id) REFERENCES teams (id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_invitations_team_id ON team_invitations (team_id);
+CREATE INDEX IF NOT EXISTS idx_team_invitations_invitee_id ON team_invitations (invitee_id);
+CREATE INDEX IF NOT EXISTS idx_team_invitations_email ON team_invitations (email);
+
+-- At most one open invitation per team and address.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_team_invitations_pending
+    ON team_invitations (team_id, email) WHERE status = 'pending';
@@ -0,0 +1,7 @@
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS parent_id uuid;
+
+-- A team with sub-teams can't be deleted until they are moved or deleted.
+ALTER TABLE teams ADD CONSTRAINT fk_teams_parent
+    FOREIGN KEY (parent_id) REFERENCES teams (id) ON DELETE RESTRICT;
+
+CREATE INDEX IF NOT EXISTS idx_teams_parent_id ON teams (parent_id);
@@ -0,0 +1,5 @@
+ALTER TABLE team_invitations DROP COLUMN IF EXISTS org_id;
+ALTER TABLE team_members DROP COLUMN IF EXISTS org_id;
+ALTER TABLE teams DROP COLUMN IF EXISTS org_id;
+ALTER TABLE users DROP COLUMN IF EXISTS org_id;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,36 @@
+CREATE TABLE IF NOT EXISTS organizations (
+    id uuid NOT NULL DEFAULT uuid_generate_v4(),
+    name text NOT NULL,
+    created_at timestamptz,
+    CONSTRAINT organizations_pkey PRIMARY KEY (id)
+);
+
+-- Everything created before organisations existed belongs to one default
+-- organisation. asset-service backfills its rows with the same ID.
+INSERT INTO organizations (id, name, created_at)
+VALUES ('00000000-0000-0000-0000-000000000001', 'Default', now())
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS org_id uuid;
+UPDATE users SET org_id = '00000000-0000-0000-0000-000000000001' WHERE org_id IS NULL;
+ALTER TABLE users ALTER COLUMN org_id SET NOT NULL;
+ALTER TABLE users ADD CONSTRAINT fk_users_org FOREIGN KEY (org_id) REFERENCES organizations (id);
+CREATE INDEX IF NOT EXISTS idx_users_org_id ON users (org_id);
+
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS org_id uuid;
+UPDATE teams SET org_id = '00000000-0000-0000-0000-000000000001' WHERE org_id IS NULL;
+ALTER TABLE teams ALTER COLUMN org_id SET NOT NULL;
+ALTER TABLE teams ADD CONSTRAINT fk_teams_org FOREIGN KEY (org_id) REFERENCES organizations (id);
+CREATE INDEX IF NOT EXISTS idx_teams_org_id ON teams (org_id);
+
+-- Memberships and invitations carry their team's organisation so that they
+-- are scoped like everything else.
+ALTER TABLE team_members ADD COLUMN IF NOT EXISTS org_id uuid;
+UPDATE team_members SET org_id = teams.org_id FROM teams WHERE teams.id = team_members.team_id AND team_members.org_id IS NULL;
+ALTER TABLE team_members ALTER COLUMN org_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_team_members_org_id ON team_members (org_id);
+
+ALTER TABLE team_invitations ADD COLUMN IF NOT EXISTS org_id uuid;
+UPDATE team_invitations SET org_id = teams.org_id FROM teams WHERE teams.id = team_invitations.team_id AND team_invitations.org_id IS NULL;
+ALTER TABLE team_invitations ALTER COLUMN org_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_team_invitations_org_id ON team_invitations (org_id);
@@ -5,13 +5,15 @@ import (
 )
 
 type Handlers struct {
-	TeamHandler *TeamHandler
-	UserService services.UserService
+	TeamHandler       *TeamHandler
+	InvitationHandler *InvitationHandler
+	UserService       services.UserService
 }
 
-func NewHandlers(userService services.UserService, teamService services.TeamService) *Handlers {
+func NewHandlers(userService services.UserService, teamService services.TeamService, invitationService services.InvitationService) *Handlers {
 	return &Handlers{
-		TeamHandler: NewTeamHandler(teamService),
-		UserService: userService,
+		TeamHandler:       NewTeamHandler(teamService, invitationService),
+		InvitationHandler: NewInvitationHandler(invitationService),
+		UserService:       userService,
 	}
 }
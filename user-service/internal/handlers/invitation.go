@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"shared/middlewares"
+	"shared/pkg/apperror"
+	"user-service/internal/models"
+	"user-service/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type InvitationHandler struct {
+	InvitationService services.InvitationService
+}
+
+func NewInvitationHandler(invitationService services.InvitationService) *InvitationHandler {
+	return &InvitationHandler{
+		InvitationService: invitationService,
+	}
+}
+
+func (h *InvitationHandler) Invite(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("teamId"))
+	if err != nil {
+		c.Error(apperror.BadRequest("invalid team ID"))
+		return
+	}
+
+	var req models.InviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.BadRequest("%v", err))
+		return
+	}
+
+	requestorUUID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	invitation, err := h.InvitationService.Invite(c.Request.Context(), teamID, req, requestorUUID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, invitation)
+}
+
+func (h *InvitationHandler) ListTeamInvitations(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("teamId"))
+	if err != nil {
+		c.Error(apperror.BadRequest("invalid team ID"))
+		return
+	}
+
+	requestorUUID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	invitations, err := h.InvitationService.ListTeamInvitations(c.Request.Context(), teamID, requestorUUID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invitations": invitations})
+}
+
+func (h *InvitationHandler) RevokeInvitation(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("teamId"))
+	if err != nil {
+		c.Error(apperror.BadRequest("invalid team ID"))
+		return
+	}
+
+	invitationID, err := uuid.Parse(c.Param("invitationId"))
+	if err != nil {
+		c.Error(apperror.BadRequest("invalid invitation ID"))
+		return
+	}
+
+	requestorUUID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	if err := h.InvitationService.RevokeInvitation(c.Request.Context(), teamID, invitationID, requestorUUID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation revoked successfully"})
+}
+
+func (h *InvitationHandler) ListMyInvitations(c *gin.Context) {
+	userUUID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	invitations, err := h.InvitationService.ListMyInvitations(c.Request.Context(), userUUID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invitations": invitations})
+}
+
+func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
+	h.respond(c, h.InvitationService.AcceptInvitation)
+}
+
+func (h *InvitationHandler) DeclineInvitation(c *gin.Context) {
+	h.respond(c, h.InvitationService.DeclineInvitation)
+}
+
+// respond answers the invitation in the path on behalf of the caller.
+func (h *InvitationHandler) respond(c *gin.Context, answer func(ctx context.Context, invitationID, userID uuid.UUID) (*models.InvitationResponse, error)) {
+	invitationID, err := uuid.Parse(c.Param("invitationId"))
+	if err != nil {
+		c.Error(apperror.BadRequest("invalid invitation ID"))
+		return
+	}
+
+	userUUID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	invitation, err := answer(c.Request.Context(), invitationID, userUUID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, invitation)
+}
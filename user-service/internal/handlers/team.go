@@ -1,7 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+	"shared/middlewares"
+	"shared/pkg/apperror"
+	"shared/pkg/listing"
+	"strconv"
 	"user-service/internal/models"
 	"user-service/internal/services"
 
@@ -10,37 +15,32 @@ import (
 )
 
 type TeamHandler struct {
-	TeamService services.TeamService
+	TeamService       services.TeamService
+	InvitationService services.InvitationService
 }
 
-func NewTeamHandler(teamService services.TeamService) *TeamHandler {
+func NewTeamHandler(teamService services.TeamService, invitationService services.InvitationService) *TeamHandler {
 	return &TeamHandler{
-		TeamService: teamService,
+		TeamService:       teamService,
+		InvitationService: invitationService,
 	}
 }
 
 func (h *TeamHandler) CreateTeam(c *gin.Context) {
 	var req models.CreateTeamRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperror.BadRequest("%v", err))
 		return
 	}
 
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
-		return
-	}
-
-	creatorID, ok := userID.(uuid.UUID)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+	creatorID, err := middlewares.ExtractUserID(c)
+	if err != nil {
 		return
 	}
 
 	teamResponse, err := h.TeamService.CreateTeam(c.Request.Context(), req, creatorID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -51,13 +51,13 @@ func (h *TeamHandler) GetTeam(c *gin.Context) {
 	teamIDParam := c.Param("teamId")
 	teamID, err := uuid.Parse(teamIDParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		c.Error(apperror.BadRequest("invalid team ID"))
 		return
 	}
 
 	teamResponse, err := h.TeamService.GetTeamByID(c.Request.Context(), teamID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+		c.Error(err)
 		return
 	}
 
@@ -65,173 +65,358 @@ func (h *TeamHandler) GetTeam(c *gin.Context) {
 }
 
 func (h *TeamHandler) GetAllTeams(c *gin.Context) {
-	requestorID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	requestorUUID, err := middlewares.ExtractUserID(c)
+	if err != nil {
 		return
 	}
 
-	requestorUUID, ok := requestorID.(uuid.UUID)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+	q := models.TeamListQuery{Search: c.Query("search"), Role: c.Query("role")}
+	if v := c.Query("includeArchived"); v != "" {
+		includeArchived, err := strconv.ParseBool(v)
+		if err != nil {
+			c.Error(apperror.BadRequest("invalid includeArchived %q", v))
+			return
+		}
+		q.IncludeArchived = includeArchived
+	}
+	if q.Limit, err = queryInt(c, "limit"); err != nil {
+		return
+	}
+	if q.After, err = queryCursor(c); err != nil {
 		return
 	}
 
-	teams, err := h.TeamService.GetAllTeams(c.Request.Context(), requestorUUID)
+	teams, next, err := h.TeamService.GetAllTeams(c.Request.Context(), requestorUUID, q)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"teams": teams})
+	listing.WriteList(c, teams, next)
 }
 
+// AddMember invites the user rather than adding them: they join once they
+// accept, as with POST /teams/{teamId}/invitations. Adding a user without
+// their consent is left to operators, through cmd/admin team add-member.
 func (h *TeamHandler) AddMember(c *gin.Context) {
+	teamID, err := uuid.Parse(c.Param("teamId"))
+	if err != nil {
+		c.Error(apperror.BadRequest("invalid team ID"))
+		return
+	}
+
+	var req models.AddMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.BadRequest("%v", err))
+		return
+	}
+
+	requestorUUID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	invitation, err := h.InvitationService.Invite(c.Request.Context(), teamID, models.InviteRequest{UserID: req.UserID}, requestorUUID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, invitation)
+}
+
+func (h *TeamHandler) RemoveMember(c *gin.Context) {
 	teamIDParam := c.Param("teamId")
 	teamID, err := uuid.Parse(teamIDParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		c.Error(apperror.BadRequest("invalid team ID"))
+		return
+	}
+
+	memberIDParam := c.Param("memberId")
+	memberID, err := uuid.Parse(memberIDParam)
+	if err != nil {
+		c.Error(apperror.BadRequest("invalid member ID"))
+		return
+	}
+
+	requestorUUID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	if err := h.TeamService.RemoveMember(c.Request.Context(), teamID, memberID, requestorUUID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed successfully"})
+}
+
+func (h *TeamHandler) AddManager(c *gin.Context) {
+	teamIDParam := c.Param("teamId")
+	teamID, err := uuid.Parse(teamIDParam)
+	if err != nil {
+		c.Error(apperror.BadRequest("invalid team ID"))
 		return
 	}
 
 	var req models.AddMemberRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperror.BadRequest("%v", err))
 		return
 	}
 
 	userID, err := uuid.Parse(req.UserID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		c.Error(apperror.BadRequest("invalid user ID"))
+		return
+	}
+
+	requestorUUID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	if err := h.TeamService.AddManager(c.Request.Context(), teamID, userID, requestorUUID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Manager added successfully"})
+}
+
+func (h *TeamHandler) RemoveManager(c *gin.Context) {
+	teamIDParam := c.Param("teamId")
+	teamID, err := uuid.Parse(teamIDParam)
+	if err != nil {
+		c.Error(apperror.BadRequest("invalid team ID"))
 		return
 	}
 
-	requestorID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	managerIDParam := c.Param("managerId")
+	managerID, err := uuid.Parse(managerIDParam)
+	if err != nil {
+		c.Error(apperror.BadRequest("invalid manager ID"))
 		return
 	}
 
-	requestorUUID, ok := requestorID.(uuid.UUID)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+	requestorUUID, err := middlewares.ExtractUserID(c)
+	if err != nil {
 		return
 	}
 
-	if err := h.TeamService.AddMember(c.Request.Context(), teamID, userID, requestorUUID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.TeamService.RemoveManager(c.Request.Context(), teamID, managerID, requestorUUID); err != nil {
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Member added successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Manager removed successfully"})
 }
 
-func (h *TeamHandler) RemoveMember(c *gin.Context) {
+func (h *TeamHandler) TransferOwnership(c *gin.Context) {
 	teamIDParam := c.Param("teamId")
 	teamID, err := uuid.Parse(teamIDParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		c.Error(apperror.BadRequest("invalid team ID"))
 		return
 	}
 
-	memberIDParam := c.Param("memberId")
-	memberID, err := uuid.Parse(memberIDParam)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid member ID"})
+	var req models.TransferOwnershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.BadRequest("%v", err))
 		return
 	}
 
-	requestorID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	newOwnerID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		c.Error(apperror.BadRequest("invalid user ID"))
 		return
 	}
 
-	requestorUUID, ok := requestorID.(uuid.UUID)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+	requestorUUID, err := middlewares.ExtractUserID(c)
+	if err != nil {
 		return
 	}
 
-	if err := h.TeamService.RemoveMember(c.Request.Context(), teamID, memberID, requestorUUID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.TeamService.TransferOwnership(c.Request.Context(), teamID, newOwnerID, requestorUUID); err != nil {
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Member removed successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Ownership transferred successfully"})
 }
 
-func (h *TeamHandler) AddManager(c *gin.Context) {
+func (h *TeamHandler) UpdateTeam(c *gin.Context) {
 	teamIDParam := c.Param("teamId")
 	teamID, err := uuid.Parse(teamIDParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		c.Error(apperror.BadRequest("invalid team ID"))
 		return
 	}
 
-	var req models.AddMemberRequest
+	var req models.UpdateTeamRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperror.BadRequest("%v", err))
 		return
 	}
 
-	userID, err := uuid.Parse(req.UserID)
+	requestorUUID, err := middlewares.ExtractUserID(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
 		return
 	}
 
-	requestorID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	teamResponse, err := h.TeamService.UpdateTeam(c.Request.Context(), teamID, req, requestorUUID)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	requestorUUID, ok := requestorID.(uuid.UUID)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+	c.JSON(http.StatusOK, teamResponse)
+}
+
+func (h *TeamHandler) ArchiveTeam(c *gin.Context) {
+	h.changeTeam(c, h.TeamService.ArchiveTeam, "Team archived successfully")
+}
+
+func (h *TeamHandler) UnarchiveTeam(c *gin.Context) {
+	h.changeTeam(c, h.TeamService.UnarchiveTeam, "Team unarchived successfully")
+}
+
+func (h *TeamHandler) DeleteTeam(c *gin.Context) {
+	h.changeTeam(c, h.TeamService.DeleteTeam, "Team deleted successfully")
+}
+
+// changeTeam runs a body-less operation on the team in the path on behalf of
+// the caller and answers with message.
+func (h *TeamHandler) changeTeam(c *gin.Context, change func(ctx context.Context, teamID, requestorID uuid.UUID) error, message string) {
+	teamIDParam := c.Param("teamId")
+	teamID, err := uuid.Parse(teamIDParam)
+	if err != nil {
+		c.Error(apperror.BadRequest("invalid team ID"))
 		return
 	}
 
-	if err := h.TeamService.AddManager(c.Request.Context(), teamID, userID, requestorUUID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	requestorUUID, err := middlewares.ExtractUserID(c)
+	if err != nil {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Manager added successfully"})
+	if err := change(c.Request.Context(), teamID, requestorUUID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
 }
 
-func (h *TeamHandler) RemoveManager(c *gin.Context) {
+// SetParent moves the team under the team in the body, or to the top level
+// when parentId is empty or null.
+func (h *TeamHandler) SetParent(c *gin.Context) {
 	teamIDParam := c.Param("teamId")
 	teamID, err := uuid.Parse(teamIDParam)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		c.Error(apperror.BadRequest("invalid team ID"))
 		return
 	}
 
-	managerIDParam := c.Param("managerId")
-	managerID, err := uuid.Parse(managerIDParam)
+	var req models.SetParentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.BadRequest("%v", err))
+		return
+	}
+
+	var parentID *uuid.UUID
+	if req.ParentID != "" {
+		id, err := uuid.Parse(req.ParentID)
+		if err != nil {
+			c.Error(apperror.BadRequest("invalid parent team ID"))
+			return
+		}
+		parentID = &id
+	}
+
+	requestorUUID, err := middlewares.ExtractUserID(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid manager ID"})
 		return
 	}
 
-	requestorID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+	teamResponse, err := h.TeamService.SetParentTeam(c.Request.Context(), teamID, parentID, requestorUUID)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	requestorUUID, ok := requestorID.(uuid.UUID)
-	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user ID format"})
+	c.JSON(http.StatusOK, teamResponse)
+}
+
+func (h *TeamHandler) GetAncestors(c *gin.Context) {
+	h.teamHierarchy(c, h.TeamService.GetAncestors)
+}
+
+func (h *TeamHandler) GetDescendants(c *gin.Context) {
+	h.teamHierarchy(c, h.TeamService.GetDescendants)
+}
+
+// teamHierarchy serves a listing of the teams around the team in the path.
+func (h *TeamHandler) teamHierarchy(c *gin.Context, list func(ctx context.Context, teamID uuid.UUID) ([]*models.TeamNodeResponse, error)) {
+	teamIDParam := c.Param("teamId")
+	teamID, err := uuid.Parse(teamIDParam)
+	if err != nil {
+		c.Error(apperror.BadRequest("invalid team ID"))
 		return
 	}
 
-	if err := h.TeamService.RemoveManager(c.Request.Context(), teamID, managerID, requestorUUID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	teams, err := list(c.Request.Context(), teamID)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Manager removed successfully"})
+	c.JSON(http.StatusOK, teams)
+}
+
+// TeamAction serves the custom methods on a team, such as
+// POST /teams/:teamId/members:batch. Their colon can't be part of a static
+// gin route, so they share the :action segment.
+func (h *TeamHandler) TeamAction(c *gin.Context) {
+	switch c.Param("action") {
+	case "members:batch":
+		h.BatchMembership(c)
+	default:
+		c.Error(apperror.NotFound("unknown team action %q", c.Param("action")))
+	}
+}
+
+func (h *TeamHandler) BatchMembership(c *gin.Context) {
+	teamIDParam := c.Param("teamId")
+	teamID, err := uuid.Parse(teamIDParam)
+	if err != nil {
+		c.Error(apperror.BadRequest("invalid team ID"))
+		return
+	}
+
+	var req models.BatchMembershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.BadRequest("%v", err))
+		return
+	}
+
+	requestorUUID, err := middlewares.ExtractUserID(c)
+	if err != nil {
+		return
+	}
+
+	resp, err := h.TeamService.BatchMembership(c.Request.Context(), teamID, req, requestorUUID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// A rejected batch still answers with the per-operation results
+	status := http.StatusOK
+	if !resp.Applied {
+		status = http.StatusUnprocessableEntity
+	}
+	c.JSON(status, resp)
 }
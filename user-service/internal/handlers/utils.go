@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"shared/pkg/apperror"
+	"shared/pkg/listing"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// queryInt returns the integer query parameter, or 0 when it is absent. On
+// failure the error is recorded on the context like in
+// middlewares.ExtractUserID.
+func queryInt(c *gin.Context, name string) (int, error) {
+	v := c.Query(name)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		err = apperror.BadRequest("invalid %s %q", name, v)
+		c.Error(err)
+	}
+	return n, err
+}
+
+// queryCursor returns the cursor query parameter, or nil when it is absent.
+// On failure the error is recorded on the context like in queryInt.
+func queryCursor(c *gin.Context) (*listing.Cursor, error) {
+	cursor, err := listing.CursorParam(c)
+	if err != nil {
+		c.Error(err)
+	}
+	return cursor, err
+}
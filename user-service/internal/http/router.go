@@ -1,8 +1,12 @@
 package httpserver
 
 import (
-	"net/http"
 	"shared/middlewares"
+	"shared/pkg/health"
+	"shared/pkg/listing"
+	"shared/pkg/log"
+	"shared/pkg/metrics"
+	"shared/pkg/tracing"
 	"user-service/graph"
 	"user-service/graph/generated"
 	"user-service/internal/handlers"
@@ -15,46 +19,77 @@ import (
 )
 
 type RouterDeps struct {
-	UserService services.UserService
-	TeamService services.TeamService
+	UserService       services.UserService
+	TeamService       services.TeamService
+	InvitationService services.InvitationService
+	Health            *health.Health
 }
 
 func NewRouter(deps RouterDeps) *gin.Engine {
 	r := gin.New()
+	r.Use(middlewares.RequestLogger())
+	r.Use(tracing.Middleware())
+	r.Use(metrics.HTTPMiddleware())
 	r.Use(gin.Recovery())
+	r.Use(middlewares.ErrorHandler())
 
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000", "http://localhost:5173", "http://localhost:5174", "http://localhost:4173"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept", "X-Requested-With"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "Accept", "X-Requested-With", log.RequestIDHeader, "traceparent", "tracestate"},
+		ExposeHeaders:    []string{"Content-Length", "Link", listing.NextCursorHeader, log.RequestIDHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * 3600, // 12 hours
 	}))
 
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok"})
-	})
+	r.GET("/metrics", metrics.Handler())
 
-	h := handlers.NewHandlers(deps.UserService, deps.TeamService)
+	r.GET("/livez", deps.Health.Livez())
+	r.GET("/readyz", deps.Health.Readyz())
+	// Kept for existing probes; new ones should use /livez and /readyz.
+	r.GET("/health", deps.Health.Livez())
+
+	h := handlers.NewHandlers(deps.UserService, deps.TeamService, deps.InvitationService)
+	auth := middlewares.AuthMiddleware(deps.UserService.CheckActive)
 
 	userGroup := r.Group("/user")
-	userGroup.Use(middlewares.AuthMiddleware())
+	userGroup.Use(auth)
 	{
 		userGroup.POST("/query", graphQLHandler(deps.UserService))
 		userGroup.GET("/query", graphQLPlayground())
 	}
 
 	teamsGroup := r.Group("/teams")
-	teamsGroup.Use(middlewares.AuthMiddleware())
+	teamsGroup.Use(auth)
 	{
 		teamsGroup.GET("", h.TeamHandler.GetAllTeams)
 		teamsGroup.POST("", h.TeamHandler.CreateTeam)
 		teamsGroup.GET("/:teamId", h.TeamHandler.GetTeam)
+		teamsGroup.PUT("/:teamId", h.TeamHandler.UpdateTeam)
+		teamsGroup.DELETE("/:teamId", h.TeamHandler.DeleteTeam)
+		teamsGroup.POST("/:teamId/archive", h.TeamHandler.ArchiveTeam)
+		teamsGroup.POST("/:teamId/unarchive", h.TeamHandler.UnarchiveTeam)
 		teamsGroup.POST("/:teamId/members", h.TeamHandler.AddMember)
 		teamsGroup.DELETE("/:teamId/members/:memberId", h.TeamHandler.RemoveMember)
 		teamsGroup.POST("/:teamId/managers", h.TeamHandler.AddManager)
 		teamsGroup.DELETE("/:teamId/managers/:managerId", h.TeamHandler.RemoveManager)
+		teamsGroup.PUT("/:teamId/owner", h.TeamHandler.TransferOwnership)
+		teamsGroup.PUT("/:teamId/parent", h.TeamHandler.SetParent)
+		teamsGroup.GET("/:teamId/ancestors", h.TeamHandler.GetAncestors)
+		teamsGroup.GET("/:teamId/descendants", h.TeamHandler.GetDescendants)
+		teamsGroup.POST("/:teamId/invitations", h.InvitationHandler.Invite)
+		teamsGroup.GET("/:teamId/invitations", h.InvitationHandler.ListTeamInvitations)
+		teamsGroup.DELETE("/:teamId/invitations/:invitationId", h.InvitationHandler.RevokeInvitation)
+		// Custom methods such as members:batch; static routes above take precedence
+		teamsGroup.POST("/:teamId/:action", h.TeamHandler.TeamAction)
+	}
+
+	invitationsGroup := r.Group("/invitations")
+	invitationsGroup.Use(auth)
+	{
+		invitationsGroup.GET("", h.InvitationHandler.ListMyInvitations)
+		invitationsGroup.POST("/:invitationId/accept", h.InvitationHandler.AcceptInvitation)
+		invitationsGroup.POST("/:invitationId/decline", h.InvitationHandler.DeclineInvitation)
 	}
 
 	return r
@@ -69,5 +104,8 @@ func graphQLHandler(userService services.UserService) gin.HandlerFunc {
 	srv := handler.NewDefaultServer(generated.NewExecutableSchema(generated.Config{
 		Resolvers: &graph.Resolver{UserService: userService},
 	}))
+	srv.SetErrorPresenter(graph.ErrorPresenter)
+	srv.Use(graph.Metrics{})
+	srv.Use(graph.Tracing{})
 	return gin.WrapH(srv)
 }
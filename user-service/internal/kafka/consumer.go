@@ -4,9 +4,14 @@ import (
 	"context"
 	"errors"
 	"shared/pkg/log"
+	"time"
 	"user-service/internal/config"
 
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type HandlerFunc func(ctx context.Context, key []byte, value []byte) error
@@ -38,22 +43,53 @@ func (c *Consumer) Run(ctx context.Context) error {
 			if errors.Is(err, context.Canceled) {
 				return nil
 			}
-			log.Error.Printf("fetch error: %v", err)
+			log.Error(ctx, "kafka fetch failed", "topic", c.r.Config().Topic, "error", err)
 			continue
 		}
 
-		if err := c.handler(ctx, m.Key, m.Value); err != nil {
-			log.Error.Printf("handler error (no commit): %v", err)
+		// HighWaterMark is the offset of the next message to be written.
+		consumerLag.WithLabelValues(m.Topic, partitionLabel(m.Partition)).Set(float64(max(m.HighWaterMark-m.Offset-1, 0)))
+
+		msgCtx, span := tracer().Start(messageContext(ctx, m), "process "+m.Topic,
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(
+				attribute.String("messaging.system", "kafka"),
+				attribute.String("messaging.destination.name", m.Topic),
+				attribute.String("messaging.operation.type", "process"),
+				attribute.Int("messaging.destination.partition.id", m.Partition),
+				attribute.Int64("messaging.kafka.offset", m.Offset),
+			),
+		)
+		start := time.Now()
+		err = c.handler(msgCtx, m.Key, m.Value)
+		consumeDuration.WithLabelValues(m.Topic, result(err)).Observe(time.Since(start).Seconds())
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			log.Error(msgCtx, "kafka handler failed, message not committed", "topic", m.Topic, "partition", m.Partition, "offset", m.Offset, "error", err)
+			span.End()
 			// Optionally: dead-letter here by producing to a DLQ topic
 			// _ = dlq.Publish(ctx, "orders.dlq", m.Key, map[string]any{"raw": string(m.Value), "error": err.Error()})
 			continue
 		}
 
 		if err := c.r.CommitMessages(ctx, m); err != nil {
-			log.Error.Printf("commit error: %v", err)
-			continue
+			log.Error(msgCtx, "kafka commit failed", "topic", m.Topic, "partition", m.Partition, "offset", m.Offset, "error", err)
+		}
+		span.End()
+	}
+}
+
+// messageContext returns ctx carrying the trace context and request ID from
+// the message headers; messages published outside a request carry neither.
+func messageContext(ctx context.Context, m kafka.Message) context.Context {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, headerCarrier{&m.Headers})
+	for _, h := range m.Headers {
+		if h.Key == log.RequestIDHeader && len(h.Value) > 0 {
+			return log.WithRequestID(ctx, string(h.Value))
 		}
 	}
+	return ctx
 }
 
 func (c *Consumer) Close() error {
@@ -0,0 +1,53 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"shared/pkg/health"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// HealthCheck checks that at least one of brokers answers a metadata
+// request and knows of at least one broker in the cluster. It fails when no
+// brokers are configured.
+func HealthCheck(brokers []string) health.Checker {
+	return health.CheckerFunc(func(ctx context.Context) error {
+		if len(brokers) == 0 {
+			return errors.New("no brokers configured")
+		}
+
+		var errs []error
+		for _, addr := range brokers {
+			err := brokerMetadata(ctx, addr)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", addr, err))
+		}
+		return errors.Join(errs...)
+	})
+}
+
+func brokerMetadata(ctx context.Context, addr string) error {
+	conn, err := kafka.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+
+	brokers, err := conn.Brokers()
+	if err != nil {
+		return err
+	}
+	if len(brokers) == 0 {
+		return errors.New("no brokers in cluster metadata")
+	}
+	return nil
+}
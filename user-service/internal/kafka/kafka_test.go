@@ -3,10 +3,15 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"shared/pkg/log"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestTeamActivityEvent_JSON(t *testing.T) {
@@ -136,3 +141,42 @@ func TestEventConstants(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestMessageContext_CarriesRequestID(t *testing.T) {
+	m := kafka.Message{Headers: []kafka.Header{{Key: log.RequestIDHeader, Value: []byte("req-123")}}}
+
+	ctx := messageContext(context.Background(), m)
+	if got := log.RequestID(ctx); got != "req-123" {
+		t.Errorf("Expected request ID req-123, got %q", got)
+	}
+
+	if got := log.RequestID(messageContext(context.Background(), kafka.Message{})); got != "" {
+		t.Errorf("Expected no request ID without a header, got %q", got)
+	}
+}
+
+func TestMessageContext_CarriesTraceContext(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SpanID:     trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	var m kafka.Message
+	otel.GetTextMapPropagator().Inject(trace.ContextWithSpanContext(context.Background(), sc), headerCarrier{&m.Headers})
+
+	got := trace.SpanContextFromContext(messageContext(context.Background(), m))
+	if got.TraceID() != sc.TraceID() || got.SpanID() != sc.SpanID() {
+		t.Errorf("Expected span context %v to survive the headers, got %v", sc, got)
+	}
+	if !got.IsRemote() {
+		t.Error("Expected the extracted span context to be remote")
+	}
+}
+
+func TestHealthCheck_FailsWithoutBrokers(t *testing.T) {
+	if err := HealthCheck(nil).Check(context.Background()); err == nil {
+		t.Error("Expected the check to fail when no brokers are configured")
+	}
+}
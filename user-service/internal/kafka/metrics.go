@@ -0,0 +1,43 @@
+package kafka
+
+import (
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	publishDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kafka_publish_duration_seconds",
+		Help:    "Time to publish a message by topic and result.",
+		Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"topic", "result"})
+
+	publishErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "kafka_publish_errors_total",
+		Help: "Messages that failed to publish by topic.",
+	}, []string{"topic"})
+
+	consumeDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kafka_consume_duration_seconds",
+		Help:    "Time to process a consumed message by topic and result.",
+		Buckets: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"topic", "result"})
+
+	consumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kafka_consumer_lag",
+		Help: "Messages behind the partition's high watermark after the last fetch.",
+	}, []string{"topic", "partition"})
+)
+
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+func partitionLabel(partition int) string {
+	return strconv.Itoa(partition)
+}
@@ -8,6 +8,10 @@ import (
 	"user-service/internal/config"
 
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Producer interface {
@@ -32,23 +36,42 @@ func NewProducer(cfg *config.KafkaConfig) Producer {
 }
 
 func (p *producer) Publish(ctx context.Context, topic string, key []byte, v any) error {
+	ctx, span := tracer().Start(ctx, "publish "+topic,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", topic),
+			attribute.String("messaging.operation.type", "send"),
+		),
+	)
+	defer span.End()
+
 	b, err := json.Marshal(v)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return err
 	}
 	msg := kafka.Message{
+		Topic: topic,
 		Time:  time.Now(),
 		Key:   key,
 		Value: b,
 	}
-	err = p.w.WriteMessages(ctx, kafka.Message{
-		Topic: topic,
-		Key:   msg.Key,
-		Value: msg.Value,
-		Time:  msg.Time,
-	})
+	// Carry the request ID so consumer logs correlate with the request that
+	// produced the message.
+	if id := log.RequestID(ctx); id != "" {
+		msg.Headers = append(msg.Headers, kafka.Header{Key: log.RequestIDHeader, Value: []byte(id)})
+	}
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier{&msg.Headers})
+	start := time.Now()
+	err = p.w.WriteMessages(ctx, msg)
+	publishDuration.WithLabelValues(topic, result(err)).Observe(time.Since(start).Seconds())
 	if err != nil {
-		log.Error.Printf("producer write error: %v", err)
+		publishErrors.WithLabelValues(topic).Inc()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		log.Error(ctx, "kafka publish failed", "topic", topic, "error", err)
 	}
 	return err
 }
@@ -1,11 +1,10 @@
-package handlers
+package kafka
 
 import (
 	"context"
 	"encoding/json"
 	"fmt"
 	"shared/pkg/log"
-	"user-service/internal/kafka"
 )
 
 // TeamActivityEventHandler handles team activity events
@@ -22,32 +21,40 @@ func NewTeamActivityEventHandler() *TeamActivityEventHandler {
 
 // HandleEvent processes a team activity event
 func (h *TeamActivityEventHandler) HandleEvent(ctx context.Context, key []byte, value []byte) error {
-	var event kafka.TeamActivityEvent
+	var event TeamActivityEvent
 	if err := json.Unmarshal(value, &event); err != nil {
 		return fmt.Errorf("failed to unmarshal team activity event: %w", err)
 	}
 
-	log.Info.Printf("Processing team activity event: %s for team %s", event.EventType, event.TeamID)
+	log.Info(ctx, "processing team activity event", "event_type", event.EventType, "team_id", event.TeamID)
 
 	switch event.EventType {
-	case kafka.EventTypeTeamCreated:
+	case EventTypeTeamCreated:
 		return h.handleTeamCreated(ctx, event)
-	case kafka.EventTypeMemberAdded:
+	case EventTypeMemberAdded:
 		return h.handleMemberAdded(ctx, event)
-	case kafka.EventTypeMemberRemoved:
+	case EventTypeMemberRemoved:
 		return h.handleMemberRemoved(ctx, event)
-	case kafka.EventTypeManagerAdded:
+	case EventTypeManagerAdded:
 		return h.handleManagerAdded(ctx, event)
-	case kafka.EventTypeManagerRemoved:
+	case EventTypeManagerRemoved:
 		return h.handleManagerRemoved(ctx, event)
+	case EventTypeOwnershipTransferred:
+		return h.handleOwnershipTransferred(ctx, event)
+	case EventTypeMemberRoleChanged:
+		return h.handleMemberRoleChanged(ctx, event)
+	case EventTypeTeamMoved:
+		return h.handleTeamMoved(ctx, event)
+	case EventTypeTeamUpdated, EventTypeTeamArchived, EventTypeTeamUnarchived, EventTypeTeamDeleted:
+		return h.handleTeamChanged(ctx, event)
 	default:
-		log.Info.Printf("Unknown event type: %s", event.EventType)
+		log.Warn(ctx, "unknown team activity event type", "event_type", event.EventType)
 		return nil // Don't fail on unknown events
 	}
 }
 
-func (h *TeamActivityEventHandler) handleTeamCreated(ctx context.Context, event kafka.TeamActivityEvent) error {
-	log.Info.Printf("Team created: %s by user %s", event.TeamID, event.PerformedBy)
+func (h *TeamActivityEventHandler) handleTeamCreated(ctx context.Context, event TeamActivityEvent) error {
+	log.Info(ctx, "team created", "team_id", event.TeamID, "performed_by", event.PerformedBy)
 
 	// Example implementations:
 	// 1. Store audit log
@@ -70,12 +77,12 @@ func (h *TeamActivityEventHandler) handleTeamCreated(ctx context.Context, event
 	return nil
 }
 
-func (h *TeamActivityEventHandler) handleMemberAdded(ctx context.Context, event kafka.TeamActivityEvent) error {
+func (h *TeamActivityEventHandler) handleMemberAdded(ctx context.Context, event TeamActivityEvent) error {
 	if event.TargetUserID == nil {
 		return fmt.Errorf("targetUserId is required for MEMBER_ADDED event")
 	}
 
-	log.Info.Printf("Member %s added to team %s by user %s", *event.TargetUserID, event.TeamID, event.PerformedBy)
+	log.Info(ctx, "member added", "user_id", *event.TargetUserID, "team_id", event.TeamID, "performed_by", event.PerformedBy)
 
 	// Example implementations:
 	// 1. Update team member cache
@@ -96,12 +103,12 @@ func (h *TeamActivityEventHandler) handleMemberAdded(ctx context.Context, event
 	return nil
 }
 
-func (h *TeamActivityEventHandler) handleMemberRemoved(ctx context.Context, event kafka.TeamActivityEvent) error {
+func (h *TeamActivityEventHandler) handleMemberRemoved(ctx context.Context, event TeamActivityEvent) error {
 	if event.TargetUserID == nil {
 		return fmt.Errorf("targetUserId is required for MEMBER_REMOVED event")
 	}
 
-	log.Info.Printf("Member %s removed from team %s by user %s", *event.TargetUserID, event.TeamID, event.PerformedBy)
+	log.Info(ctx, "member removed", "user_id", *event.TargetUserID, "team_id", event.TeamID, "performed_by", event.PerformedBy)
 
 	// Example implementations:
 	// 1. Update team member cache
@@ -122,12 +129,12 @@ func (h *TeamActivityEventHandler) handleMemberRemoved(ctx context.Context, even
 	return nil
 }
 
-func (h *TeamActivityEventHandler) handleManagerAdded(ctx context.Context, event kafka.TeamActivityEvent) error {
+func (h *TeamActivityEventHandler) handleManagerAdded(ctx context.Context, event TeamActivityEvent) error {
 	if event.TargetUserID == nil {
 		return fmt.Errorf("targetUserId is required for MANAGER_ADDED event")
 	}
 
-	log.Info.Printf("Manager %s added to team %s by user %s", *event.TargetUserID, event.TeamID, event.PerformedBy)
+	log.Info(ctx, "manager added", "user_id", *event.TargetUserID, "team_id", event.TeamID, "performed_by", event.PerformedBy)
 
 	// Example implementations:
 	// 1. Update team manager cache
@@ -148,12 +155,12 @@ func (h *TeamActivityEventHandler) handleManagerAdded(ctx context.Context, event
 	return nil
 }
 
-func (h *TeamActivityEventHandler) handleManagerRemoved(ctx context.Context, event kafka.TeamActivityEvent) error {
+func (h *TeamActivityEventHandler) handleManagerRemoved(ctx context.Context, event TeamActivityEvent) error {
 	if event.TargetUserID == nil {
 		return fmt.Errorf("targetUserId is required for MANAGER_REMOVED event")
 	}
 
-	log.Info.Printf("Manager %s removed from team %s by user %s", *event.TargetUserID, event.TeamID, event.PerformedBy)
+	log.Info(ctx, "manager removed", "user_id", *event.TargetUserID, "team_id", event.TeamID, "performed_by", event.PerformedBy)
 
 	// Example implementations:
 	// 1. Update team manager cache
@@ -173,3 +180,58 @@ func (h *TeamActivityEventHandler) handleManagerRemoved(ctx context.Context, eve
 
 	return nil
 }
+
+func (h *TeamActivityEventHandler) handleOwnershipTransferred(ctx context.Context, event TeamActivityEvent) error {
+	if event.TargetUserID == nil {
+		return fmt.Errorf("targetUserId is required for OWNERSHIP_TRANSFERRED event")
+	}
+
+	log.Info(ctx, "team ownership transferred", "user_id", *event.TargetUserID, "team_id", event.TeamID, "performed_by", event.PerformedBy)
+
+	// Example implementations:
+	// 1. Store audit log
+	// 2. Notify the new owner
+	// h.notificationService.NotifyOwnershipTransferred(ctx, event.TeamID, *event.TargetUserID)
+
+	return nil
+}
+
+func (h *TeamActivityEventHandler) handleMemberRoleChanged(ctx context.Context, event TeamActivityEvent) error {
+	if event.TargetUserID == nil || event.Role == nil {
+		return fmt.Errorf("targetUserId and role are required for MEMBER_ROLE_CHANGED event")
+	}
+
+	log.Info(ctx, "member role changed", "user_id", *event.TargetUserID, "role", *event.Role, "team_id", event.TeamID, "performed_by", event.PerformedBy)
+
+	// Example implementations:
+	// 1. Grant or revoke manager permissions
+	// h.accessControlService.SetTeamRole(ctx, event.TeamID, *event.TargetUserID, *event.Role)
+
+	return nil
+}
+
+func (h *TeamActivityEventHandler) handleTeamMoved(ctx context.Context, event TeamActivityEvent) error {
+	parentTeamID := ""
+	if event.ParentTeamID != nil {
+		parentTeamID = *event.ParentTeamID
+	}
+	log.Info(ctx, "team moved", "team_id", event.TeamID, "parent_team_id", parentTeamID, "performed_by", event.PerformedBy)
+
+	// Example implementations:
+	// 1. Drop cached manager rights of the team and everything beneath it,
+	//    which the move changes
+	// h.accessControlService.InvalidateSubtree(ctx, event.TeamID)
+
+	return nil
+}
+
+func (h *TeamActivityEventHandler) handleTeamChanged(ctx context.Context, event TeamActivityEvent) error {
+	log.Info(ctx, "team changed", "event_type", event.EventType, "team_id", event.TeamID, "performed_by", event.PerformedBy)
+
+	// Example implementations:
+	// 1. Store audit log
+	// 2. Update or drop the cached team
+	// h.cacheService.InvalidateTeamCache(ctx, event.TeamID)
+
+	return nil
+}
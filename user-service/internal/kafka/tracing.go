@@ -0,0 +1,48 @@
+package kafka
+
+import (
+	"shared/pkg/tracing"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "user-service/internal/kafka"
+
+func tracer() trace.Tracer {
+	return tracing.Tracer(instrumentationName)
+}
+
+// headerCarrier adapts kafka message headers to the propagation
+// TextMapCarrier, so trace context travels in the message like it does in
+// HTTP headers.
+type headerCarrier struct {
+	headers *[]kafka.Header
+}
+
+func (c headerCarrier) Get(key string) string {
+	for _, h := range *c.headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c headerCarrier) Set(key, value string) {
+	for i, h := range *c.headers {
+		if h.Key == key {
+			(*c.headers)[i].Value = []byte(value)
+			return
+		}
+	}
+	*c.headers = append(*c.headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(*c.headers))
+	for _, h := range *c.headers {
+		keys = append(keys, h.Key)
+	}
+	return keys
+}
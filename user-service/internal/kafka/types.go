@@ -7,20 +7,33 @@ type Value []byte
 
 // Team Activity Event Types
 const (
-	EventTypeTeamCreated    = "TEAM_CREATED"
-	EventTypeMemberAdded    = "MEMBER_ADDED"
-	EventTypeMemberRemoved  = "MEMBER_REMOVED"
-	EventTypeManagerAdded   = "MANAGER_ADDED"
-	EventTypeManagerRemoved = "MANAGER_REMOVED"
+	EventTypeTeamCreated          = "TEAM_CREATED"
+	EventTypeMemberAdded          = "MEMBER_ADDED"
+	EventTypeMemberRemoved        = "MEMBER_REMOVED"
+	EventTypeManagerAdded         = "MANAGER_ADDED"
+	EventTypeManagerRemoved       = "MANAGER_REMOVED"
+	EventTypeOwnershipTransferred = "OWNERSHIP_TRANSFERRED"
+	EventTypeTeamUpdated          = "TEAM_UPDATED"
+	EventTypeTeamArchived         = "TEAM_ARCHIVED"
+	EventTypeTeamUnarchived       = "TEAM_UNARCHIVED"
+	EventTypeTeamDeleted          = "TEAM_DELETED"
+	EventTypeMemberRoleChanged    = "MEMBER_ROLE_CHANGED"
+	EventTypeTeamMoved            = "TEAM_MOVED"
 )
 
+// PerformedByOperator is the PerformedBy of events from cmd/admin, which acts
+// as no user.
+const PerformedByOperator = "operator"
+
 // Team Activity Event as specified in kafka_redis.md
 type TeamActivityEvent struct {
 	EventType    string    `json:"eventType"`
 	TeamID       string    `json:"teamId"`
 	PerformedBy  string    `json:"performedBy"`
-	TargetUserID *string   `json:"targetUserId,omitempty"` // nil for TEAM_CREATED
-	TeamName     *string   `json:"teamName,omitempty"`     // only for TEAM_CREATED
+	TargetUserID *string   `json:"targetUserId,omitempty"` // nil for events about the team itself; the new owner for OWNERSHIP_TRANSFERRED
+	TeamName     *string   `json:"teamName,omitempty"`     // only for TEAM_CREATED and TEAM_UPDATED
+	Role         *string   `json:"role,omitempty"`         // the new role, only for MEMBER_ROLE_CHANGED
+	ParentTeamID *string   `json:"parentTeamId,omitempty"` // the parent of a nested team for TEAM_CREATED and TEAM_MOVED
 	Timestamp    time.Time `json:"timestamp"`
 }
 
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InvitationStatus string
+
+const (
+	InvitationPending  InvitationStatus = "pending"
+	InvitationAccepted InvitationStatus = "accepted"
+	InvitationDeclined InvitationStatus = "declined"
+	InvitationRevoked  InvitationStatus = "revoked"
+	// InvitationExpired is reported for pending invitations past ExpiresAt;
+	// it is only stored once a new invitation replaces the stale one.
+	InvitationExpired InvitationStatus = "expired"
+)
+
+// TeamInvitation asks a user to join a team as a member. Invitations to an
+// email nobody has registered with yet wait for that user; InviteeID stays
+// nil until then.
+type TeamInvitation struct {
+	ID          uuid.UUID        `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	OrgID       uuid.UUID        `gorm:"type:uuid;not null;index" json:"-"`
+	TeamID      uuid.UUID        `gorm:"type:uuid;not null;index" json:"teamId"`
+	InviterID   uuid.UUID        `gorm:"type:uuid;not null" json:"inviterId"`
+	InviteeID   *uuid.UUID       `gorm:"type:uuid;index" json:"inviteeId,omitempty"`
+	Email       string           `gorm:"not null;index" json:"email"`
+	Status      InvitationStatus `gorm:"type:varchar(10);not null" json:"status"`
+	ExpiresAt   time.Time        `gorm:"not null" json:"expiresAt"`
+	RespondedAt *time.Time       `json:"respondedAt,omitempty"`
+	CreatedAt   time.Time        `gorm:"autoCreateTime" json:"createdAt"`
+
+	Team Team `gorm:"foreignKey:TeamID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (TeamInvitation) TableName() string {
+	return "team_invitations"
+}
+
+// Expired reports whether a pending invitation can no longer be answered.
+func (i *TeamInvitation) Expired(now time.Time) bool {
+	return i.Status == InvitationPending && !now.Before(i.ExpiresAt)
+}
+
+// InviteRequest names the invitee by user ID or by email; exactly one is set.
+type InviteRequest struct {
+	UserID string `json:"userId"`
+	Email  string `json:"email"`
+}
+
+type InvitationResponse struct {
+	ID        uuid.UUID        `json:"id"`
+	TeamID    uuid.UUID        `json:"teamId"`
+	TeamName  string           `json:"teamName"`
+	InviterID uuid.UUID        `json:"inviterId"`
+	InviteeID *uuid.UUID       `json:"inviteeId,omitempty"`
+	Email     string           `json:"email"`
+	Status    InvitationStatus `json:"status"`
+	ExpiresAt time.Time        `json:"expiresAt"`
+	CreatedAt time.Time        `json:"createdAt"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Organization is a customer sharing the deployment. Users, teams and
+// everything in them belong to one organisation and never see another's.
+type Organization struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Name      string    `gorm:"not null" json:"name"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
@@ -1,22 +1,34 @@
 package models
 
 import (
+	"shared/pkg/listing"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Team is owned by one of its managers. Only the owner adds and removes the
+// other managers, and the owner can't leave the team without handing
+// ownership to another manager first, so a team always has a manager.
+// Archived teams are read-only and left out of listings unless asked for.
+// Teams nest under a parent team; managers of a team also manage every team
+// beneath it.
 type Team struct {
-	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
-	TeamName  string    `gorm:"not null" json:"teamName"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+	ID         uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	OrgID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"orgId"`
+	TeamName   string     `gorm:"not null" json:"teamName"`
+	OwnerID    uuid.UUID  `gorm:"type:uuid;index" json:"ownerId"`
+	ParentID   *uuid.UUID `gorm:"type:uuid;index" json:"parentId,omitempty"`
+	ArchivedAt *time.Time `json:"archivedAt,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
 
 	TeamMembers []TeamMember `gorm:"foreignKey:TeamID;constraint:OnDelete:CASCADE" json:"teamMembers,omitempty"`
 }
 
 type TeamMember struct {
 	ID       uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	OrgID    uuid.UUID `gorm:"type:uuid;not null;index" json:"-"`
 	TeamID   uuid.UUID `gorm:"type:uuid;not null;index;uniqueIndex:idx_team_user" json:"teamId"`
 	UserID   uuid.UUID `gorm:"type:uuid;not null;index;uniqueIndex:idx_team_user" json:"userId"`
 	Role     string    `gorm:"type:VARCHAR(10);not null" json:"role"`
@@ -31,6 +43,7 @@ func (TeamMember) TableName() string {
 
 type CreateTeamRequest struct {
 	TeamName string              `json:"teamName" binding:"required"`
+	ParentID string              `json:"parentId"`
 	Managers []TeamMemberRequest `json:"managers"`
 	Members  []TeamMemberRequest `json:"members"`
 }
@@ -44,13 +57,63 @@ type AddMemberRequest struct {
 	UserID string `json:"userId" binding:"required"`
 }
 
+type UpdateTeamRequest struct {
+	TeamName string `json:"teamName" binding:"required"`
+}
+
+// TeamListQuery narrows and pages GetAllTeams.
+type TeamListQuery struct {
+	IncludeArchived bool
+	// Search matches anywhere in the team name, ignoring case.
+	Search string
+	// Role keeps the teams where the requestor is a "manager" or "member".
+	Role  string
+	Limit int
+	// After resumes the listing past the team it marks, by name then id.
+	After *listing.Cursor
+}
+
+// TeamVisibility is the set of teams a user may list: every team of the
+// organisation, or those the user belongs to and the sub-teams of those
+// they manage.
+type TeamVisibility struct {
+	UserID uuid.UUID
+	All    bool
+}
+
+// SetParentRequest moves a team under another one, or to the top level when
+// ParentID is empty.
+type SetParentRequest struct {
+	ParentID string `json:"parentId"`
+}
+
+type TransferOwnershipRequest struct {
+	UserID string `json:"userId" binding:"required"`
+}
+
 type TeamResponse struct {
-	ID        uuid.UUID            `json:"id"`
-	TeamName  string               `json:"teamName"`
-	Managers  []TeamMemberResponse `json:"managers"`
-	Members   []TeamMemberResponse `json:"members"`
-	CreatedAt time.Time            `json:"createdAt"`
-	UpdatedAt time.Time            `json:"updatedAt"`
+	ID         uuid.UUID            `json:"id"`
+	TeamName   string               `json:"teamName"`
+	OwnerID    uuid.UUID            `json:"ownerId"`
+	ParentID   *uuid.UUID           `json:"parentId,omitempty"`
+	ArchivedAt *time.Time           `json:"archivedAt,omitempty"`
+	Managers   []TeamMemberResponse `json:"managers"`
+	Members    []TeamMemberResponse `json:"members"`
+	CreatedAt  time.Time            `json:"createdAt"`
+	UpdatedAt  time.Time            `json:"updatedAt"`
+	// Skipped lists what CreateTeam could not add; it is empty otherwise.
+	Skipped []SkippedEntry `json:"skipped,omitempty"`
+}
+
+// TeamNodeResponse is a team in an ancestors or descendants listing. Depth
+// counts the levels from the team asked about: 1 for its parent or children.
+type TeamNodeResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	TeamName   string     `json:"teamName"`
+	OwnerID    uuid.UUID  `json:"ownerId"`
+	ParentID   *uuid.UUID `json:"parentId,omitempty"`
+	ArchivedAt *time.Time `json:"archivedAt,omitempty"`
+	Depth      int        `json:"depth"`
 }
 
 type TeamMemberResponse struct {
@@ -60,3 +123,63 @@ type TeamMemberResponse struct {
 	Role     string    `json:"role"`
 	JoinedAt time.Time `json:"joinedAt"`
 }
+
+// SkippedEntry is a manager or member of a CreateTeamRequest that was not
+// added, with the reason.
+type SkippedEntry struct {
+	UserID string `json:"userId"`
+	Role   string `json:"role"`
+	Reason string `json:"reason"`
+}
+
+// Membership operations of a batch request.
+const (
+	MembershipAdd     = "add"
+	MembershipRemove  = "remove"
+	MembershipSetRole = "setRole"
+)
+
+type MembershipOperation struct {
+	Op     string `json:"op" binding:"required"`
+	UserID string `json:"userId" binding:"required"`
+	// Role is "member" or "manager"; required for setRole, defaults to
+	// member for add and is ignored for remove.
+	Role string `json:"role"`
+}
+
+type BatchMembershipRequest struct {
+	Operations []MembershipOperation `json:"operations" binding:"required,min=1,dive"`
+}
+
+// Outcomes of a batch operation.
+const (
+	OutcomeApplied    = "applied"
+	OutcomeFailed     = "failed"
+	OutcomeNotApplied = "not_applied"
+)
+
+type MembershipResult struct {
+	Op     string `json:"op"`
+	UserID string `json:"userId"`
+	Role   string `json:"role,omitempty"`
+	// Outcome is not_applied when the operation was valid but another one in
+	// the batch failed.
+	Outcome string `json:"outcome"`
+	Code    string `json:"code,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// BatchMembershipResponse reports every operation of a batch, in request
+// order. The batch is applied as a whole or not at all.
+type BatchMembershipResponse struct {
+	Applied bool               `json:"applied"`
+	Results []MembershipResult `json:"results"`
+}
+
+// MembershipChange is a validated batch operation for the repository. Role is
+// the role the user ends up with, or the role they had for removals.
+type MembershipChange struct {
+	Op     string
+	UserID uuid.UUID
+	Role   string
+}
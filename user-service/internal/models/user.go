@@ -8,10 +8,14 @@ import (
 
 type User struct {
 	ID           uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	OrgID        uuid.UUID `gorm:"type:uuid;not null;index" json:"orgId"`
 	Username     string    `gorm:"not null" json:"username"`
 	Email        string    `gorm:"unique;not null" json:"email"`
 	Role         string    `gorm:"type:VARCHAR(10);not null" json:"role"`
 	PasswordHash string    `gorm:"not null" json:"-"`
 	CreatedAt    time.Time `gorm:"autoCreateTime" json:"createdAt"`
 	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+	// DisabledAt is set while an operator has disabled the account; disabled
+	// users can't log in.
+	DisabledAt *time.Time `json:"disabledAt,omitempty"`
 }
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *models.TeamInvitation) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.TeamInvitation, error)
+	FindPending(ctx context.Context, teamID uuid.UUID, email string) (*models.TeamInvitation, error)
+	ListPendingForTeam(ctx context.Context, teamID uuid.UUID) ([]*models.TeamInvitation, error)
+	ListPendingForUser(ctx context.Context, userID uuid.UUID, email string) ([]*models.TeamInvitation, error)
+	Accept(ctx context.Context, id uuid.UUID, member *models.TeamMember) error
+	Close(ctx context.Context, id uuid.UUID, status models.InvitationStatus, userID *uuid.UUID) error
+}
+
+// ErrInvitationClosed is returned when an invitation was answered, revoked or
+// expired before the change could be applied.
+var ErrInvitationClosed = errors.New("invitation is no longer pending")
+
+type GormInvitationRepository struct {
+	DB *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) InvitationRepository {
+	return &GormInvitationRepository{DB: db}
+}
+
+// Create stores the invitation, first marking a stale pending invitation for
+// the same team and address as expired so the new one can take its place.
+func (r *GormInvitationRepository) Create(ctx context.Context, invitation *models.TeamInvitation) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.TeamInvitation{}).
+			Where("team_id = ? AND email = ? AND status = ? AND expires_at <= ?",
+				invitation.TeamID, invitation.Email, models.InvitationPending, time.Now()).
+			Update("status", models.InvitationExpired).Error; err != nil {
+			return err
+		}
+		return tx.Create(invitation).Error
+	})
+}
+
+func (r *GormInvitationRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.TeamInvitation, error) {
+	var invitation models.TeamInvitation
+	if err := r.DB.WithContext(ctx).Preload("Team").First(&invitation, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// FindPending returns the open invitation to the team for email.
+func (r *GormInvitationRepository) FindPending(ctx context.Context, teamID uuid.UUID, email string) (*models.TeamInvitation, error) {
+	var invitation models.TeamInvitation
+	if err := r.DB.WithContext(ctx).Scopes(pending).
+		Where("team_id = ? AND email = ?", teamID, email).
+		First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *GormInvitationRepository) ListPendingForTeam(ctx context.Context, teamID uuid.UUID) ([]*models.TeamInvitation, error) {
+	var invitations []*models.TeamInvitation
+	if err := r.DB.WithContext(ctx).Preload("Team").Scopes(pending).
+		Where("team_id = ?", teamID).
+		Order("created_at").
+		Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// ListPendingForUser returns the open invitations addressed to the user,
+// including those sent to their email before they registered.
+func (r *GormInvitationRepository) ListPendingForUser(ctx context.Context, userID uuid.UUID, email string) ([]*models.TeamInvitation, error) {
+	var invitations []*models.TeamInvitation
+	if err := r.DB.WithContext(ctx).Preload("Team").Scopes(pending).
+		Where("invitee_id = ? OR (invitee_id IS NULL AND email = ?)", userID, email).
+		Order("created_at").
+		Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// Accept marks the invitation accepted by member.UserID and adds the member in
+// the same transaction.
+func (r *GormInvitationRepository) Accept(ctx context.Context, id uuid.UUID, member *models.TeamMember) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := closeInvitation(tx, id, models.InvitationAccepted, &member.UserID); err != nil {
+			return err
+		}
+		return tx.Create(member).Error
+	})
+}
+
+// Close answers or revokes the pending invitation. userID, when set, records
+// who answered it.
+func (r *GormInvitationRepository) Close(ctx context.Context, id uuid.UUID, status models.InvitationStatus, userID *uuid.UUID) error {
+	return closeInvitation(r.DB.WithContext(ctx), id, status, userID)
+}
+
+func closeInvitation(db *gorm.DB, id uuid.UUID, status models.InvitationStatus, userID *uuid.UUID) error {
+	changes := map[string]any{"status": status, "responded_at": time.Now()}
+	if userID != nil {
+		changes["invitee_id"] = *userID
+	}
+
+	res := db.Model(&models.TeamInvitation{}).Scopes(pending).Where("id = ?", id).Updates(changes)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrInvitationClosed
+	}
+	return nil
+}
+
+// pending keeps invitations that can still be answered.
+func pending(db *gorm.DB) *gorm.DB {
+	return db.Where("team_invitations.status = ? AND team_invitations.expires_at > ?", models.InvitationPending, time.Now())
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"user-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OrganizationRepository interface {
+	Create(ctx context.Context, org *models.Organization) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Organization, error)
+	FindAll(ctx context.Context) ([]*models.Organization, error)
+}
+
+type GormOrganizationRepository struct {
+	DB *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) OrganizationRepository {
+	return &GormOrganizationRepository{DB: db}
+}
+
+func (r *GormOrganizationRepository) Create(ctx context.Context, org *models.Organization) error {
+	return r.DB.WithContext(ctx).Create(org).Error
+}
+
+func (r *GormOrganizationRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Organization, error) {
+	var org models.Organization
+	if err := r.DB.WithContext(ctx).First(&org, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (r *GormOrganizationRepository) FindAll(ctx context.Context) ([]*models.Organization, error) {
+	var orgs []*models.Organization
+	if err := r.DB.WithContext(ctx).Order("name").Find(&orgs).Error; err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
@@ -3,6 +3,10 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"shared/pkg/listing"
+	"strings"
+	"time"
 	"user-service/internal/models"
 
 	"github.com/google/uuid"
@@ -12,13 +16,21 @@ import (
 type TeamRepository interface {
 	Create(ctx context.Context, team *models.Team) (*models.Team, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*models.Team, error)
-	FindAll(ctx context.Context) ([]*models.Team, error)
+	FindTeams(ctx context.Context, v models.TeamVisibility, q models.TeamListQuery) ([]*models.Team, *listing.Cursor, error)
 	FindMembersByTeamID(ctx context.Context, teamID uuid.UUID) ([]*models.TeamMember, error)
 	AddMember(ctx context.Context, teamMember *models.TeamMember) error
 	RemoveMember(ctx context.Context, teamID, userID uuid.UUID) error
-	FindUserTeams(ctx context.Context, userID uuid.UUID) ([]*models.Team, error)
 	IsUserInTeam(ctx context.Context, teamID, userID uuid.UUID) bool
 	IsUserManagerOfTeam(ctx context.Context, teamID, userID uuid.UUID) bool
+	IsUserDirectManagerOfTeam(ctx context.Context, teamID, userID uuid.UUID) bool
+	FindAncestors(ctx context.Context, teamID uuid.UUID) ([]*models.Team, error)
+	FindDescendants(ctx context.Context, teamID uuid.UUID) ([]*models.Team, error)
+	SetParent(ctx context.Context, teamID uuid.UUID, parentID *uuid.UUID) error
+	TransferOwnership(ctx context.Context, teamID, fromID, toID uuid.UUID) error
+	AssignOwner(ctx context.Context, teamID, toID uuid.UUID) error
+	Update(ctx context.Context, id uuid.UUID, changes map[string]any) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ApplyMembershipChanges(ctx context.Context, teamID uuid.UUID, changes []models.MembershipChange) error
 }
 
 type GormTeamRepository struct {
@@ -44,12 +56,44 @@ func (r *GormTeamRepository) FindByID(ctx context.Context, id uuid.UUID) (*model
 	return &team, nil
 }
 
-func (r *GormTeamRepository) FindAll(ctx context.Context) ([]*models.Team, error) {
+// FindTeams returns the page of the teams visible to the user that match the
+// query, ordered by name then id, with their members and users loaded, and the
+// cursor of the next page, or nil on the last one.
+func (r *GormTeamRepository) FindTeams(ctx context.Context, v models.TeamVisibility, q models.TeamListQuery) ([]*models.Team, *listing.Cursor, error) {
+	db := r.DB.WithContext(ctx)
+	memberOf := func(role string) *gorm.DB {
+		teams := db.Model(&models.TeamMember{}).Select("team_id").Where("user_id = ?", v.UserID)
+		if role != "" {
+			teams = teams.Where("role = ?", role)
+		}
+		return teams
+	}
+
+	query := db.Preload("TeamMembers.User").Scopes(archived(q))
+	if !v.All {
+		query = query.Where("(teams.id IN (?) OR teams.id IN (?))", memberOf(""), managedTeams(db, v.UserID))
+	}
+	if q.Role != "" {
+		query = query.Where("teams.id IN (?)", memberOf(q.Role))
+	}
+	if q.Search != "" {
+		query = query.Where("teams.team_name ILIKE ?", "%"+likeEscaper.Replace(q.Search)+"%")
+	}
+	if q.After != nil {
+		query = query.Where("(teams.team_name, teams.id) > (?, ?)", q.After.Value, q.After.ID)
+	}
+
+	// One more than asked for tells whether another page follows
 	var teams []*models.Team
-	if err := r.DB.WithContext(ctx).Find(&teams).Error; err != nil {
-		return nil, err
+	if err := query.Order("teams.team_name, teams.id").Limit(q.Limit + 1).Find(&teams).Error; err != nil {
+		return nil, nil, err
+	}
+	if len(teams) <= q.Limit {
+		return teams, nil, nil
 	}
-	return teams, nil
+	teams = teams[:q.Limit]
+	last := teams[len(teams)-1]
+	return teams, &listing.Cursor{Value: last.TeamName, ID: last.ID}, nil
 }
 
 func (r *GormTeamRepository) FindMembersByTeamID(ctx context.Context, teamID uuid.UUID) ([]*models.TeamMember, error) {
@@ -77,20 +121,21 @@ func (r *GormTeamRepository) AddMember(ctx context.Context, teamMember *models.T
 	return r.DB.WithContext(ctx).Create(teamMember).Error
 }
 
+// RemoveMember removes the user from the team unless they own it, in which
+// case nothing is removed and gorm.ErrRecordNotFound is returned like for a
+// user outside the team.
 func (r *GormTeamRepository) RemoveMember(ctx context.Context, teamID, userID uuid.UUID) error {
-	return r.DB.WithContext(ctx).Where("team_id = ? AND user_id = ?", teamID, userID).Delete(&models.TeamMember{}).Error
-}
-
-func (r *GormTeamRepository) FindUserTeams(ctx context.Context, userID uuid.UUID) ([]*models.Team, error) {
-	var teams []*models.Team
-	if err := r.DB.WithContext(ctx).
-		Table("teams").
-		Joins("JOIN team_members ON teams.id = team_members.team_id").
-		Where("team_members.user_id = ?", userID).
-		Find(&teams).Error; err != nil {
-		return nil, err
+	db := r.DB.WithContext(ctx)
+	res := db.Where("team_id = ? AND user_id = ?", teamID, userID).
+		Where("NOT EXISTS (?)", db.Model(&models.Team{}).Select("1").Where("id = ? AND owner_id = ?", teamID, userID)).
+		Delete(&models.TeamMember{})
+	if res.Error != nil {
+		return res.Error
 	}
-	return teams, nil
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
 func (r *GormTeamRepository) IsUserInTeam(ctx context.Context, teamID, userID uuid.UUID) bool {
@@ -101,10 +146,125 @@ func (r *GormTeamRepository) IsUserInTeam(ctx context.Context, teamID, userID uu
 	return count > 0
 }
 
-func (r *GormTeamRepository) IsUserManagerOfTeam(ctx context.Context, teamID, userID uuid.UUID) bool {
-	var count int64
-	r.DB.WithContext(ctx).Model(&models.TeamMember{}).
-		Where("team_id = ? AND user_id = ? AND role = ?", teamID, userID, "manager").
-		Count(&count)
-	return count > 0
+// ErrOwnershipChanged is returned by TransferOwnership and AssignOwner when
+// fromID no longer owns the team or toID is not one of its managers.
+var ErrOwnershipChanged = errors.New("team ownership changed")
+
+// TransferOwnership makes toID the owner of the team in place of fromID. Both
+// conditions are checked in the update itself, so a concurrent transfer or
+// manager removal can't leave the team owned by a non-manager. uuid.Nil never
+// owns a team, so it is refused even for teams without an owner; operators
+// use AssignOwner for those.
+func (r *GormTeamRepository) TransferOwnership(ctx context.Context, teamID, fromID, toID uuid.UUID) error {
+	if fromID == uuid.Nil {
+		return ErrOwnershipChanged
+	}
+	return r.setOwner(ctx, teamID, toID, &fromID)
+}
+
+// AssignOwner makes toID the owner of the team whoever owns it now, including
+// teams the owner backfill left without one. It is the operator path and must
+// not be reachable from the API. toID must still be one of the team's managers.
+func (r *GormTeamRepository) AssignOwner(ctx context.Context, teamID, toID uuid.UUID) error {
+	return r.setOwner(ctx, teamID, toID, nil)
+}
+
+// setOwner makes toID the owner, provided fromID owns the team when given.
+func (r *GormTeamRepository) setOwner(ctx context.Context, teamID, toID uuid.UUID, fromID *uuid.UUID) error {
+	db := r.DB.WithContext(ctx)
+	query := db.Model(&models.Team{}).Where("id = ?", teamID)
+	if fromID != nil {
+		query = query.Where("owner_id = ?", *fromID)
+	}
+	res := query.
+		Where("EXISTS (?)", db.Model(&models.TeamMember{}).Select("1").
+			Where("team_id = ? AND user_id = ? AND role = ?", teamID, toID, "manager")).
+		Update("owner_id", toID)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrOwnershipChanged
+	}
+	return nil
+}
+
+func (r *GormTeamRepository) Update(ctx context.Context, id uuid.UUID, changes map[string]any) error {
+	res := r.DB.WithContext(ctx).Model(&models.Team{}).Where("id = ?", id).Updates(changes)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete removes the team; its memberships go with it through the foreign
+// key's ON DELETE CASCADE.
+func (r *GormTeamRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res := r.DB.WithContext(ctx).Delete(&models.Team{}, "id = ?", id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ErrMembershipChanged is returned by ApplyMembershipChanges when a change no
+// longer matches the team, e.g. because a member left meanwhile. Nothing is
+// applied then.
+var ErrMembershipChanged = errors.New("team membership changed")
+
+// ApplyMembershipChanges applies all changes in one transaction. Removals and
+// role changes only touch non-owners holding the expected role.
+func (r *GormTeamRepository) ApplyMembershipChanges(ctx context.Context, teamID uuid.UUID, changes []models.MembershipChange) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		owner := tx.Model(&models.Team{}).Select("1").Where("id = ? AND owner_id = team_members.user_id", teamID)
+
+		for _, change := range changes {
+			var res *gorm.DB
+			switch change.Op {
+			case models.MembershipAdd:
+				res = tx.Create(&models.TeamMember{
+					ID:       uuid.New(),
+					TeamID:   teamID,
+					UserID:   change.UserID,
+					Role:     change.Role,
+					JoinedAt: time.Now(),
+				})
+			case models.MembershipRemove:
+				res = tx.Where("team_id = ? AND user_id = ? AND role = ? AND NOT EXISTS (?)", teamID, change.UserID, change.Role, owner).
+					Delete(&models.TeamMember{})
+			case models.MembershipSetRole:
+				res = tx.Model(&models.TeamMember{}).
+					Where("team_id = ? AND user_id = ? AND role <> ? AND NOT EXISTS (?)", teamID, change.UserID, change.Role, owner).
+					Update("role", change.Role)
+			default:
+				return fmt.Errorf("unknown membership operation %q", change.Op)
+			}
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected != 1 {
+				return ErrMembershipChanged
+			}
+		}
+		return nil
+	})
+}
+
+// likeEscaper makes user input match literally in a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// archived leaves archived teams out unless the query includes them.
+func archived(q models.TeamListQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if q.IncludeArchived {
+			return db
+		}
+		return db.Where("teams.archived_at IS NULL")
+	}
 }
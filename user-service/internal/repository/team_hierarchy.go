@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"shared/pkg/tenant"
+	"user-service/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxTeamDepth is how many levels teams nest at most. It also bounds the
+// recursive queries walking the hierarchy.
+const MaxTeamDepth = 32
+
+var (
+	// ErrHierarchyCycle is returned by SetParent when the new parent is the
+	// team itself or one of its sub-teams.
+	ErrHierarchyCycle = errors.New("team hierarchy cycle")
+	// ErrHierarchyTooDeep is returned by SetParent when the move would nest
+	// teams deeper than MaxTeamDepth.
+	ErrHierarchyTooDeep = errors.New("team hierarchy too deep")
+)
+
+// lineageQuery selects the team and its ancestors with their distance from
+// it. Raw SQL escapes the tenant plugin, so the queries filter by @org
+// themselves; parents are always in the team's organisation.
+const lineageQuery = `WITH RECURSIVE lineage AS (
+	SELECT id, parent_id, 0 AS depth FROM teams
+	WHERE id = @team AND (CAST(@org AS uuid) IS NULL OR org_id = @org)
+	UNION ALL
+	SELECT t.id, t.parent_id, l.depth + 1 FROM teams t JOIN lineage l ON t.id = l.parent_id
+	WHERE l.depth < @max
+)`
+
+// subtreeQuery selects the teams beneath a team with their distance from it.
+const subtreeQuery = `WITH RECURSIVE subtree AS (
+	SELECT id, 1 AS depth FROM teams
+	WHERE parent_id = @team AND (CAST(@org AS uuid) IS NULL OR org_id = @org)
+	UNION ALL
+	SELECT t.id, s.depth + 1 FROM teams t JOIN subtree s ON t.parent_id = s.id
+	WHERE s.depth < @max
+)`
+
+// managedQuery selects the teams the user manages, directly or through one of
+// the teams above them.
+const managedQuery = `WITH RECURSIVE managed AS (
+	SELECT team_id AS id, 0 AS depth FROM team_members
+	WHERE user_id = @user AND role = 'manager' AND (CAST(@org AS uuid) IS NULL OR org_id = @org)
+	UNION ALL
+	SELECT t.id, m.depth + 1 FROM teams t JOIN managed m ON t.parent_id = m.id
+	WHERE m.depth < @max
+)
+SELECT id FROM managed`
+
+// IsUserManagerOfTeam reports whether the user manages the team, directly or
+// through one of the teams above it.
+func (r *GormTeamRepository) IsUserManagerOfTeam(ctx context.Context, teamID, userID uuid.UUID) bool {
+	db := r.DB.WithContext(ctx)
+	args := hierarchyArgs(db, map[string]any{"team": teamID, "user": userID})
+
+	var managed bool
+	db.Raw(lineageQuery+`
+		SELECT EXISTS (
+			SELECT 1 FROM team_members m JOIN lineage l ON m.team_id = l.id
+			WHERE m.user_id = @user AND m.role = 'manager'
+		)`, args).
+		Scan(&managed)
+	return managed
+}
+
+// IsUserDirectManagerOfTeam reports whether the user is a manager in the
+// team's own membership, ignoring the teams above it.
+func (r *GormTeamRepository) IsUserDirectManagerOfTeam(ctx context.Context, teamID, userID uuid.UUID) bool {
+	var count int64
+	r.DB.WithContext(ctx).Model(&models.TeamMember{}).
+		Where("team_id = ? AND user_id = ? AND role = ?", teamID, userID, "manager").
+		Count(&count)
+	return count > 0
+}
+
+// FindAncestors returns the teams above the team, its parent first.
+func (r *GormTeamRepository) FindAncestors(ctx context.Context, teamID uuid.UUID) ([]*models.Team, error) {
+	return findAncestors(r.DB.WithContext(ctx), teamID)
+}
+
+// FindDescendants returns the teams beneath the team, level by level.
+func (r *GormTeamRepository) FindDescendants(ctx context.Context, teamID uuid.UUID) ([]*models.Team, error) {
+	return findDescendants(r.DB.WithContext(ctx), teamID)
+}
+
+// SetParent moves the team under parentID, or to the top level when it is
+// nil. Concurrent moves are serialised so that together they can't create a
+// cycle.
+func (r *GormTeamRepository) SetParent(ctx context.Context, teamID uuid.UUID, parentID *uuid.UUID) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('team_hierarchy'))").Error; err != nil {
+			return err
+		}
+
+		if parentID != nil {
+			if *parentID == teamID {
+				return ErrHierarchyCycle
+			}
+			descendants, err := findDescendants(tx, teamID)
+			if err != nil {
+				return err
+			}
+			for _, d := range descendants {
+				if d.ID == *parentID {
+					return ErrHierarchyCycle
+				}
+			}
+
+			ancestors, err := findAncestors(tx, *parentID)
+			if err != nil {
+				return err
+			}
+			// Levels above the team, the team itself, then its subtree
+			levels := len(ancestors) + 2 + subtreeHeight(teamID, descendants)
+			if levels > MaxTeamDepth {
+				return ErrHierarchyTooDeep
+			}
+		}
+
+		res := tx.Model(&models.Team{}).Where("id = ?", teamID).Update("parent_id", parentID)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// managedTeams selects the IDs of the teams the user manages, for use as a
+// subquery.
+func managedTeams(db *gorm.DB, userID uuid.UUID) *gorm.DB {
+	return db.Raw(managedQuery, hierarchyArgs(db, map[string]any{"user": userID}))
+}
+
+func findAncestors(db *gorm.DB, teamID uuid.UUID) ([]*models.Team, error) {
+	var teams []*models.Team
+	err := db.Raw(lineageQuery+`
+		SELECT teams.* FROM teams JOIN lineage ON teams.id = lineage.id
+		WHERE lineage.depth > 0
+		ORDER BY lineage.depth`,
+		hierarchyArgs(db, map[string]any{"team": teamID})).
+		Scan(&teams).Error
+	return teams, err
+}
+
+func findDescendants(db *gorm.DB, teamID uuid.UUID) ([]*models.Team, error) {
+	var teams []*models.Team
+	err := db.Raw(subtreeQuery+`
+		SELECT teams.* FROM teams JOIN subtree ON teams.id = subtree.id
+		ORDER BY subtree.depth, teams.team_name`,
+		hierarchyArgs(db, map[string]any{"team": teamID})).
+		Scan(&teams).Error
+	return teams, err
+}
+
+// hierarchyArgs completes the named arguments of the hierarchy queries with
+// the depth bound and the organisation of db's context. Like the tenant
+// plugin, it only spans organisations for contexts opted out with
+// tenant.WithoutOrg; without an organisation the queries match no team.
+func hierarchyArgs(db *gorm.DB, args map[string]any) map[string]any {
+	ctx := db.Statement.Context
+	orgID, ok := tenant.OrgID(ctx)
+	switch {
+	case ok:
+		args["org"] = orgID
+	case tenant.Unscoped(ctx):
+		args["org"] = nil
+	default:
+		args["org"] = uuid.Nil
+	}
+	args["max"] = MaxTeamDepth
+	return args
+}
+
+// subtreeHeight returns how many levels the descendants of root span, given
+// them level by level as findDescendants does.
+func subtreeHeight(root uuid.UUID, descendants []*models.Team) int {
+	depth := map[uuid.UUID]int{root: 0}
+	height := 0
+	for _, d := range descendants {
+		if d.ParentID == nil {
+			continue
+		}
+		depth[d.ID] = depth[*d.ParentID] + 1
+		height = max(height, depth[d.ID])
+	}
+	return height
+}
@@ -15,6 +15,7 @@ type UserRepository interface {
 	FindByEmail(ctx context.Context, email string) (*models.User, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*models.User, error)
 	FetchAll(ctx context.Context) ([]*models.User, error)
+	Update(ctx context.Context, id uuid.UUID, changes map[string]any) error
 	Login(ctx context.Context, email, password string) (*models.User, error)
 }
 
@@ -57,6 +58,20 @@ func (r *GormUserRepository) FetchAll(ctx context.Context) ([]*models.User, erro
 	return users, nil
 }
 
+func (r *GormUserRepository) Update(ctx context.Context, id uuid.UUID, changes map[string]any) error {
+	res := r.DB.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Updates(changes)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ErrInvalidCredentials is returned by Login when the password does not match.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
 func (r *GormUserRepository) Login(ctx context.Context, email, password string) (*models.User, error) {
 	var user models.User
 	if err := r.DB.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
@@ -64,7 +79,7 @@ func (r *GormUserRepository) Login(ctx context.Context, email, password string)
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
-		return nil, errors.New("invalid credentials")
+		return nil, ErrInvalidCredentials
 	}
 
 	return &user, nil
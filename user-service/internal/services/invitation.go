@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"shared/pkg/apperror"
+	"strings"
+	"time"
+	"user-service/internal/models"
+	"user-service/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InvitationService lets team managers invite users as members, who join
+// only once they accept.
+type InvitationService interface {
+	Invite(ctx context.Context, teamID uuid.UUID, req models.InviteRequest, requestorID uuid.UUID) (*models.InvitationResponse, error)
+	ListTeamInvitations(ctx context.Context, teamID uuid.UUID, requestorID uuid.UUID) ([]*models.InvitationResponse, error)
+	RevokeInvitation(ctx context.Context, teamID uuid.UUID, invitationID uuid.UUID, requestorID uuid.UUID) error
+	ListMyInvitations(ctx context.Context, userID uuid.UUID) ([]*models.InvitationResponse, error)
+	AcceptInvitation(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID) (*models.InvitationResponse, error)
+	DeclineInvitation(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID) (*models.InvitationResponse, error)
+}
+
+type InvitationServiceImpl struct {
+	InvitationRepo repository.InvitationRepository
+	TeamRepo       repository.TeamRepository
+	UserRepo       repository.UserRepository
+	// TTL is how long an invitation stays open.
+	TTL time.Duration
+}
+
+func NewInvitationService(invitationRepo repository.InvitationRepository, teamRepo repository.TeamRepository, userRepo repository.UserRepository, ttl time.Duration) InvitationService {
+	return &InvitationServiceImpl{
+		InvitationRepo: invitationRepo,
+		TeamRepo:       teamRepo,
+		UserRepo:       userRepo,
+		TTL:            ttl,
+	}
+}
+
+func (s *InvitationServiceImpl) Invite(ctx context.Context, teamID uuid.UUID, req models.InviteRequest, requestorID uuid.UUID) (*models.InvitationResponse, error) {
+	if !s.TeamRepo.IsUserManagerOfTeam(ctx, teamID, requestorID) {
+		return nil, apperror.Forbidden("only team managers can invite members")
+	}
+
+	team, err := s.TeamRepo.FindByID(ctx, teamID)
+	if err != nil {
+		return nil, apperror.Lookup(err, "team")
+	}
+	if err := requireActive(team); err != nil {
+		return nil, err
+	}
+
+	invitation := &models.TeamInvitation{
+		ID:        uuid.New(),
+		TeamID:    teamID,
+		InviterID: requestorID,
+		Status:    models.InvitationPending,
+		ExpiresAt: time.Now().Add(s.TTL),
+		CreatedAt: time.Now(),
+	}
+
+	// Resolve the invitee; an unknown email is kept for when it registers
+	var invitee *models.User
+	switch {
+	case req.UserID != "" && req.Email != "":
+		return nil, apperror.Validation("invalid invitation", map[string]string{"userId": "give either userId or email, not both"})
+	case req.UserID != "":
+		userID, err := uuid.Parse(req.UserID)
+		if err != nil {
+			return nil, apperror.Validation("invalid invitation", map[string]string{"userId": "must be a UUID"})
+		}
+		if invitee, err = s.UserRepo.FindByID(ctx, userID); err != nil {
+			return nil, apperror.Lookup(err, "user")
+		}
+		invitation.Email = normalizeEmail(invitee.Email)
+	case req.Email != "":
+		invitation.Email = normalizeEmail(req.Email)
+		if !strings.Contains(invitation.Email, "@") {
+			return nil, apperror.Validation("invalid invitation", map[string]string{"email": "must be an email address"})
+		}
+		invitee, err = s.UserRepo.FindByEmail(ctx, invitation.Email)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.Lookup(err, "user")
+		}
+	default:
+		return nil, apperror.Validation("invalid invitation", map[string]string{"userId": "userId or email is required"})
+	}
+
+	if invitee != nil {
+		if s.TeamRepo.IsUserInTeam(ctx, teamID, invitee.ID) {
+			return nil, apperror.Conflict("user is already a member of this team")
+		}
+		invitation.InviteeID = &invitee.ID
+	}
+
+	_, err = s.InvitationRepo.FindPending(ctx, teamID, invitation.Email)
+	switch {
+	case err == nil:
+		return nil, apperror.Conflict("%s already has a pending invitation to this team", invitation.Email)
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	if err := s.InvitationRepo.Create(ctx, invitation); err != nil {
+		return nil, err
+	}
+	invitation.Team = *team
+	return invitationResponse(invitation, time.Now()), nil
+}
+
+func (s *InvitationServiceImpl) ListTeamInvitations(ctx context.Context, teamID uuid.UUID, requestorID uuid.UUID) ([]*models.InvitationResponse, error) {
+	if !s.TeamRepo.IsUserManagerOfTeam(ctx, teamID, requestorID) {
+		return nil, apperror.Forbidden("only team managers can view invitations")
+	}
+
+	invitations, err := s.InvitationRepo.ListPendingForTeam(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	return invitationResponses(invitations), nil
+}
+
+func (s *InvitationServiceImpl) RevokeInvitation(ctx context.Context, teamID uuid.UUID, invitationID uuid.UUID, requestorID uuid.UUID) error {
+	if !s.TeamRepo.IsUserManagerOfTeam(ctx, teamID, requestorID) {
+		return apperror.Forbidden("only team managers can revoke invitations")
+	}
+
+	invitation, err := s.InvitationRepo.FindByID(ctx, invitationID)
+	if err != nil || invitation.TeamID != teamID {
+		return apperror.Lookup(orNotFound(err), "invitation")
+	}
+
+	return closedError(s.InvitationRepo.Close(ctx, invitationID, models.InvitationRevoked, nil))
+}
+
+func (s *InvitationServiceImpl) ListMyInvitations(ctx context.Context, userID uuid.UUID) ([]*models.InvitationResponse, error) {
+	user, err := s.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, apperror.Lookup(err, "user")
+	}
+
+	invitations, err := s.InvitationRepo.ListPendingForUser(ctx, userID, normalizeEmail(user.Email))
+	if err != nil {
+		return nil, err
+	}
+	return invitationResponses(invitations), nil
+}
+
+func (s *InvitationServiceImpl) AcceptInvitation(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID) (*models.InvitationResponse, error) {
+	invitation, err := s.invitationFor(ctx, invitationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireActive(&invitation.Team); err != nil {
+		return nil, err
+	}
+	if s.TeamRepo.IsUserInTeam(ctx, invitation.TeamID, userID) {
+		return nil, apperror.Conflict("user is already a member of this team")
+	}
+
+	member := &models.TeamMember{
+		ID:       uuid.New(),
+		TeamID:   invitation.TeamID,
+		UserID:   userID,
+		Role:     "member",
+		JoinedAt: time.Now(),
+	}
+	if err := closedError(s.InvitationRepo.Accept(ctx, invitationID, member)); err != nil {
+		return nil, err
+	}
+
+	invitation.Status = models.InvitationAccepted
+	invitation.InviteeID = &userID
+	return invitationResponse(invitation, time.Now()), nil
+}
+
+func (s *InvitationServiceImpl) DeclineInvitation(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID) (*models.InvitationResponse, error) {
+	invitation, err := s.invitationFor(ctx, invitationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := closedError(s.InvitationRepo.Close(ctx, invitationID, models.InvitationDeclined, &userID)); err != nil {
+		return nil, err
+	}
+
+	invitation.Status = models.InvitationDeclined
+	invitation.InviteeID = &userID
+	return invitationResponse(invitation, time.Now()), nil
+}
+
+// invitationFor returns the pending invitation addressed to the user.
+// Invitations meant for someone else are reported as not found.
+func (s *InvitationServiceImpl) invitationFor(ctx context.Context, invitationID, userID uuid.UUID) (*models.TeamInvitation, error) {
+	user, err := s.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, apperror.Lookup(err, "user")
+	}
+
+	invitation, err := s.InvitationRepo.FindByID(ctx, invitationID)
+	if err != nil {
+		return nil, apperror.Lookup(err, "invitation")
+	}
+
+	addressed := invitation.InviteeID != nil && *invitation.InviteeID == userID ||
+		invitation.InviteeID == nil && invitation.Email == normalizeEmail(user.Email)
+	if !addressed {
+		return nil, apperror.NotFound("invitation not found")
+	}
+
+	if invitation.Expired(time.Now()) {
+		return nil, apperror.Conflict("invitation has expired")
+	}
+	if invitation.Status != models.InvitationPending {
+		return nil, apperror.Conflict("invitation was already %s", invitation.Status)
+	}
+	return invitation, nil
+}
+
+// closedError reports an invitation closed by a concurrent change as a
+// conflict.
+func closedError(err error) error {
+	if errors.Is(err, repository.ErrInvitationClosed) {
+		return apperror.Conflict("invitation is no longer pending")
+	}
+	return err
+}
+
+// orNotFound stands in a not-found error for a record that exists but is
+// out of scope.
+func orNotFound(err error) error {
+	if err == nil {
+		return gorm.ErrRecordNotFound
+	}
+	return err
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func invitationResponse(i *models.TeamInvitation, now time.Time) *models.InvitationResponse {
+	status := i.Status
+	if i.Expired(now) {
+		status = models.InvitationExpired
+	}
+	return &models.InvitationResponse{
+		ID:        i.ID,
+		TeamID:    i.TeamID,
+		TeamName:  i.Team.TeamName,
+		InviterID: i.InviterID,
+		InviteeID: i.InviteeID,
+		Email:     i.Email,
+		Status:    status,
+		ExpiresAt: i.ExpiresAt,
+		CreatedAt: i.CreatedAt,
+	}
+}
+
+func invitationResponses(invitations []*models.TeamInvitation) []*models.InvitationResponse {
+	now := time.Now()
+	responses := make([]*models.InvitationResponse, len(invitations))
+	for i, invitation := range invitations {
+		responses[i] = invitationResponse(invitation, now)
+	}
+	return responses
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"shared/pkg/apperror"
+	"testing"
+	"time"
+	"user-service/internal/models"
+	"user-service/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// memoryInvitationRepo keeps invitations in memory, sharing the team repo's
+// roles so accepting adds the member there.
+type memoryInvitationRepo struct {
+	teams       *memoryTeamRepo
+	invitations map[uuid.UUID]*models.TeamInvitation
+}
+
+func (r *memoryInvitationRepo) Create(_ context.Context, i *models.TeamInvitation) error {
+	r.invitations[i.ID] = i
+	return nil
+}
+
+func (r *memoryInvitationRepo) FindByID(_ context.Context, id uuid.UUID) (*models.TeamInvitation, error) {
+	i, ok := r.invitations[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *i
+	found.Team = r.teams.team
+	return &found, nil
+}
+
+func (r *memoryInvitationRepo) FindPending(_ context.Context, teamID uuid.UUID, email string) (*models.TeamInvitation, error) {
+	for _, i := range r.invitations {
+		if i.TeamID == teamID && i.Email == email && i.Status == models.InvitationPending && !i.Expired(time.Now()) {
+			return i, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryInvitationRepo) ListPendingForTeam(context.Context, uuid.UUID) ([]*models.TeamInvitation, error) {
+	return nil, nil
+}
+
+func (r *memoryInvitationRepo) ListPendingForUser(_ context.Context, userID uuid.UUID, email string) ([]*models.TeamInvitation, error) {
+	var found []*models.TeamInvitation
+	for _, i := range r.invitations {
+		addressed := i.InviteeID != nil && *i.InviteeID == userID || i.InviteeID == nil && i.Email == email
+		if addressed && i.Status == models.InvitationPending && !i.Expired(time.Now()) {
+			found = append(found, i)
+		}
+	}
+	return found, nil
+}
+
+func (r *memoryInvitationRepo) Accept(ctx context.Context, id uuid.UUID, member *models.TeamMember) error {
+	if err := r.Close(ctx, id, models.InvitationAccepted, &member.UserID); err != nil {
+		return err
+	}
+	return r.teams.AddMember(ctx, member)
+}
+
+func (r *memoryInvitationRepo) Close(_ context.Context, id uuid.UUID, status models.InvitationStatus, _ *uuid.UUID) error {
+	i := r.invitations[id]
+	if i.Status != models.InvitationPending || i.Expired(time.Now()) {
+		return repository.ErrInvitationClosed
+	}
+	i.Status = status
+	return nil
+}
+
+func (r *memoryUserRepo) FindByEmail(_ context.Context, email string) (*models.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func newInvitationService() (svc InvitationService, teams *memoryTeamRepo, invitations *memoryInvitationRepo, users *memoryUserRepo, owner, member uuid.UUID) {
+	_, teams, owner, _, member = newOwnedTeam()
+	users = &memoryUserRepo{users: map[uuid.UUID]*models.User{}}
+	for id, role := range teams.roles {
+		users.users[id] = &models.User{ID: id, Role: role, Email: id.String() + "@example.com"}
+	}
+	invitations = &memoryInvitationRepo{teams: teams, invitations: map[uuid.UUID]*models.TeamInvitation{}}
+	return NewInvitationService(invitations, teams, users, time.Hour), teams, invitations, users, owner, member
+}
+
+func TestInvitationService_HeldUntilEmailRegisters(t *testing.T) {
+	svc, teams, _, users, owner, _ := newInvitationService()
+	ctx := context.Background()
+
+	invitation, err := svc.Invite(ctx, teams.team.ID, models.InviteRequest{Email: " New@Example.com "}, owner)
+	if err != nil {
+		t.Fatalf("Expected the invitation to be created, got %v", err)
+	}
+	if invitation.InviteeID != nil || invitation.Email != "new@example.com" {
+		t.Fatalf("Expected an invitation held for new@example.com, got %+v", invitation)
+	}
+
+	_, err = svc.Invite(ctx, teams.team.ID, models.InviteRequest{Email: "new@example.com"}, owner)
+	assertKind(t, err, apperror.KindConflict)
+
+	// The user registers later with the invited address.
+	newcomer := &models.User{ID: uuid.New(), Email: "new@Example.com", Role: "member"}
+	users.users[newcomer.ID] = newcomer
+
+	pending, err := svc.ListMyInvitations(ctx, newcomer.ID)
+	if err != nil || len(pending) != 1 || pending[0].ID != invitation.ID {
+		t.Fatalf("Expected the held invitation to be listed, got %v, %v", pending, err)
+	}
+
+	accepted, err := svc.AcceptInvitation(ctx, invitation.ID, newcomer.ID)
+	if err != nil {
+		t.Fatalf("Expected the invitation to be accepted, got %v", err)
+	}
+	if accepted.Status != models.InvitationAccepted || teams.roles[newcomer.ID] != "member" {
+		t.Errorf("Expected %s to have joined as member, got %+v and role %q", newcomer.ID, accepted, teams.roles[newcomer.ID])
+	}
+
+	_, err = svc.DeclineInvitation(ctx, invitation.ID, newcomer.ID)
+	assertKind(t, err, apperror.KindConflict)
+}
+
+func TestInvitationService_RejectsInvalidAnswers(t *testing.T) {
+	svc, teams, invitations, users, owner, member := newInvitationService()
+	ctx := context.Background()
+
+	_, err := svc.Invite(ctx, teams.team.ID, models.InviteRequest{Email: "someone@example.com"}, member)
+	assertKind(t, err, apperror.KindForbidden)
+	_, err = svc.Invite(ctx, teams.team.ID, models.InviteRequest{UserID: member.String()}, owner)
+	assertKind(t, err, apperror.KindConflict)
+
+	invitee := &models.User{ID: uuid.New(), Email: "invitee@example.com", Role: "member"}
+	users.users[invitee.ID] = invitee
+	invitation, err := svc.Invite(ctx, teams.team.ID, models.InviteRequest{UserID: invitee.ID.String()}, owner)
+	if err != nil {
+		t.Fatalf("Expected the invitation to be created, got %v", err)
+	}
+
+	// Only the invitee can answer, and only before the invitation expires.
+	_, err = svc.AcceptInvitation(ctx, invitation.ID, member)
+	assertKind(t, err, apperror.KindNotFound)
+
+	invitations.invitations[invitation.ID].ExpiresAt = time.Now().Add(-time.Minute)
+	_, err = svc.AcceptInvitation(ctx, invitation.ID, invitee.ID)
+	assertKind(t, err, apperror.KindConflict)
+	if teams.roles[invitee.ID] != "" {
+		t.Error("Expected an expired invitation not to add the member")
+	}
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"time"
+	"user-service/internal/kafka"
+	"user-service/internal/models"
+
+	"shared/pkg/log"
+
+	"github.com/google/uuid"
+)
+
+// InvitationServiceWithEvents wraps an InvitationService and publishes a
+// MEMBER_ADDED event when an invitation is accepted, as TeamServiceWithEvents
+// does for members added directly.
+type InvitationServiceWithEvents struct {
+	InvitationService
+	producer  kafka.Producer
+	topicName string
+}
+
+func NewInvitationServiceWithEvents(baseService InvitationService, producer kafka.Producer, topicName string) InvitationService {
+	return &InvitationServiceWithEvents{
+		InvitationService: baseService,
+		producer:          producer,
+		topicName:         topicName,
+	}
+}
+
+func (s *InvitationServiceWithEvents) AcceptInvitation(ctx context.Context, invitationID uuid.UUID, userID uuid.UUID) (*models.InvitationResponse, error) {
+	invitation, err := s.InvitationService.AcceptInvitation(ctx, invitationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The inviter granted the membership, so they are recorded as performing it
+	event := kafka.TeamActivityEvent{
+		EventType:    kafka.EventTypeMemberAdded,
+		TeamID:       invitation.TeamID.String(),
+		PerformedBy:  invitation.InviterID.String(),
+		TargetUserID: stringPtr(userID.String()),
+		Timestamp:    time.Now(),
+	}
+
+	if err := s.producer.Publish(ctx, s.topicName, []byte(event.TeamID), event); err != nil {
+		log.Error(ctx, "failed to publish team activity event", "event_type", kafka.EventTypeMemberAdded, "error", err)
+		// Don't fail the operation if event publishing fails
+	}
+
+	return invitation, nil
+}
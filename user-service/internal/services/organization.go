@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"shared/pkg/apperror"
+	"strings"
+	"user-service/internal/models"
+	"user-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type OrganizationService interface {
+	CreateOrganization(ctx context.Context, name string) (*models.Organization, error)
+	GetOrganization(ctx context.Context, id uuid.UUID) (*models.Organization, error)
+	ListOrganizations(ctx context.Context) ([]*models.Organization, error)
+}
+
+type OrganizationServiceImpl struct {
+	Repo repository.OrganizationRepository
+}
+
+func NewOrganizationService(repo repository.OrganizationRepository) OrganizationService {
+	return &OrganizationServiceImpl{Repo: repo}
+}
+
+func (s *OrganizationServiceImpl) CreateOrganization(ctx context.Context, name string) (*models.Organization, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, apperror.Validation("invalid organisation", map[string]string{"name": "must not be empty"})
+	}
+	org := &models.Organization{ID: uuid.New(), Name: name}
+	if err := s.Repo.Create(ctx, org); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+func (s *OrganizationServiceImpl) GetOrganization(ctx context.Context, id uuid.UUID) (*models.Organization, error) {
+	org, err := s.Repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, apperror.Lookup(err, "organisation")
+	}
+	return org, nil
+}
+
+func (s *OrganizationServiceImpl) ListOrganizations(ctx context.Context) ([]*models.Organization, error) {
+	return s.Repo.FindAll(ctx)
+}
@@ -3,11 +3,16 @@ package services
 import (
 	"context"
 	"errors"
+	"shared/pkg/apperror"
+	"shared/pkg/listing"
+	"shared/pkg/log"
+	"strings"
 	"time"
 	"user-service/internal/models"
 	"user-service/internal/repository"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type TeamService interface {
@@ -17,7 +22,17 @@ type TeamService interface {
 	RemoveMember(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, requestorID uuid.UUID) error
 	AddManager(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, requestorID uuid.UUID) error
 	RemoveManager(ctx context.Context, teamID uuid.UUID, managerID uuid.UUID, requestorID uuid.UUID) error
-	GetAllTeams(ctx context.Context, requestorID uuid.UUID) ([]*models.TeamResponse, error)
+	TransferOwnership(ctx context.Context, teamID uuid.UUID, newOwnerID uuid.UUID, requestorID uuid.UUID) error
+	AdminTransferOwnership(ctx context.Context, teamID uuid.UUID, newOwnerID uuid.UUID) error
+	GetAllTeams(ctx context.Context, requestorID uuid.UUID, q models.TeamListQuery) ([]*models.TeamResponse, *listing.Cursor, error)
+	UpdateTeam(ctx context.Context, teamID uuid.UUID, req models.UpdateTeamRequest, requestorID uuid.UUID) (*models.TeamResponse, error)
+	ArchiveTeam(ctx context.Context, teamID uuid.UUID, requestorID uuid.UUID) error
+	UnarchiveTeam(ctx context.Context, teamID uuid.UUID, requestorID uuid.UUID) error
+	DeleteTeam(ctx context.Context, teamID uuid.UUID, requestorID uuid.UUID) error
+	BatchMembership(ctx context.Context, teamID uuid.UUID, req models.BatchMembershipRequest, requestorID uuid.UUID) (*models.BatchMembershipResponse, error)
+	SetParentTeam(ctx context.Context, teamID uuid.UUID, parentID *uuid.UUID, requestorID uuid.UUID) (*models.TeamResponse, error)
+	GetAncestors(ctx context.Context, teamID uuid.UUID) ([]*models.TeamNodeResponse, error)
+	GetDescendants(ctx context.Context, teamID uuid.UUID) ([]*models.TeamNodeResponse, error)
 }
 
 type TeamServiceImpl struct {
@@ -35,16 +50,23 @@ func NewTeamService(teamRepo repository.TeamRepository, userRepo repository.User
 func (s *TeamServiceImpl) CreateTeam(ctx context.Context, req models.CreateTeamRequest, creatorID uuid.UUID) (*models.TeamResponse, error) {
 	creator, err := s.UserRepo.FindByID(ctx, creatorID)
 	if err != nil {
-		return nil, errors.New("creator not found")
+		return nil, apperror.Lookup(err, "creator")
 	}
 
-	if creator.Role != "manager" {
-		return nil, errors.New("only managers can create teams")
+	if creator.Role != "manager" && creator.Role != "admin" {
+		return nil, apperror.Forbidden("only managers and admins can create teams")
+	}
+
+	parentID, err := s.newTeamParent(ctx, req.ParentID, creatorID)
+	if err != nil {
+		return nil, err
 	}
 
 	team := &models.Team{
 		ID:        uuid.New(),
 		TeamName:  req.TeamName,
+		OwnerID:   creatorID,
+		ParentID:  parentID,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -66,69 +88,71 @@ func (s *TeamServiceImpl) CreateTeam(ctx context.Context, req models.CreateTeamR
 		return nil, err
 	}
 
-	// Add other managers
+	// Add the other managers and the members, reporting whoever is skipped
+	var skipped []models.SkippedEntry
 	for _, manager := range req.Managers {
-		managerUUID, err := uuid.Parse(manager.UserID)
-		if err != nil {
-			continue // Skip invalid UUIDs
-		}
-
-		// Skip if it's the same as creator
-		if managerUUID == creatorID {
-			continue
-		}
-
-		// Verify user exists and is a manager
-		managerUser, err := s.UserRepo.FindByID(ctx, managerUUID)
-		if err != nil || managerUser.Role != "manager" {
-			continue // Skip if user doesn't exist or isn't a manager
-		}
-
-		teamMember := &models.TeamMember{
-			ID:       uuid.New(),
-			TeamID:   createdTeam.ID,
-			UserID:   managerUUID,
-			Role:     "manager",
-			JoinedAt: time.Now(),
+		if reason := s.addInitialMember(ctx, createdTeam.ID, manager.UserID, "manager", creatorID); reason != "" {
+			skipped = append(skipped, models.SkippedEntry{UserID: manager.UserID, Role: "manager", Reason: reason})
 		}
-		s.TeamRepo.AddMember(ctx, teamMember)
 	}
-
-	// Add members
 	for _, member := range req.Members {
-		memberUUID, err := uuid.Parse(member.UserID)
-		if err != nil {
-			continue // Skip invalid UUIDs
+		if reason := s.addInitialMember(ctx, createdTeam.ID, member.UserID, "member", creatorID); reason != "" {
+			skipped = append(skipped, models.SkippedEntry{UserID: member.UserID, Role: "member", Reason: reason})
 		}
+	}
 
-		// Skip if it's the same as creator
-		if memberUUID == creatorID {
-			continue
-		}
+	teamResponse, err := s.GetTeamByID(ctx, createdTeam.ID)
+	if err != nil {
+		return nil, err
+	}
+	teamResponse.Skipped = skipped
+	return teamResponse, nil
+}
 
-		// Verify user exists
-		_, err = s.UserRepo.FindByID(ctx, memberUUID)
-		if err != nil {
-			continue // Skip if user doesn't exist
-		}
+// addInitialMember adds a user listed in a CreateTeamRequest and returns why
+// it didn't, if it didn't.
+func (s *TeamServiceImpl) addInitialMember(ctx context.Context, teamID uuid.UUID, userIDParam, role string, creatorID uuid.UUID) string {
+	userID, err := uuid.Parse(userIDParam)
+	if err != nil {
+		return "invalid user ID"
+	}
+	if userID == creatorID {
+		return "the creator is already a manager"
+	}
 
-		teamMember := &models.TeamMember{
-			ID:       uuid.New(),
-			TeamID:   createdTeam.ID,
-			UserID:   memberUUID,
-			Role:     "member",
-			JoinedAt: time.Now(),
-		}
-		s.TeamRepo.AddMember(ctx, teamMember)
+	user, err := s.UserRepo.FindByID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "user not found"
+	}
+	if err != nil {
+		log.Error(ctx, "failed to look up team member", "user_id", userID, "error", err)
+		return "user lookup failed"
+	}
+	if role == "manager" && user.Role != "manager" {
+		return "user must have manager role to be added as team manager"
+	}
+	if s.TeamRepo.IsUserInTeam(ctx, teamID, userID) {
+		return "user is already a member of this team"
 	}
 
-	return s.GetTeamByID(ctx, createdTeam.ID)
+	teamMember := &models.TeamMember{
+		ID:       uuid.New(),
+		TeamID:   teamID,
+		UserID:   userID,
+		Role:     role,
+		JoinedAt: time.Now(),
+	}
+	if err := s.TeamRepo.AddMember(ctx, teamMember); err != nil {
+		log.Error(ctx, "failed to add team member", "user_id", userID, "error", err)
+		return "could not be added"
+	}
+	return ""
 }
 
 func (s *TeamServiceImpl) GetTeamByID(ctx context.Context, teamID uuid.UUID) (*models.TeamResponse, error) {
 	team, err := s.TeamRepo.FindByID(ctx, teamID)
 	if err != nil {
-		return nil, err
+		return nil, apperror.Lookup(err, "team")
 	}
 
 	members, err := s.TeamRepo.FindMembersByTeamID(ctx, teamID)
@@ -136,25 +160,33 @@ func (s *TeamServiceImpl) GetTeamByID(ctx context.Context, teamID uuid.UUID) (*m
 		return nil, err
 	}
 
-	var managers []models.TeamMemberResponse
-	var teamMembers []models.TeamMemberResponse
-
+	resolved := make([]models.TeamMember, 0, len(members))
 	for _, member := range members {
 		// If User is preloaded, use it; otherwise fetch separately
-		var memberUser *models.User
-		if member.User.ID != uuid.Nil {
-			memberUser = &member.User
-		} else {
-			memberUser, err = s.UserRepo.FindByID(ctx, member.UserID)
+		if member.User.ID == uuid.Nil {
+			memberUser, err := s.UserRepo.FindByID(ctx, member.UserID)
 			if err != nil {
 				continue // Skip if user not found
 			}
+			member.User = *memberUser
 		}
+		resolved = append(resolved, *member)
+	}
 
+	return newTeamResponse(team, resolved), nil
+}
+
+// newTeamResponse splits the members, whose users must be loaded, into
+// managers and members.
+func newTeamResponse(team *models.Team, members []models.TeamMember) *models.TeamResponse {
+	var managers []models.TeamMemberResponse
+	var teamMembers []models.TeamMemberResponse
+
+	for _, member := range members {
 		memberResponse := models.TeamMemberResponse{
-			UserID:   memberUser.ID,
-			UserName: memberUser.Username,
-			Email:    memberUser.Email,
+			UserID:   member.User.ID,
+			UserName: member.User.Username,
+			Email:    member.User.Email,
 			Role:     member.Role,
 			JoinedAt: member.JoinedAt,
 		}
@@ -167,30 +199,43 @@ func (s *TeamServiceImpl) GetTeamByID(ctx context.Context, teamID uuid.UUID) (*m
 	}
 
 	return &models.TeamResponse{
-		ID:        team.ID,
-		TeamName:  team.TeamName,
-		Managers:  managers,
-		Members:   teamMembers,
-		CreatedAt: team.CreatedAt,
-		UpdatedAt: team.UpdatedAt,
-	}, nil
+		ID:         team.ID,
+		TeamName:   team.TeamName,
+		OwnerID:    team.OwnerID,
+		ParentID:   team.ParentID,
+		ArchivedAt: team.ArchivedAt,
+		Managers:   managers,
+		Members:    teamMembers,
+		CreatedAt:  team.CreatedAt,
+		UpdatedAt:  team.UpdatedAt,
+	}
 }
 
+// AddMember adds the user straight away, without an invitation. The API
+// invites instead; this is for operators, through cmd/admin.
 func (s *TeamServiceImpl) AddMember(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, requestorID uuid.UUID) error {
 	// Check if requestor is a manager of the team
 	if !s.TeamRepo.IsUserManagerOfTeam(ctx, teamID, requestorID) {
-		return errors.New("only team managers can add members")
+		return apperror.Forbidden("only team managers can add members")
+	}
+
+	team, err := s.TeamRepo.FindByID(ctx, teamID)
+	if err != nil {
+		return apperror.Lookup(err, "team")
+	}
+	if err := requireActive(team); err != nil {
+		return err
 	}
 
 	// Check if user exists
-	_, err := s.UserRepo.FindByID(ctx, userID)
+	_, err = s.UserRepo.FindByID(ctx, userID)
 	if err != nil {
-		return errors.New("user not found")
+		return apperror.Lookup(err, "user")
 	}
 
 	// Check if user is already in the team
 	if s.TeamRepo.IsUserInTeam(ctx, teamID, userID) {
-		return errors.New("user is already a member of this team")
+		return apperror.Conflict("user is already a member of this team")
 	}
 
 	teamMember := &models.TeamMember{
@@ -207,36 +252,52 @@ func (s *TeamServiceImpl) AddMember(ctx context.Context, teamID uuid.UUID, userI
 func (s *TeamServiceImpl) RemoveMember(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, requestorID uuid.UUID) error {
 	// Check if requestor is a manager of the team
 	if !s.TeamRepo.IsUserManagerOfTeam(ctx, teamID, requestorID) {
-		return errors.New("only team managers can remove members")
+		return apperror.Forbidden("only team managers can remove members")
+	}
+
+	team, err := s.TeamRepo.FindByID(ctx, teamID)
+	if err != nil {
+		return apperror.Lookup(err, "team")
+	}
+	if err := requireActive(team); err != nil {
+		return err
 	}
 
 	// Check if user is in the team
 	if !s.TeamRepo.IsUserInTeam(ctx, teamID, userID) {
-		return errors.New("user is not a member of this team")
+		return apperror.NotFound("user is not a member of this team")
 	}
 
-	return s.TeamRepo.RemoveMember(ctx, teamID, userID)
+	// Managers are removed by the owner through RemoveManager
+	if s.TeamRepo.IsUserDirectManagerOfTeam(ctx, teamID, userID) {
+		return apperror.Conflict("user is a manager of this team; remove them as a manager instead")
+	}
+
+	return removeMember(ctx, s.TeamRepo, teamID, userID, "member")
 }
 
 func (s *TeamServiceImpl) AddManager(ctx context.Context, teamID uuid.UUID, userID uuid.UUID, requestorID uuid.UUID) error {
-	// Check if requestor is a manager of the team
-	if !s.TeamRepo.IsUserManagerOfTeam(ctx, teamID, requestorID) {
-		return errors.New("only team managers can add other managers")
+	team, err := s.requireOwner(ctx, teamID, requestorID, "only the team owner can add other managers")
+	if err != nil {
+		return err
+	}
+	if err := requireActive(team); err != nil {
+		return err
 	}
 
 	// Check if user exists and is a manager role
 	user, err := s.UserRepo.FindByID(ctx, userID)
 	if err != nil {
-		return errors.New("user not found")
+		return apperror.Lookup(err, "user")
 	}
 
 	if user.Role != "manager" {
-		return errors.New("user must have manager role to be added as team manager")
+		return apperror.Validation("user must have manager role to be added as team manager", nil)
 	}
 
 	// Check if user is already in the team
 	if s.TeamRepo.IsUserInTeam(ctx, teamID, userID) {
-		return errors.New("user is already a member of this team")
+		return apperror.Conflict("user is already a member of this team")
 	}
 
 	teamMember := &models.TeamMember{
@@ -251,47 +312,222 @@ func (s *TeamServiceImpl) AddManager(ctx context.Context, teamID uuid.UUID, user
 }
 
 func (s *TeamServiceImpl) RemoveManager(ctx context.Context, teamID uuid.UUID, managerID uuid.UUID, requestorID uuid.UUID) error {
-	// Check if requestor is a manager of the team
-	if !s.TeamRepo.IsUserManagerOfTeam(ctx, teamID, requestorID) {
-		return errors.New("only team managers can remove other managers")
+	team, err := s.requireOwner(ctx, teamID, requestorID, "only the team owner can remove other managers")
+	if err != nil {
+		return err
+	}
+	if err := requireActive(team); err != nil {
+		return err
+	}
+
+	// The owner stays so that the team keeps at least one manager
+	if managerID == requestorID {
+		return apperror.Conflict("the team owner can't be removed; transfer ownership first")
 	}
 
 	// Check if the manager to be removed is in the team as a manager
-	if !s.TeamRepo.IsUserManagerOfTeam(ctx, teamID, managerID) {
-		return errors.New("user is not a manager of this team")
+	if !s.TeamRepo.IsUserDirectManagerOfTeam(ctx, teamID, managerID) {
+		return apperror.NotFound("user is not a manager of this team")
 	}
 
-	return s.TeamRepo.RemoveMember(ctx, teamID, managerID)
+	return removeMember(ctx, s.TeamRepo, teamID, managerID, "manager")
 }
 
-func (s *TeamServiceImpl) GetAllTeams(ctx context.Context, requestorID uuid.UUID) ([]*models.TeamResponse, error) {
-	requestor, err := s.UserRepo.FindByID(ctx, requestorID)
+// TransferOwnership hands the team to another of its managers. The previous
+// owner stays on as a manager.
+func (s *TeamServiceImpl) TransferOwnership(ctx context.Context, teamID uuid.UUID, newOwnerID uuid.UUID, requestorID uuid.UUID) error {
+	team, err := s.requireOwner(ctx, teamID, requestorID, "only the team owner can transfer ownership")
 	if err != nil {
-		return nil, errors.New("requestor not found")
+		return err
+	}
+	if err := requireActive(team); err != nil {
+		return err
+	}
+
+	if newOwnerID == requestorID {
+		return apperror.BadRequest("user already owns this team")
+	}
+
+	if !s.TeamRepo.IsUserDirectManagerOfTeam(ctx, teamID, newOwnerID) {
+		return apperror.Validation("new owner must be a manager of this team", nil)
 	}
 
-	var teams []*models.Team
+	err = s.TeamRepo.TransferOwnership(ctx, teamID, requestorID, newOwnerID)
+	if errors.Is(err, repository.ErrOwnershipChanged) {
+		return apperror.Conflict("team ownership or managers changed; retry")
+	}
+	return err
+}
+
+// AdminTransferOwnership makes newOwnerID the owner whoever owns the team now,
+// first adding them as a manager when they aren't one. It is the operator path
+// for cmd/admin, which acts as no user, and also covers teams the owner
+// backfill left without an owner; the API never calls it.
+func (s *TeamServiceImpl) AdminTransferOwnership(ctx context.Context, teamID uuid.UUID, newOwnerID uuid.UUID) error {
+	team, err := s.TeamRepo.FindByID(ctx, teamID)
+	if err != nil {
+		return apperror.Lookup(err, "team")
+	}
+	if team.OwnerID == newOwnerID {
+		return apperror.BadRequest("user already owns this team")
+	}
 
-	if requestor.Role == "manager" {
-		teams, err = s.TeamRepo.FindAll(ctx)
+	if !s.TeamRepo.IsUserDirectManagerOfTeam(ctx, teamID, newOwnerID) {
+		user, err := s.UserRepo.FindByID(ctx, newOwnerID)
 		if err != nil {
-			return nil, err
+			return apperror.Lookup(err, "user")
+		}
+		if user.Role != "manager" {
+			return apperror.Validation("new owner must have the manager role", nil)
+		}
+		if s.TeamRepo.IsUserInTeam(ctx, teamID, newOwnerID) {
+			return apperror.Conflict("user is already a member of this team; make them a manager first")
 		}
-	} else {
-		teams, err = s.TeamRepo.FindUserTeams(ctx, requestorID)
+		err = s.TeamRepo.AddMember(ctx, &models.TeamMember{
+			ID:       uuid.New(),
+			TeamID:   teamID,
+			UserID:   newOwnerID,
+			Role:     "manager",
+			JoinedAt: time.Now(),
+		})
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	var teamResponses []*models.TeamResponse
+	err = s.TeamRepo.AssignOwner(ctx, teamID, newOwnerID)
+	if errors.Is(err, repository.ErrOwnershipChanged) {
+		return apperror.Conflict("team managers changed; retry")
+	}
+	return err
+}
+
+// requireOwner returns the team, or a forbidden error with msg unless userID
+// owns it. uuid.Nil owns nothing, so teams without an owner refuse everyone.
+func (s *TeamServiceImpl) requireOwner(ctx context.Context, teamID, userID uuid.UUID, msg string) (*models.Team, error) {
+	team, err := s.TeamRepo.FindByID(ctx, teamID)
+	if err != nil {
+		return nil, apperror.Lookup(err, "team")
+	}
+	if userID == uuid.Nil || team.OwnerID != userID {
+		return nil, apperror.Forbidden("%s", msg)
+	}
+	return team, nil
+}
+
+// requireActive returns a conflict for archived teams, which are read-only.
+func requireActive(team *models.Team) error {
+	if team.ArchivedAt != nil {
+		return apperror.Conflict("team is archived")
+	}
+	return nil
+}
+
+// removeMember removes the user after the caller's checks. The repository
+// still refuses to remove the owner, which a concurrent ownership transfer
+// can lead to; that is reported as a conflict.
+func removeMember(ctx context.Context, repo repository.TeamRepository, teamID, userID uuid.UUID, what string) error {
+	err := repo.RemoveMember(ctx, teamID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.Conflict("%s was not removed; the team changed meanwhile", what)
+	}
+	return err
+}
+
+// GetAllTeams returns one page of the teams the requestor may see under
+// teamVisibility.
+func (s *TeamServiceImpl) GetAllTeams(ctx context.Context, requestorID uuid.UUID, q models.TeamListQuery) ([]*models.TeamResponse, *listing.Cursor, error) {
+	q, err := normalizeTeamListQuery(q)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	requestor, err := s.UserRepo.FindByID(ctx, requestorID)
+	if err != nil {
+		return nil, nil, apperror.Lookup(err, "requestor")
+	}
+
+	teams, next, err := s.TeamRepo.FindTeams(ctx, teamVisibility(requestor), q)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	responses := make([]*models.TeamResponse, 0, len(teams))
 	for _, team := range teams {
-		teamResponse, err := s.GetTeamByID(ctx, team.ID)
-		if err != nil {
-			continue
-		}
-		teamResponses = append(teamResponses, teamResponse)
+		responses = append(responses, newTeamResponse(team, team.TeamMembers))
+	}
+	return responses, next, nil
+}
+
+func (s *TeamServiceImpl) UpdateTeam(ctx context.Context, teamID uuid.UUID, req models.UpdateTeamRequest, requestorID uuid.UUID) (*models.TeamResponse, error) {
+	team, err := s.requireOwner(ctx, teamID, requestorID, "only the team owner can update the team")
+	if err != nil {
+		return nil, err
+	}
+	if err := requireActive(team); err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.TeamName)
+	if name == "" {
+		return nil, apperror.Validation("invalid team", map[string]string{"teamName": "must not be blank"})
 	}
 
-	return teamResponses, nil
+	if err := s.TeamRepo.Update(ctx, teamID, map[string]any{"team_name": name}); err != nil {
+		return nil, apperror.Lookup(err, "team")
+	}
+	return s.GetTeamByID(ctx, teamID)
+}
+
+// ArchiveTeam makes the team read-only and hides it from listings; its
+// members are kept.
+func (s *TeamServiceImpl) ArchiveTeam(ctx context.Context, teamID uuid.UUID, requestorID uuid.UUID) error {
+	team, err := s.requireOwner(ctx, teamID, requestorID, "only the team owner can archive the team")
+	if err != nil {
+		return err
+	}
+	if team.ArchivedAt != nil {
+		return apperror.Conflict("team is already archived")
+	}
+
+	if err := s.TeamRepo.Update(ctx, teamID, map[string]any{"archived_at": time.Now()}); err != nil {
+		return apperror.Lookup(err, "team")
+	}
+	return nil
+}
+
+func (s *TeamServiceImpl) UnarchiveTeam(ctx context.Context, teamID uuid.UUID, requestorID uuid.UUID) error {
+	team, err := s.requireOwner(ctx, teamID, requestorID, "only the team owner can unarchive the team")
+	if err != nil {
+		return err
+	}
+	if team.ArchivedAt == nil {
+		return apperror.Conflict("team is not archived")
+	}
+
+	if err := s.TeamRepo.Update(ctx, teamID, map[string]any{"archived_at": nil}); err != nil {
+		return apperror.Lookup(err, "team")
+	}
+	return nil
+}
+
+// DeleteTeam removes the team and its memberships for good. Teams with
+// sub-teams are kept until those are moved or deleted.
+func (s *TeamServiceImpl) DeleteTeam(ctx context.Context, teamID uuid.UUID, requestorID uuid.UUID) error {
+	if _, err := s.requireOwner(ctx, teamID, requestorID, "only the team owner can delete the team"); err != nil {
+		return err
+	}
+
+	subTeams, err := s.TeamRepo.FindDescendants(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if len(subTeams) > 0 {
+		return apperror.Conflict("team has sub-teams; move or delete them first")
+	}
+
+	if err := s.TeamRepo.Delete(ctx, teamID); err != nil {
+		return apperror.Lookup(err, "team")
+	}
+	return nil
 }
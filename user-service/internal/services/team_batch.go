@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"shared/pkg/apperror"
+	"shared/pkg/log"
+	"user-service/internal/models"
+	"user-service/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxBatchOperations bounds the operations of one BatchMembership call.
+const maxBatchOperations = 100
+
+// BatchMembership validates every operation against the team as it is, under
+// the same rules as the single-user methods, and applies them in one
+// transaction only if all are valid. Errors affecting the whole request, such
+// as a requestor who doesn't manage the team, are returned; everything else
+// is reported per operation.
+func (s *TeamServiceImpl) BatchMembership(ctx context.Context, teamID uuid.UUID, req models.BatchMembershipRequest, requestorID uuid.UUID) (*models.BatchMembershipResponse, error) {
+	if len(req.Operations) > maxBatchOperations {
+		return nil, apperror.Validation("too many operations", map[string]string{"operations": "at most 100 per batch"})
+	}
+
+	if !s.TeamRepo.IsUserManagerOfTeam(ctx, teamID, requestorID) {
+		return nil, apperror.Forbidden("only team managers can change members")
+	}
+
+	team, err := s.TeamRepo.FindByID(ctx, teamID)
+	if err != nil {
+		return nil, apperror.Lookup(err, "team")
+	}
+	if err := requireActive(team); err != nil {
+		return nil, err
+	}
+
+	members, err := s.TeamRepo.FindMembersByTeamID(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	roles := make(map[uuid.UUID]string, len(members))
+	for _, member := range members {
+		roles[member.UserID] = member.Role
+	}
+
+	resp := &models.BatchMembershipResponse{Results: make([]models.MembershipResult, len(req.Operations))}
+	changes := make([]models.MembershipChange, 0, len(req.Operations))
+	seen := make(map[uuid.UUID]bool, len(req.Operations))
+	failed := false
+
+	for i, op := range req.Operations {
+		result := &resp.Results[i]
+		result.Op, result.UserID, result.Role = op.Op, op.UserID, op.Role
+
+		change, err := s.membershipChange(ctx, op, team, requestorID, roles, seen)
+		if err != nil {
+			failed = true
+			result.Outcome = models.OutcomeFailed
+			result.Code, result.Error = resultError(ctx, err)
+			continue
+		}
+		result.Role = change.Role
+		changes = append(changes, change)
+	}
+
+	if failed {
+		for i := range resp.Results {
+			if resp.Results[i].Outcome == "" {
+				resp.Results[i].Outcome = models.OutcomeNotApplied
+			}
+		}
+		return resp, nil
+	}
+
+	err = s.TeamRepo.ApplyMembershipChanges(ctx, teamID, changes)
+	if errors.Is(err, repository.ErrMembershipChanged) {
+		return nil, apperror.Conflict("team membership changed while the batch was applied; retry")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Applied = true
+	for i := range resp.Results {
+		resp.Results[i].Outcome = models.OutcomeApplied
+	}
+	return resp, nil
+}
+
+// membershipChange checks one batch operation and returns the change to
+// apply. seen tracks the users already named in the batch.
+func (s *TeamServiceImpl) membershipChange(ctx context.Context, op models.MembershipOperation, team *models.Team, requestorID uuid.UUID, roles map[uuid.UUID]string, seen map[uuid.UUID]bool) (models.MembershipChange, error) {
+	var change models.MembershipChange
+
+	userID, err := uuid.Parse(op.UserID)
+	if err != nil {
+		return change, apperror.BadRequest("invalid user ID")
+	}
+	if seen[userID] {
+		return change, apperror.Validation("user appears more than once in the batch", nil)
+	}
+	seen[userID] = true
+
+	isOwner := team.OwnerID == requestorID
+	current := roles[userID]
+	change = models.MembershipChange{Op: op.Op, UserID: userID, Role: op.Role}
+
+	switch op.Op {
+	case models.MembershipAdd:
+		if change.Role == "" {
+			change.Role = "member"
+		}
+		if err := validateTeamRole(change.Role); err != nil {
+			return change, err
+		}
+		if current != "" {
+			return change, apperror.Conflict("user is already a member of this team")
+		}
+		if change.Role == "manager" && !isOwner {
+			return change, apperror.Forbidden("only the team owner can add other managers")
+		}
+		if err := s.requireUserRole(ctx, userID, change.Role); err != nil {
+			return change, err
+		}
+
+	case models.MembershipRemove:
+		if current == "" {
+			return change, apperror.NotFound("user is not a member of this team")
+		}
+		if userID == team.OwnerID {
+			return change, apperror.Conflict("the team owner can't be removed; transfer ownership first")
+		}
+		if current == "manager" && !isOwner {
+			return change, apperror.Forbidden("only the team owner can remove other managers")
+		}
+		change.Role = current
+
+	case models.MembershipSetRole:
+		if err := validateTeamRole(change.Role); err != nil {
+			return change, err
+		}
+		if current == "" {
+			return change, apperror.NotFound("user is not a member of this team")
+		}
+		if userID == team.OwnerID {
+			return change, apperror.Conflict("the team owner's role can't change")
+		}
+		if !isOwner {
+			return change, apperror.Forbidden("only the team owner can change roles")
+		}
+		if current == change.Role {
+			return change, apperror.Conflict("user already has the %s role", current)
+		}
+		if err := s.requireUserRole(ctx, userID, change.Role); err != nil {
+			return change, err
+		}
+
+	default:
+		return change, apperror.Validation("unknown operation", map[string]string{"op": "must be add, remove or setRole"})
+	}
+	return change, nil
+}
+
+// requireUserRole checks that the user exists and, for team managers, has the
+// manager role in the system.
+func (s *TeamServiceImpl) requireUserRole(ctx context.Context, userID uuid.UUID, teamRole string) error {
+	user, err := s.UserRepo.FindByID(ctx, userID)
+	if err != nil {
+		return apperror.Lookup(err, "user")
+	}
+	if teamRole == "manager" && user.Role != "manager" {
+		return apperror.Validation("user must have manager role to be added as team manager", nil)
+	}
+	return nil
+}
+
+func validateTeamRole(role string) error {
+	if role != "manager" && role != "member" {
+		return apperror.Validation("invalid role", map[string]string{"role": "must be manager or member"})
+	}
+	return nil
+}
+
+// resultError returns the code and client-safe message of a per-operation
+// error, logging failures that are not domain errors.
+func resultError(ctx context.Context, err error) (string, string) {
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) && appErr.Kind != apperror.KindInternal {
+		return string(appErr.Kind), appErr.Message
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return string(apperror.KindNotFound), "not found"
+	}
+	log.Error(ctx, "batch membership operation failed", "error", err)
+	return string(apperror.KindInternal), "internal error"
+}
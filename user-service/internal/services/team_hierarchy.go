@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"shared/pkg/apperror"
+	"user-service/internal/models"
+	"user-service/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// SetParentTeam moves the team under another one, or to the top level when
+// parentID is nil. Whoever manages the current parent decides where the team
+// goes, or its owner for a top-level team, and the new parent's managers must
+// accept it, so a team can't slip out from under its department.
+func (s *TeamServiceImpl) SetParentTeam(ctx context.Context, teamID uuid.UUID, parentID *uuid.UUID, requestorID uuid.UUID) (*models.TeamResponse, error) {
+	team, err := s.TeamRepo.FindByID(ctx, teamID)
+	if err != nil {
+		return nil, apperror.Lookup(err, "team")
+	}
+	if err := requireActive(team); err != nil {
+		return nil, err
+	}
+
+	if team.ParentID != nil {
+		if !s.TeamRepo.IsUserManagerOfTeam(ctx, *team.ParentID, requestorID) {
+			return nil, apperror.Forbidden("only managers of the parent team can move the team")
+		}
+	} else if team.OwnerID != requestorID {
+		return nil, apperror.Forbidden("only the team owner can move a top-level team")
+	}
+
+	if sameParent(team.ParentID, parentID) {
+		return nil, apperror.BadRequest("team already has this parent")
+	}
+
+	if parentID != nil {
+		if err := s.requireParent(ctx, *parentID, requestorID); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.TeamRepo.SetParent(ctx, teamID, parentID)
+	switch {
+	case errors.Is(err, repository.ErrHierarchyCycle):
+		return nil, apperror.Conflict("a team can't be moved under itself or one of its sub-teams")
+	case errors.Is(err, repository.ErrHierarchyTooDeep):
+		return nil, tooDeepError()
+	case err != nil:
+		return nil, apperror.Lookup(err, "team")
+	}
+	return s.GetTeamByID(ctx, teamID)
+}
+
+// GetAncestors returns the teams above the team, its parent first.
+func (s *TeamServiceImpl) GetAncestors(ctx context.Context, teamID uuid.UUID) ([]*models.TeamNodeResponse, error) {
+	if _, err := s.TeamRepo.FindByID(ctx, teamID); err != nil {
+		return nil, apperror.Lookup(err, "team")
+	}
+
+	ancestors, err := s.TeamRepo.FindAncestors(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*models.TeamNodeResponse, len(ancestors))
+	for i, team := range ancestors {
+		nodes[i] = teamNode(team, i+1)
+	}
+	return nodes, nil
+}
+
+// GetDescendants returns the teams beneath the team, level by level.
+func (s *TeamServiceImpl) GetDescendants(ctx context.Context, teamID uuid.UUID) ([]*models.TeamNodeResponse, error) {
+	if _, err := s.TeamRepo.FindByID(ctx, teamID); err != nil {
+		return nil, apperror.Lookup(err, "team")
+	}
+
+	descendants, err := s.TeamRepo.FindDescendants(ctx, teamID)
+	if err != nil {
+		return nil, err
+	}
+	depth := map[uuid.UUID]int{teamID: 0}
+	nodes := make([]*models.TeamNodeResponse, len(descendants))
+	for i, team := range descendants {
+		if team.ParentID != nil {
+			depth[team.ID] = depth[*team.ParentID] + 1
+		}
+		nodes[i] = teamNode(team, depth[team.ID])
+	}
+	return nodes, nil
+}
+
+// newTeamParent resolves the parent named in a CreateTeamRequest, if any.
+func (s *TeamServiceImpl) newTeamParent(ctx context.Context, parentIDParam string, creatorID uuid.UUID) (*uuid.UUID, error) {
+	if parentIDParam == "" {
+		return nil, nil
+	}
+	parentID, err := uuid.Parse(parentIDParam)
+	if err != nil {
+		return nil, apperror.Validation("invalid team", map[string]string{"parentId": "must be a UUID"})
+	}
+	if err := s.requireParent(ctx, parentID, creatorID); err != nil {
+		return nil, err
+	}
+
+	ancestors, err := s.TeamRepo.FindAncestors(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(ancestors)+2 > repository.MaxTeamDepth {
+		return nil, tooDeepError()
+	}
+	return &parentID, nil
+}
+
+// requireParent checks that the team can take sub-teams from the user.
+func (s *TeamServiceImpl) requireParent(ctx context.Context, parentID, userID uuid.UUID) error {
+	parent, err := s.TeamRepo.FindByID(ctx, parentID)
+	if err != nil {
+		return apperror.Lookup(err, "parent team")
+	}
+	if parent.ArchivedAt != nil {
+		return apperror.Conflict("parent team is archived")
+	}
+	if !s.TeamRepo.IsUserManagerOfTeam(ctx, parentID, userID) {
+		return apperror.Forbidden("only managers of the parent team can add teams under it")
+	}
+	return nil
+}
+
+func tooDeepError() error {
+	return apperror.Validation("team hierarchy too deep", map[string]string{
+		"parentId": fmt.Sprintf("teams nest at most %d levels", repository.MaxTeamDepth),
+	})
+}
+
+func sameParent(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func teamNode(team *models.Team, depth int) *models.TeamNodeResponse {
+	return &models.TeamNodeResponse{
+		ID:         team.ID,
+		TeamName:   team.TeamName,
+		OwnerID:    team.OwnerID,
+		ParentID:   team.ParentID,
+		ArchivedAt: team.ArchivedAt,
+		Depth:      depth,
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"shared/pkg/apperror"
+	"testing"
+	"user-service/internal/models"
+	"user-service/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// memoryTreeRepo keeps several teams and their direct roles in memory.
+type memoryTreeRepo struct {
+	repository.TeamRepository
+	teams map[uuid.UUID]*models.Team
+	roles map[uuid.UUID]map[uuid.UUID]string
+}
+
+func (r *memoryTreeRepo) FindByID(_ context.Context, id uuid.UUID) (*models.Team, error) {
+	if team, ok := r.teams[id]; ok {
+		copied := *team
+		return &copied, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryTreeRepo) IsUserInTeam(_ context.Context, teamID, userID uuid.UUID) bool {
+	return r.roles[teamID][userID] != ""
+}
+
+func (r *memoryTreeRepo) IsUserDirectManagerOfTeam(_ context.Context, teamID, userID uuid.UUID) bool {
+	return r.roles[teamID][userID] == "manager"
+}
+
+func (r *memoryTreeRepo) IsUserManagerOfTeam(ctx context.Context, teamID, userID uuid.UUID) bool {
+	for team := r.teams[teamID]; team != nil; team = r.parent(team) {
+		if r.roles[team.ID][userID] == "manager" {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryTreeRepo) AddMember(_ context.Context, m *models.TeamMember) error {
+	r.roles[m.TeamID][m.UserID] = m.Role
+	return nil
+}
+
+func (r *memoryTreeRepo) SetParent(_ context.Context, teamID uuid.UUID, parentID *uuid.UUID) error {
+	if parentID != nil {
+		for team := r.teams[*parentID]; team != nil; team = r.parent(team) {
+			if team.ID == teamID {
+				return repository.ErrHierarchyCycle
+			}
+		}
+	}
+	r.teams[teamID].ParentID = parentID
+	return nil
+}
+
+func (r *memoryTreeRepo) FindMembersByTeamID(context.Context, uuid.UUID) ([]*models.TeamMember, error) {
+	return nil, nil
+}
+
+func (r *memoryTreeRepo) parent(team *models.Team) *models.Team {
+	if team.ParentID == nil {
+		return nil
+	}
+	return r.teams[*team.ParentID]
+}
+
+func TestTeamService_ManagersManageSubTeams(t *testing.T) {
+	head, lead, dev := uuid.New(), uuid.New(), uuid.New()
+	department := &models.Team{ID: uuid.New(), OwnerID: head}
+	squad := &models.Team{ID: uuid.New(), OwnerID: lead, ParentID: &department.ID}
+	repo := &memoryTreeRepo{
+		teams: map[uuid.UUID]*models.Team{department.ID: department, squad.ID: squad},
+		roles: map[uuid.UUID]map[uuid.UUID]string{
+			department.ID: {head: "manager"},
+			squad.ID:      {lead: "manager"},
+		},
+	}
+	users := &memoryUserRepo{users: map[uuid.UUID]*models.User{dev: {ID: dev, Role: "member"}}}
+	svc := NewTeamService(repo, users)
+	ctx := context.Background()
+
+	if err := svc.AddMember(ctx, squad.ID, dev, head); err != nil {
+		t.Fatalf("Expected the department head to add a squad member, got %v", err)
+	}
+	assertKind(t, svc.AddMember(ctx, department.ID, dev, lead), apperror.KindForbidden)
+
+	// The squad can't leave the department without its managers' consent
+	_, err := svc.SetParentTeam(ctx, squad.ID, nil, lead)
+	assertKind(t, err, apperror.KindForbidden)
+	_, err = svc.SetParentTeam(ctx, department.ID, &squad.ID, head)
+	assertKind(t, err, apperror.KindConflict)
+
+	if _, err := svc.SetParentTeam(ctx, squad.ID, nil, head); err != nil {
+		t.Fatalf("Expected the department head to detach the squad, got %v", err)
+	}
+	if repo.IsUserManagerOfTeam(ctx, squad.ID, head) {
+		t.Error("Expected the department head to no longer manage the squad")
+	}
+}
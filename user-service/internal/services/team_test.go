@@ -0,0 +1,317 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"shared/pkg/apperror"
+	"testing"
+	"time"
+	"user-service/internal/models"
+	"user-service/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// memoryTeamRepo keeps one team's roles in memory; methods the ownership
+// rules don't use are left unimplemented.
+type memoryTeamRepo struct {
+	repository.TeamRepository
+	team  models.Team
+	roles map[uuid.UUID]string
+}
+
+func (r *memoryTeamRepo) Create(_ context.Context, team *models.Team) (*models.Team, error) {
+	r.team = *team
+	return team, nil
+}
+
+func (r *memoryTeamRepo) FindByID(_ context.Context, id uuid.UUID) (*models.Team, error) {
+	if id != r.team.ID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	team := r.team
+	return &team, nil
+}
+
+func (r *memoryTeamRepo) IsUserInTeam(_ context.Context, _, userID uuid.UUID) bool {
+	return r.roles[userID] != ""
+}
+
+func (r *memoryTeamRepo) IsUserManagerOfTeam(_ context.Context, _, userID uuid.UUID) bool {
+	return r.roles[userID] == "manager"
+}
+
+func (r *memoryTeamRepo) IsUserDirectManagerOfTeam(ctx context.Context, teamID, userID uuid.UUID) bool {
+	return r.IsUserManagerOfTeam(ctx, teamID, userID)
+}
+
+func (r *memoryTeamRepo) FindDescendants(context.Context, uuid.UUID) ([]*models.Team, error) {
+	return nil, nil
+}
+
+func (r *memoryTeamRepo) AddMember(_ context.Context, m *models.TeamMember) error {
+	r.roles[m.UserID] = m.Role
+	return nil
+}
+
+func (r *memoryTeamRepo) RemoveMember(_ context.Context, _, userID uuid.UUID) error {
+	if userID == r.team.OwnerID || r.roles[userID] == "" {
+		return gorm.ErrRecordNotFound
+	}
+	delete(r.roles, userID)
+	return nil
+}
+
+func (r *memoryTeamRepo) TransferOwnership(ctx context.Context, teamID, fromID, toID uuid.UUID) error {
+	if fromID == uuid.Nil || r.team.OwnerID != fromID {
+		return repository.ErrOwnershipChanged
+	}
+	return r.AssignOwner(ctx, teamID, toID)
+}
+
+func (r *memoryTeamRepo) AssignOwner(_ context.Context, _, toID uuid.UUID) error {
+	if r.roles[toID] != "manager" {
+		return repository.ErrOwnershipChanged
+	}
+	r.team.OwnerID = toID
+	return nil
+}
+
+func (r *memoryTeamRepo) Update(_ context.Context, _ uuid.UUID, changes map[string]any) error {
+	if v, ok := changes["archived_at"]; ok {
+		r.team.ArchivedAt = nil
+		if at, ok := v.(time.Time); ok {
+			r.team.ArchivedAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *memoryTeamRepo) FindMembersByTeamID(_ context.Context, teamID uuid.UUID) ([]*models.TeamMember, error) {
+	members := make([]*models.TeamMember, 0, len(r.roles))
+	for id, role := range r.roles {
+		members = append(members, &models.TeamMember{TeamID: teamID, UserID: id, Role: role})
+	}
+	return members, nil
+}
+
+func (r *memoryTeamRepo) ApplyMembershipChanges(_ context.Context, _ uuid.UUID, changes []models.MembershipChange) error {
+	for _, c := range changes {
+		if c.Op == models.MembershipRemove {
+			delete(r.roles, c.UserID)
+		} else {
+			r.roles[c.UserID] = c.Role
+		}
+	}
+	return nil
+}
+
+type memoryUserRepo struct {
+	repository.UserRepository
+	users map[uuid.UUID]*models.User
+}
+
+func (r *memoryUserRepo) FindByID(_ context.Context, id uuid.UUID) (*models.User, error) {
+	if u, ok := r.users[id]; ok {
+		return u, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func newOwnedTeam() (svc TeamService, repo *memoryTeamRepo, owner, manager, member uuid.UUID) {
+	owner, manager, member = uuid.New(), uuid.New(), uuid.New()
+	repo = &memoryTeamRepo{
+		team:  models.Team{ID: uuid.New(), OwnerID: owner},
+		roles: map[uuid.UUID]string{owner: "manager", manager: "manager", member: "member"},
+	}
+	users := &memoryUserRepo{users: map[uuid.UUID]*models.User{}}
+	for id, role := range repo.roles {
+		users.users[id] = &models.User{ID: id, Role: role}
+	}
+	return NewTeamService(repo, users), repo, owner, manager, member
+}
+
+func assertKind(t *testing.T, err error, want apperror.Kind) {
+	t.Helper()
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) || appErr.Kind != want {
+		t.Errorf("Expected a %s error, got %v", want, err)
+	}
+}
+
+func TestTeamService_OnlyOwnerManagesManagers(t *testing.T) {
+	svc, repo, owner, manager, _ := newOwnedTeam()
+	ctx := context.Background()
+
+	assertKind(t, svc.RemoveManager(ctx, repo.team.ID, owner, manager), apperror.KindForbidden)
+	assertKind(t, svc.AddManager(ctx, repo.team.ID, uuid.New(), manager), apperror.KindForbidden)
+
+	if err := svc.RemoveManager(ctx, repo.team.ID, manager, owner); err != nil {
+		t.Fatalf("Expected the owner to remove a manager, got %v", err)
+	}
+	if repo.roles[manager] != "" {
+		t.Error("Expected the manager to be removed")
+	}
+}
+
+func TestTeamService_TeamKeepsItsOwner(t *testing.T) {
+	svc, repo, owner, manager, _ := newOwnedTeam()
+	ctx := context.Background()
+
+	assertKind(t, svc.RemoveManager(ctx, repo.team.ID, owner, owner), apperror.KindConflict)
+	assertKind(t, svc.RemoveMember(ctx, repo.team.ID, owner, manager), apperror.KindConflict)
+	if repo.roles[owner] != "manager" {
+		t.Error("Expected the owner to stay a manager")
+	}
+}
+
+func TestTeamService_TransferOwnership(t *testing.T) {
+	svc, repo, owner, manager, member := newOwnedTeam()
+	ctx := context.Background()
+
+	assertKind(t, svc.TransferOwnership(ctx, repo.team.ID, member, owner), apperror.KindValidation)
+	assertKind(t, svc.TransferOwnership(ctx, repo.team.ID, owner, manager), apperror.KindForbidden)
+
+	if err := svc.TransferOwnership(ctx, repo.team.ID, manager, owner); err != nil {
+		t.Fatalf("Expected ownership to move, got %v", err)
+	}
+	if repo.team.OwnerID != manager {
+		t.Errorf("Expected %s to own the team, got %s", manager, repo.team.OwnerID)
+	}
+
+	// The previous owner is now an ordinary manager the new owner can remove.
+	if err := svc.RemoveManager(ctx, repo.team.ID, owner, manager); err != nil {
+		t.Errorf("Expected the new owner to remove the previous one, got %v", err)
+	}
+}
+
+func TestTeamService_ArchivedTeamIsReadOnly(t *testing.T) {
+	svc, repo, owner, manager, member := newOwnedTeam()
+	ctx := context.Background()
+
+	assertKind(t, svc.ArchiveTeam(ctx, repo.team.ID, manager), apperror.KindForbidden)
+	if err := svc.ArchiveTeam(ctx, repo.team.ID, owner); err != nil {
+		t.Fatalf("Expected the owner to archive the team, got %v", err)
+	}
+
+	assertKind(t, svc.AddMember(ctx, repo.team.ID, uuid.New(), manager), apperror.KindConflict)
+	_, err := svc.UpdateTeam(ctx, repo.team.ID, models.UpdateTeamRequest{TeamName: "Renamed"}, owner)
+	assertKind(t, err, apperror.KindConflict)
+	assertKind(t, svc.ArchiveTeam(ctx, repo.team.ID, owner), apperror.KindConflict)
+	assertKind(t, svc.RemoveMember(ctx, repo.team.ID, member, manager), apperror.KindConflict)
+	assertKind(t, svc.RemoveManager(ctx, repo.team.ID, manager, owner), apperror.KindConflict)
+	assertKind(t, svc.TransferOwnership(ctx, repo.team.ID, manager, owner), apperror.KindConflict)
+
+	if err := svc.UnarchiveTeam(ctx, repo.team.ID, owner); err != nil {
+		t.Fatalf("Expected the owner to unarchive the team, got %v", err)
+	}
+	if repo.team.ArchivedAt != nil {
+		t.Error("Expected the team to be active again")
+	}
+}
+
+func TestTeamService_BatchMembershipIsAllOrNothing(t *testing.T) {
+	svc, repo, owner, manager, member := newOwnedTeam()
+	ctx := context.Background()
+	newcomer := uuid.New()
+	svc.(*TeamServiceImpl).UserRepo.(*memoryUserRepo).users[newcomer] = &models.User{ID: newcomer, Role: "member"}
+
+	// Removing the owner fails, so nothing else is applied either
+	req := models.BatchMembershipRequest{Operations: []models.MembershipOperation{
+		{Op: models.MembershipAdd, UserID: newcomer.String()},
+		{Op: models.MembershipRemove, UserID: owner.String()},
+	}}
+	resp, err := svc.BatchMembership(ctx, repo.team.ID, req, owner)
+	if err != nil {
+		t.Fatalf("Expected per-operation results, got %v", err)
+	}
+	if resp.Applied || resp.Results[0].Outcome != models.OutcomeNotApplied || resp.Results[1].Outcome != models.OutcomeFailed {
+		t.Errorf("Expected the batch to be rejected on its second operation, got %+v", resp)
+	}
+	if resp.Results[1].Code != string(apperror.KindConflict) {
+		t.Errorf("Expected a %s code, got %q", apperror.KindConflict, resp.Results[1].Code)
+	}
+	if repo.roles[newcomer] != "" {
+		t.Error("Expected the newcomer not to be added")
+	}
+
+	req.Operations[1] = models.MembershipOperation{Op: models.MembershipSetRole, UserID: manager.String(), Role: "member"}
+	req.Operations = append(req.Operations, models.MembershipOperation{Op: models.MembershipRemove, UserID: member.String()})
+	resp, err = svc.BatchMembership(ctx, repo.team.ID, req, owner)
+	if err != nil || !resp.Applied {
+		t.Fatalf("Expected the batch to be applied, got %+v, %v", resp, err)
+	}
+	if repo.roles[newcomer] != "member" || repo.roles[manager] != "member" || repo.roles[member] != "" {
+		t.Errorf("Expected every operation to be applied, got %v", repo.roles)
+	}
+}
+
+func TestTeamService_NilRequestorOwnsNothing(t *testing.T) {
+	svc, repo, _, manager, member := newOwnedTeam()
+	repo.team.OwnerID = uuid.Nil
+	ctx := context.Background()
+
+	assertKind(t, svc.TransferOwnership(ctx, repo.team.ID, manager, member), apperror.KindForbidden)
+	assertKind(t, svc.TransferOwnership(ctx, repo.team.ID, manager, uuid.Nil), apperror.KindForbidden)
+	assertKind(t, svc.AddManager(ctx, repo.team.ID, member, uuid.Nil), apperror.KindForbidden)
+	assertKind(t, svc.DeleteTeam(ctx, repo.team.ID, uuid.Nil), apperror.KindForbidden)
+	if repo.team.OwnerID != uuid.Nil {
+		t.Errorf("Expected the team to stay without an owner, got %s", repo.team.OwnerID)
+	}
+}
+
+func TestTeamService_AdminTransfersOwnership(t *testing.T) {
+	_, repo, owner, manager, member := newOwnedTeam()
+	repo.team.OwnerID = uuid.Nil
+	outsider := uuid.New()
+	svc := NewTeamService(repo, &memoryUserRepo{users: map[uuid.UUID]*models.User{
+		member:   {ID: member, Role: "member"},
+		outsider: {ID: outsider, Role: "manager"},
+	}})
+	ctx := context.Background()
+
+	if err := svc.AdminTransferOwnership(ctx, repo.team.ID, manager); err != nil {
+		t.Fatalf("Expected the operator to transfer the orphaned team, got %v", err)
+	}
+	if repo.team.OwnerID != manager {
+		t.Errorf("Expected %s to own the team, got %s", manager, repo.team.OwnerID)
+	}
+
+	assertKind(t, svc.AdminTransferOwnership(ctx, repo.team.ID, manager), apperror.KindBadRequest)
+	assertKind(t, svc.AdminTransferOwnership(ctx, repo.team.ID, member), apperror.KindValidation)
+	assertKind(t, svc.AdminTransferOwnership(ctx, uuid.New(), owner), apperror.KindNotFound)
+
+	// A manager outside the team is added as a manager first
+	if err := svc.AdminTransferOwnership(ctx, repo.team.ID, outsider); err != nil {
+		t.Fatalf("Expected the operator to hand the team to a new manager, got %v", err)
+	}
+	if repo.team.OwnerID != outsider || repo.roles[outsider] != "manager" {
+		t.Errorf("Expected %s to own and manage the team, got %s and %q", outsider, repo.team.OwnerID, repo.roles[outsider])
+	}
+}
+
+func TestTeamService_ManagersAndAdminsCreateTeams(t *testing.T) {
+	admin, manager, member := uuid.New(), uuid.New(), uuid.New()
+	repo := &memoryTeamRepo{roles: map[uuid.UUID]string{}}
+	users := &memoryUserRepo{users: map[uuid.UUID]*models.User{
+		admin:   {ID: admin, Role: "admin"},
+		manager: {ID: manager, Role: "manager"},
+		member:  {ID: member, Role: "member"},
+	}}
+	svc := NewTeamService(repo, users)
+	ctx := context.Background()
+
+	for _, creator := range []uuid.UUID{admin, manager} {
+		team, err := svc.CreateTeam(ctx, models.CreateTeamRequest{TeamName: "Ops"}, creator)
+		if err != nil {
+			t.Fatalf("Expected %s to create the team, got %v", users.users[creator].Role, err)
+		}
+		if team.OwnerID != creator || repo.roles[creator] != "manager" {
+			t.Errorf("Expected the creator to own and manage the team, got owner %s", team.OwnerID)
+		}
+	}
+
+	_, err := svc.CreateTeam(ctx, models.CreateTeamRequest{TeamName: "Ops"}, member)
+	assertKind(t, err, apperror.KindForbidden)
+}
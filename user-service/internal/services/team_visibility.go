@@ -0,0 +1,42 @@
+package services
+
+import (
+	"fmt"
+	"shared/pkg/apperror"
+	"strings"
+	"user-service/internal/models"
+)
+
+const (
+	defaultTeamPageSize = 50
+	maxTeamPageSize     = 100
+)
+
+// teamVisibility decides which teams the user sees in listings. Admins see
+// every team of their organisation. Everyone else, managers included, sees
+// the teams they belong to and the sub-teams of those they manage, which are
+// the teams whose rules apply to them.
+func teamVisibility(user *models.User) models.TeamVisibility {
+	return models.TeamVisibility{UserID: user.ID, All: user.Role == "admin"}
+}
+
+// normalizeTeamListQuery validates the query and fills in the page size.
+func normalizeTeamListQuery(q models.TeamListQuery) (models.TeamListQuery, error) {
+	verr := map[string]string{}
+
+	q.Search = strings.TrimSpace(q.Search)
+	if q.Role != "" && q.Role != "manager" && q.Role != "member" {
+		verr["role"] = "must be manager or member"
+	}
+	switch {
+	case q.Limit == 0:
+		q.Limit = defaultTeamPageSize
+	case q.Limit < 0 || q.Limit > maxTeamPageSize:
+		verr["limit"] = fmt.Sprintf("must be between 1 and %d", maxTeamPageSize)
+	}
+
+	if len(verr) > 0 {
+		return q, apperror.Validation("invalid team query", verr)
+	}
+	return q, nil
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"shared/pkg/apperror"
+	"shared/pkg/listing"
+	"testing"
+	"user-service/internal/models"
+	"user-service/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// listingTeamRepo records the listing it is asked for and returns its teams,
+// cursor and error.
+type listingTeamRepo struct {
+	repository.TeamRepository
+	visibility models.TeamVisibility
+	query      models.TeamListQuery
+	teams      []*models.Team
+	next       *listing.Cursor
+	err        error
+}
+
+func (r *listingTeamRepo) FindTeams(_ context.Context, v models.TeamVisibility, q models.TeamListQuery) ([]*models.Team, *listing.Cursor, error) {
+	r.visibility, r.query = v, q
+	return r.teams, r.next, r.err
+}
+
+func (r *listingTeamRepo) FindByID(context.Context, uuid.UUID) (*models.Team, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestTeamService_ListingFollowsVisibilityPolicy(t *testing.T) {
+	admin, manager := uuid.New(), uuid.New()
+	repo := &listingTeamRepo{}
+	users := &memoryUserRepo{users: map[uuid.UUID]*models.User{
+		admin:   {ID: admin, Role: "admin"},
+		manager: {ID: manager, Role: "manager"},
+	}}
+	svc := NewTeamService(repo, users)
+	ctx := context.Background()
+
+	teams, _, err := svc.GetAllTeams(ctx, manager, models.TeamListQuery{Search: "  ops ", Role: "manager"})
+	if err != nil {
+		t.Fatalf("Expected the listing to succeed, got %v", err)
+	}
+	if repo.visibility.All || repo.visibility.UserID != manager {
+		t.Errorf("Expected managers to only see their own teams, got %+v", repo.visibility)
+	}
+	if repo.query.Search != "ops" || repo.query.Limit != defaultTeamPageSize {
+		t.Errorf("Expected a trimmed search and the default page size, got %+v", repo.query)
+	}
+	if teams == nil {
+		t.Error("Expected an empty page to list no teams rather than null")
+	}
+
+	if _, _, err := svc.GetAllTeams(ctx, admin, models.TeamListQuery{}); err != nil {
+		t.Fatalf("Expected the listing to succeed, got %v", err)
+	}
+	if !repo.visibility.All {
+		t.Error("Expected admins to see every team")
+	}
+
+	_, _, err = svc.GetAllTeams(ctx, manager, models.TeamListQuery{Role: "owner", Limit: maxTeamPageSize + 1})
+	assertKind(t, err, apperror.KindValidation)
+}
+
+func TestTeamService_ListingMapsLoadedMembers(t *testing.T) {
+	manager, member := uuid.New(), uuid.New()
+	team := &models.Team{ID: uuid.New(), TeamName: "Ops", OwnerID: manager, TeamMembers: []models.TeamMember{
+		{UserID: manager, Role: "manager", User: models.User{ID: manager, Username: "jane"}},
+		{UserID: member, Role: "member", User: models.User{ID: member, Username: "bob"}},
+	}}
+	next := &listing.Cursor{Value: team.TeamName, ID: team.ID}
+	repo := &listingTeamRepo{teams: []*models.Team{team}, next: next}
+	users := &memoryUserRepo{users: map[uuid.UUID]*models.User{manager: {ID: manager, Role: "manager"}}}
+	svc := NewTeamService(repo, users)
+
+	teams, cursor, err := svc.GetAllTeams(context.Background(), manager, models.TeamListQuery{})
+	if err != nil {
+		t.Fatalf("Expected the listing to succeed, got %v", err)
+	}
+	if len(teams) != 1 || len(teams[0].Managers) != 1 || teams[0].Members[0].UserName != "bob" {
+		t.Errorf("Expected the team with its manager and member, got %+v", teams)
+	}
+	if cursor != next {
+		t.Errorf("Expected the repository's cursor, got %+v", cursor)
+	}
+
+	repo.err = errors.New("connection reset")
+	if _, _, err := svc.GetAllTeams(context.Background(), manager, models.TeamListQuery{}); !errors.Is(err, repo.err) {
+		t.Errorf("Expected the repository error, got %v", err)
+	}
+}
@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"shared/pkg/listing"
+	"slices"
 	"time"
 	"user-service/internal/kafka"
 	"user-service/internal/models"
@@ -41,9 +43,12 @@ func (s *TeamServiceWithEvents) CreateTeam(ctx context.Context, req models.Creat
 		TeamName:    &teamResponse.TeamName,
 		Timestamp:   time.Now(),
 	}
+	if teamResponse.ParentID != nil {
+		event.ParentTeamID = stringPtr(teamResponse.ParentID.String())
+	}
 
 	if err := s.publishEvent(ctx, event); err != nil {
-		log.Error.Printf("Failed to publish TEAM_CREATED event: %v", err)
+		log.Error(ctx, "failed to publish team activity event", "event_type", kafka.EventTypeTeamCreated, "error", err)
 		// Don't fail the operation if event publishing fails
 	}
 
@@ -57,7 +62,7 @@ func (s *TeamServiceWithEvents) CreateTeam(ctx context.Context, req models.Creat
 			Timestamp:    time.Now(),
 		}
 		if err := s.publishEvent(ctx, managerEvent); err != nil {
-			log.Error.Printf("Failed to publish MANAGER_ADDED event: %v", err)
+			log.Error(ctx, "failed to publish team activity event", "event_type", kafka.EventTypeManagerAdded, "error", err)
 		}
 	}
 
@@ -71,7 +76,7 @@ func (s *TeamServiceWithEvents) CreateTeam(ctx context.Context, req models.Creat
 			Timestamp:    time.Now(),
 		}
 		if err := s.publishEvent(ctx, memberEvent); err != nil {
-			log.Error.Printf("Failed to publish MEMBER_ADDED event: %v", err)
+			log.Error(ctx, "failed to publish team activity event", "event_type", kafka.EventTypeMemberAdded, "error", err)
 		}
 	}
 
@@ -99,7 +104,7 @@ func (s *TeamServiceWithEvents) AddMember(ctx context.Context, teamID uuid.UUID,
 	}
 
 	if err := s.publishEvent(ctx, event); err != nil {
-		log.Error.Printf("Failed to publish MEMBER_ADDED event: %v", err)
+		log.Error(ctx, "failed to publish team activity event", "event_type", kafka.EventTypeMemberAdded, "error", err)
 		// Don't fail the operation if event publishing fails
 	}
 
@@ -123,7 +128,7 @@ func (s *TeamServiceWithEvents) RemoveMember(ctx context.Context, teamID uuid.UU
 	}
 
 	if err := s.publishEvent(ctx, event); err != nil {
-		log.Error.Printf("Failed to publish MEMBER_REMOVED event: %v", err)
+		log.Error(ctx, "failed to publish team activity event", "event_type", kafka.EventTypeMemberRemoved, "error", err)
 		// Don't fail the operation if event publishing fails
 	}
 
@@ -147,7 +152,7 @@ func (s *TeamServiceWithEvents) AddManager(ctx context.Context, teamID uuid.UUID
 	}
 
 	if err := s.publishEvent(ctx, event); err != nil {
-		log.Error.Printf("Failed to publish MANAGER_ADDED event: %v", err)
+		log.Error(ctx, "failed to publish team activity event", "event_type", kafka.EventTypeManagerAdded, "error", err)
 		// Don't fail the operation if event publishing fails
 	}
 
@@ -171,15 +176,173 @@ func (s *TeamServiceWithEvents) RemoveManager(ctx context.Context, teamID uuid.U
 	}
 
 	if err := s.publishEvent(ctx, event); err != nil {
-		log.Error.Printf("Failed to publish MANAGER_REMOVED event: %v", err)
+		log.Error(ctx, "failed to publish team activity event", "event_type", kafka.EventTypeManagerRemoved, "error", err)
 		// Don't fail the operation if event publishing fails
 	}
 
 	return nil
 }
 
-func (s *TeamServiceWithEvents) GetAllTeams(ctx context.Context, requestorID uuid.UUID) ([]*models.TeamResponse, error) {
-	return s.baseService.GetAllTeams(ctx, requestorID)
+func (s *TeamServiceWithEvents) TransferOwnership(ctx context.Context, teamID uuid.UUID, newOwnerID uuid.UUID, requestorID uuid.UUID) error {
+	if err := s.baseService.TransferOwnership(ctx, teamID, newOwnerID, requestorID); err != nil {
+		return err
+	}
+	s.publishTeamEvent(ctx, kafka.EventTypeOwnershipTransferred, teamID, requestorID, func(e *kafka.TeamActivityEvent) {
+		e.TargetUserID = stringPtr(newOwnerID.String())
+	})
+	return nil
+}
+
+// AdminTransferOwnership publishes MANAGER_ADDED as well when the new owner
+// had to be made a manager first. Both events are performed by the operator.
+func (s *TeamServiceWithEvents) AdminTransferOwnership(ctx context.Context, teamID uuid.UUID, newOwnerID uuid.UUID) error {
+	before, err := s.baseService.GetTeamByID(ctx, teamID)
+	if err != nil {
+		return err
+	}
+	if err := s.baseService.AdminTransferOwnership(ctx, teamID, newOwnerID); err != nil {
+		return err
+	}
+
+	eventTypes := []string{kafka.EventTypeOwnershipTransferred}
+	if !slices.ContainsFunc(before.Managers, func(m models.TeamMemberResponse) bool { return m.UserID == newOwnerID }) {
+		eventTypes = []string{kafka.EventTypeManagerAdded, kafka.EventTypeOwnershipTransferred}
+	}
+	for _, eventType := range eventTypes {
+		event := kafka.TeamActivityEvent{
+			EventType:    eventType,
+			TeamID:       teamID.String(),
+			PerformedBy:  kafka.PerformedByOperator,
+			TargetUserID: stringPtr(newOwnerID.String()),
+			Timestamp:    time.Now(),
+		}
+		if err := s.publishEvent(ctx, event); err != nil {
+			log.Error(ctx, "failed to publish team activity event", "event_type", eventType, "error", err)
+		}
+	}
+	return nil
+}
+
+func (s *TeamServiceWithEvents) GetAllTeams(ctx context.Context, requestorID uuid.UUID, q models.TeamListQuery) ([]*models.TeamResponse, *listing.Cursor, error) {
+	return s.baseService.GetAllTeams(ctx, requestorID, q)
+}
+
+func (s *TeamServiceWithEvents) UpdateTeam(ctx context.Context, teamID uuid.UUID, req models.UpdateTeamRequest, requestorID uuid.UUID) (*models.TeamResponse, error) {
+	teamResponse, err := s.baseService.UpdateTeam(ctx, teamID, req, requestorID)
+	if err != nil {
+		return nil, err
+	}
+	s.publishTeamEvent(ctx, kafka.EventTypeTeamUpdated, teamID, requestorID, func(e *kafka.TeamActivityEvent) {
+		e.TeamName = &teamResponse.TeamName
+	})
+	return teamResponse, nil
+}
+
+func (s *TeamServiceWithEvents) ArchiveTeam(ctx context.Context, teamID uuid.UUID, requestorID uuid.UUID) error {
+	if err := s.baseService.ArchiveTeam(ctx, teamID, requestorID); err != nil {
+		return err
+	}
+	s.publishTeamEvent(ctx, kafka.EventTypeTeamArchived, teamID, requestorID)
+	return nil
+}
+
+func (s *TeamServiceWithEvents) UnarchiveTeam(ctx context.Context, teamID uuid.UUID, requestorID uuid.UUID) error {
+	if err := s.baseService.UnarchiveTeam(ctx, teamID, requestorID); err != nil {
+		return err
+	}
+	s.publishTeamEvent(ctx, kafka.EventTypeTeamUnarchived, teamID, requestorID)
+	return nil
+}
+
+func (s *TeamServiceWithEvents) DeleteTeam(ctx context.Context, teamID uuid.UUID, requestorID uuid.UUID) error {
+	if err := s.baseService.DeleteTeam(ctx, teamID, requestorID); err != nil {
+		return err
+	}
+	s.publishTeamEvent(ctx, kafka.EventTypeTeamDeleted, teamID, requestorID)
+	return nil
+}
+
+func (s *TeamServiceWithEvents) BatchMembership(ctx context.Context, teamID uuid.UUID, req models.BatchMembershipRequest, requestorID uuid.UUID) (*models.BatchMembershipResponse, error) {
+	resp, err := s.baseService.BatchMembership(ctx, teamID, req, requestorID)
+	if err != nil || !resp.Applied {
+		return resp, err
+	}
+
+	// Publish one event per applied operation, as the single-user methods do
+	for _, result := range resp.Results {
+		event := kafka.TeamActivityEvent{
+			EventType:    membershipEventType(result),
+			TeamID:       teamID.String(),
+			PerformedBy:  requestorID.String(),
+			TargetUserID: stringPtr(result.UserID),
+			Timestamp:    time.Now(),
+		}
+		if result.Op == models.MembershipSetRole {
+			event.Role = stringPtr(result.Role)
+		}
+
+		if err := s.publishEvent(ctx, event); err != nil {
+			log.Error(ctx, "failed to publish team activity event", "event_type", event.EventType, "error", err)
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *TeamServiceWithEvents) SetParentTeam(ctx context.Context, teamID uuid.UUID, parentID *uuid.UUID, requestorID uuid.UUID) (*models.TeamResponse, error) {
+	teamResponse, err := s.baseService.SetParentTeam(ctx, teamID, parentID, requestorID)
+	if err != nil {
+		return nil, err
+	}
+	// No parent means the team is now top-level
+	s.publishTeamEvent(ctx, kafka.EventTypeTeamMoved, teamID, requestorID, func(e *kafka.TeamActivityEvent) {
+		if parentID != nil {
+			e.ParentTeamID = stringPtr(parentID.String())
+		}
+	})
+	return teamResponse, nil
+}
+
+func (s *TeamServiceWithEvents) GetAncestors(ctx context.Context, teamID uuid.UUID) ([]*models.TeamNodeResponse, error) {
+	return s.baseService.GetAncestors(ctx, teamID)
+}
+
+func (s *TeamServiceWithEvents) GetDescendants(ctx context.Context, teamID uuid.UUID) ([]*models.TeamNodeResponse, error) {
+	return s.baseService.GetDescendants(ctx, teamID)
+}
+
+// membershipEventType maps an applied batch operation to its event type.
+func membershipEventType(result models.MembershipResult) string {
+	switch {
+	case result.Op == models.MembershipSetRole:
+		return kafka.EventTypeMemberRoleChanged
+	case result.Op == models.MembershipAdd && result.Role == "manager":
+		return kafka.EventTypeManagerAdded
+	case result.Op == models.MembershipAdd:
+		return kafka.EventTypeMemberAdded
+	case result.Role == "manager":
+		return kafka.EventTypeManagerRemoved
+	default:
+		return kafka.EventTypeMemberRemoved
+	}
+}
+
+// publishTeamEvent publishes an event about the team, logging rather than
+// returning a failure. The optional funcs fill in the event's details.
+func (s *TeamServiceWithEvents) publishTeamEvent(ctx context.Context, eventType string, teamID, requestorID uuid.UUID, details ...func(*kafka.TeamActivityEvent)) {
+	event := kafka.TeamActivityEvent{
+		EventType:   eventType,
+		TeamID:      teamID.String(),
+		PerformedBy: requestorID.String(),
+		Timestamp:   time.Now(),
+	}
+	for _, detail := range details {
+		detail(&event)
+	}
+
+	if err := s.publishEvent(ctx, event); err != nil {
+		log.Error(ctx, "failed to publish team activity event", "event_type", eventType, "error", err)
+	}
 }
 
 // publishEvent publishes a team activity event to Kafka
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"user-service/internal/kafka"
+	"user-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// recordingProducer keeps the events it is asked to publish.
+type recordingProducer struct {
+	kafka.Producer
+	events []kafka.TeamActivityEvent
+}
+
+func (p *recordingProducer) Publish(_ context.Context, _ string, _ []byte, v any) error {
+	p.events = append(p.events, v.(kafka.TeamActivityEvent))
+	return nil
+}
+
+// succeedingTeamService accepts every change it is asked to make.
+type succeedingTeamService struct {
+	TeamService
+}
+
+func (succeedingTeamService) UpdateTeam(_ context.Context, teamID uuid.UUID, req models.UpdateTeamRequest, _ uuid.UUID) (*models.TeamResponse, error) {
+	return &models.TeamResponse{ID: teamID, TeamName: req.TeamName}, nil
+}
+
+func (succeedingTeamService) TransferOwnership(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) error {
+	return nil
+}
+
+func (succeedingTeamService) SetParentTeam(_ context.Context, teamID uuid.UUID, _ *uuid.UUID, _ uuid.UUID) (*models.TeamResponse, error) {
+	return &models.TeamResponse{ID: teamID}, nil
+}
+
+func TestTeamServiceWithEvents_EventDetails(t *testing.T) {
+	producer := &recordingProducer{}
+	svc := NewTeamServiceWithEvents(succeedingTeamService{}, producer, "team.activity")
+	teamID, requestor, target := uuid.New(), uuid.New(), uuid.New()
+	ctx := context.Background()
+
+	if _, err := svc.UpdateTeam(ctx, teamID, models.UpdateTeamRequest{TeamName: "Ops"}, requestor); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.TransferOwnership(ctx, teamID, target, requestor); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SetParentTeam(ctx, teamID, &target, requestor); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.SetParentTeam(ctx, teamID, nil, requestor); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(producer.events) != 4 {
+		t.Fatalf("Expected 4 events, got %d", len(producer.events))
+	}
+	for _, e := range producer.events {
+		if e.TeamID != teamID.String() || e.PerformedBy != requestor.String() || e.Timestamp.IsZero() {
+			t.Errorf("Expected %s event for the team by the requestor, got %+v", e.EventType, e)
+		}
+	}
+	updated, transferred, moved, toTop := producer.events[0], producer.events[1], producer.events[2], producer.events[3]
+	if updated.EventType != kafka.EventTypeTeamUpdated || updated.TeamName == nil || *updated.TeamName != "Ops" {
+		t.Errorf("Expected TEAM_UPDATED with the new name, got %+v", updated)
+	}
+	if transferred.EventType != kafka.EventTypeOwnershipTransferred || transferred.TargetUserID == nil || *transferred.TargetUserID != target.String() {
+		t.Errorf("Expected OWNERSHIP_TRANSFERRED to the new owner, got %+v", transferred)
+	}
+	if moved.EventType != kafka.EventTypeTeamMoved || moved.ParentTeamID == nil || *moved.ParentTeamID != target.String() {
+		t.Errorf("Expected TEAM_MOVED under the parent, got %+v", moved)
+	}
+	if toTop.ParentTeamID != nil {
+		t.Errorf("Expected a top-level TEAM_MOVED without a parent, got %+v", toTop)
+	}
+}
@@ -3,11 +3,15 @@ package services
 import (
 	"context"
 	"errors"
+	"shared/pkg/apperror"
+	"shared/pkg/tenant"
+	"time"
 	"user-service/internal/models"
 	"user-service/internal/repository"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type UserService interface {
@@ -15,6 +19,9 @@ type UserService interface {
 	Login(ctx context.Context, email, password string) (*models.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 	FetchUsers(ctx context.Context) ([]*models.User, error)
+	SetRole(ctx context.Context, userID uuid.UUID, role string) (*models.User, error)
+	SetDisabled(ctx context.Context, userID uuid.UUID, disabled bool) (*models.User, error)
+	CheckActive(ctx context.Context, userID uuid.UUID) error
 }
 
 type UserServiceImpl struct {
@@ -25,13 +32,19 @@ func NewUserService(repo repository.UserRepository) UserService {
 	return &UserServiceImpl{Repo: repo}
 }
 
+// CreateUser creates the user in the organisation of ctx.
 func (s *UserServiceImpl) CreateUser(ctx context.Context, username, email, password, role string) (*models.User, error) {
-	if role != "manager" && role != "member" {
-		return nil, errors.New("invalid role")
+	if err := validateRole(role); err != nil {
+		return nil, err
+	}
+	orgID, ok := tenant.OrgID(ctx)
+	if !ok {
+		return nil, apperror.BadRequest("an organisation is required to create users")
 	}
 	hash, _ := bcrypt.GenerateFromPassword([]byte(password), 12)
 	user := &models.User{
 		ID:           uuid.New(),
+		OrgID:        orgID,
 		Username:     username,
 		Email:        email,
 		PasswordHash: string(hash),
@@ -40,11 +53,19 @@ func (s *UserServiceImpl) CreateUser(ctx context.Context, username, email, passw
 	return s.Repo.Create(ctx, user)
 }
 
+// Login finds the user by email in any organisation, as emails are unique
+// across them; the token issued for the user carries the user's own.
 func (s *UserServiceImpl) Login(ctx context.Context, email, password string) (*models.User, error) {
-	user, err := s.Repo.Login(ctx, email, password)
+	user, err := s.Repo.Login(tenant.WithoutOrg(ctx), email, password)
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, repository.ErrInvalidCredentials) {
+		return nil, apperror.Unauthenticated("invalid credentials")
+	}
 	if err != nil {
 		return nil, err
 	}
+	if user.DisabledAt != nil {
+		return nil, apperror.Forbidden("account is disabled")
+	}
 	return user, nil
 }
 
@@ -55,3 +76,53 @@ func (s *UserServiceImpl) GetUserByEmail(ctx context.Context, email string) (*mo
 func (s *UserServiceImpl) FetchUsers(ctx context.Context) ([]*models.User, error) {
 	return s.Repo.FetchAll(ctx)
 }
+
+// SetRole changes the user's role. Unlike CreateUser it also grants "admin",
+// which sees every team of the organisation; only operators call it.
+func (s *UserServiceImpl) SetRole(ctx context.Context, userID uuid.UUID, role string) (*models.User, error) {
+	if role != "admin" {
+		if err := validateRole(role); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.Repo.Update(ctx, userID, map[string]any{"role": role}); err != nil {
+		return nil, apperror.Lookup(err, "user")
+	}
+	return s.Repo.FindByID(ctx, userID)
+}
+
+// SetDisabled disables or re-enables the user's account. Tokens issued
+// before the user was disabled are rejected by CheckActive.
+func (s *UserServiceImpl) SetDisabled(ctx context.Context, userID uuid.UUID, disabled bool) (*models.User, error) {
+	var disabledAt any // NULL re-enables
+	if disabled {
+		disabledAt = time.Now()
+	}
+	if err := s.Repo.Update(ctx, userID, map[string]any{"disabled_at": disabledAt}); err != nil {
+		return nil, apperror.Lookup(err, "user")
+	}
+	return s.Repo.FindByID(ctx, userID)
+}
+
+// CheckActive rejects users that were deleted or disabled after their token
+// was issued.
+func (s *UserServiceImpl) CheckActive(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.Repo.FindByID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apperror.Unauthenticated("account no longer exists")
+	}
+	if err != nil {
+		return err
+	}
+	if user.DisabledAt != nil {
+		return apperror.Forbidden("account is disabled")
+	}
+	return nil
+}
+
+func validateRole(role string) error {
+	if role != "manager" && role != "member" {
+		return apperror.Validation("invalid user", map[string]string{"role": "must be manager or member"})
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package services
+
+import (
+	"context"
+	"shared/pkg/apperror"
+	"testing"
+	"time"
+	"user-service/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestUserService_CheckActiveRejectsDisabledAndDeletedUsers(t *testing.T) {
+	active, disabled := uuid.New(), uuid.New()
+	disabledAt := time.Now()
+	svc := NewUserService(&memoryUserRepo{users: map[uuid.UUID]*models.User{
+		active:   {ID: active},
+		disabled: {ID: disabled, DisabledAt: &disabledAt},
+	}})
+	ctx := context.Background()
+
+	if err := svc.CheckActive(ctx, active); err != nil {
+		t.Errorf("Expected an active user to pass, got %v", err)
+	}
+	assertKind(t, svc.CheckActive(ctx, disabled), apperror.KindForbidden)
+	assertKind(t, svc.CheckActive(ctx, uuid.New()), apperror.KindUnauthenticated)
+}